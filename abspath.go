@@ -3,8 +3,9 @@ package abspath
 import (
 	"fmt"
 	"os"
-	"os/user"
 	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 // AbsPath is a type to represent absolute path.  Please do not make an instance of this struct directly.
@@ -34,9 +35,46 @@ func New(from string) (AbsPath, error) {
 	if !filepath.IsAbs(from) {
 		return AbsPath{""}, &NotAbsolutePathError{from}
 	}
+	if isClean(from) {
+		return AbsPath{from}, nil
+	}
 	return AbsPath{filepath.Clean(from)}, nil
 }
 
+// isClean returns whether the absolute path is already clean, in other words, filepath.Clean() returns the same
+// string.  It is much faster than filepath.Clean() since it only scans the string without building a new one.  When it
+// returns false, the path may still be clean.
+func isClean(p string) bool {
+	p = p[len(filepath.VolumeName(p)):]
+	if p == "" || p[0] != filepath.Separator {
+		return false
+	}
+	if len(p) == 1 {
+		return true
+	}
+	if p[len(p)-1] == filepath.Separator {
+		return false
+	}
+	start := 1
+	for i := 1; i <= len(p); i++ {
+		if i < len(p) {
+			c := p[i]
+			if c == '/' && filepath.Separator != '/' {
+				return false
+			}
+			if c != filepath.Separator {
+				continue
+			}
+		}
+		switch p[start:i] {
+		case "", ".", "..":
+			return false
+		}
+		start = i + 1
+	}
+	return true
+}
+
 // ExpandFrom creates AbsPath struct with expanding the parameter.  Parameter can be a full-path, relative path or a path starting with '~'
 // where '~' means a home directory.  When parameter is a relative path, it will be joined with a path to current directory automatically.
 //
@@ -54,18 +92,18 @@ func ExpandFrom(specified string) (AbsPath, error) {
 	}
 
 	if specified[0] == '~' {
-		u, err := user.Current()
+		h, err := homeDir()
 		if err != nil {
 			return AbsPath{""}, err
 		}
-		return AbsPath{filepath.Join(u.HomeDir, specified[1:])}, nil
+		return AbsPath{filepath.Join(h, specified[1:])}, nil
 	}
 
-	p, err := filepath.Abs(specified)
+	cwd, err := getwd()
 	if err != nil {
 		return AbsPath{""}, err
 	}
-	return AbsPath{p}, nil
+	return AbsPath{filepath.Join(cwd, specified)}, nil
 }
 
 // FromSlash creates AbsPath struct instance from a string separated by slashes.  A parameter must represent an absolute path.
@@ -94,14 +132,16 @@ func ExpandFromSlash(s string) (AbsPath, error) {
 //		panic(err)
 //	}
 func Getwd() (AbsPath, error) {
-	cwd, err := os.Getwd()
+	cwd, err := getwd()
 	if err != nil {
-		return AbsPath{""}, nil
+		return AbsPath{""}, err
 	}
 	return New(cwd)
 }
 
 // HomeDir creates AbsPath instance for the home directory.  If home directory cannot be obtained or is not an absolute path, it will return an error.
+// On Plan 9, the home directory is obtained from $home environment variable.  On GOOS=js, it is obtained from $HOME
+// environment variable and an error which satisfies errors.Is(err, ErrUnsupported) is returned when it is not set.
 //
 // Example:
 //	home, err := abspath.HomeDir()
@@ -110,31 +150,84 @@ func Getwd() (AbsPath, error) {
 //	}
 //	println(home.String())
 func HomeDir() (AbsPath, error) {
-	u, err := user.Current()
+	h, err := homeDir()
 	if err != nil {
 		return AbsPath{""}, err
 	}
-	return New(u.HomeDir)
+	return New(h)
 }
 
-// Base is equivalent to filepath.Base().
+// tidy returns the underlying string without redundant separators, '.' components and a trailing separator.  The
+// underlying string is not clean only when the path was created with NoClean() or KeepTrailingSeparator() option.
+// Unlike filepath.Clean(), '..' components are kept since they must be resolved physically for such paths.
+func (a AbsPath) tidy() string {
+	p := a.underlying
+	if filepath.Clean(p) == p {
+		return p
+	}
+	vol := filepath.VolumeName(p)
+	ss := []string{}
+	for _, s := range strings.FieldsFunc(p[len(vol):], isSeparatorRune) {
+		if s != "." {
+			ss = append(ss, s)
+		}
+	}
+	return vol + string(filepath.Separator) + strings.Join(ss, string(filepath.Separator))
+}
+
+// lastSeparator returns the path tidied by tidy(), the index of the last path separator in it and the length of its
+// volume name.  The tidied path has no trailing separator except for the root.
+func (a AbsPath) lastSeparator() (string, int, int) {
+	p := a.tidy()
+	vol := len(filepath.VolumeName(p))
+	i := len(p) - 1
+	for i >= vol && !os.IsPathSeparator(p[i]) {
+		i--
+	}
+	return p, i, vol
+}
+
+// Base is equivalent to filepath.Base().  It only slices the underlying string.  For a path created with NoClean() or
+// KeepTrailingSeparator() option, redundant separators, '.' components and a trailing separator are ignored, and a
+// '..' component is returned as it is.
 //
 // Ref: https://golang.org/pkg/path/filepath/#Base
 func (a AbsPath) Base() AbsPath {
-	return AbsPath{filepath.Base(a.underlying)}
+	p, i, vol := a.lastSeparator()
+	if i < vol {
+		return AbsPath{filepath.Base(p)}
+	}
+	if i == len(p)-1 {
+		return AbsPath{string(filepath.Separator)}
+	}
+	return AbsPath{p[i+1:]}
 }
 
-// Dir is equivalent to filepath.Dir().
+// Dir is equivalent to filepath.Dir().  It only slices the underlying string without cleaning it again.  For a path
+// created with NoClean() or KeepTrailingSeparator() option, redundant separators, '.' components and a trailing
+// separator are ignored, and '..' components are kept.  Note that filepath.Dir() does not ignore a trailing separator.
 //
 // Ref: https://golang.org/pkg/path/filepath/#Dir
 func (a AbsPath) Dir() AbsPath {
-	return AbsPath{filepath.Dir(a.underlying)}
+	p, i, vol := a.lastSeparator()
+	if i < vol {
+		return AbsPath{filepath.Dir(p)}
+	}
+	if i == vol {
+		return AbsPath{p[:vol+1]}
+	}
+	return AbsPath{p[:i]}
 }
 
-// EvalSymlinks is equivalent to filepath.EvalSymlinks().
+// EvalSymlinks is equivalent to filepath.EvalSymlinks().  When a filesystem other than the OS filesystem is set by
+// SetFS(), symbolic links are resolved via the filesystem as Resolve() does.
 //
 // Ref: https://golang.org/pkg/path/filepath/#EvalSymlinks
-func (a AbsPath) EvalSymlinks() (AbsPath, error) {
+func (a AbsPath) EvalSymlinks() (_ AbsPath, err error) {
+	defer annotate(&err, "evaluate symbolic links of", a)
+	if !usesOSFS() {
+		return a.Resolve(&ResolveOptions{MustExist: true})
+	}
 	s, err := filepath.EvalSymlinks(a.underlying)
 	if err != nil {
 		return AbsPath{""}, err
@@ -146,31 +239,107 @@ func (a AbsPath) EvalSymlinks() (AbsPath, error) {
 	return AbsPath{s}, nil
 }
 
-// Ext is equivalent to filepath.Ext().
+// Ext is equivalent to filepath.Ext().  It only slices the underlying string.  For a path created with NoClean() or
+// KeepTrailingSeparator() option, the extension of Base() is returned.
 //
 // Ref: https://golang.org/pkg/path/filepath/#Ext
 func (a AbsPath) Ext() string {
-	return filepath.Ext(a.underlying)
+	p := a.tidy()
+	for i := len(p) - 1; i >= 0 && !os.IsPathSeparator(p[i]); i-- {
+		if p[i] == '.' {
+			return p[i:]
+		}
+	}
+	return ""
 }
 
 // HasPrefix is equivalent to filepath.HasPrefix().
 //
 // Ref: https://golang.org/pkg/path/filepath/#HasPrefix
+//
+// Deprecated: filepath.HasPrefix() does not respect path boundaries ('/foo' is a prefix of '/foobar').  Use
+// ContainsPath() instead.
 func (a AbsPath) HasPrefix(prefix string) bool {
 	return filepath.HasPrefix(a.underlying, prefix)
 }
 
-// Join is equivalent to filepath.Join().  Parameters are joined into the absolute path.
+// Join is equivalent to filepath.Join().  Parameters are joined into the absolute path.  Since the absolute path is
+// already clean, only the joined parameters are cleaned and the result is built in one pass.
 //
 // Ref: https://golang.org/pkg/path/filepath/#Join
 func (a AbsPath) Join(elem ...string) AbsPath {
-	switch len(elem) {
-	case 0:
+	if a.underlying == "" {
+		return AbsPath{filepath.Join(append([]string{a.underlying}, elem...)...)}
+	}
+	size := len(a.underlying)
+	for _, e := range elem {
+		if hasParentComponent(e) {
+			// '..' may remove components of the absolute path. Fallback to cleaning the whole path
+			return AbsPath{filepath.Join(append([]string{a.underlying}, elem...)...)}
+		}
+		size += len(e) + 1
+	}
+
+	var b strings.Builder
+	for _, e := range elem {
+		appendComponents(&b, a.underlying, size, e)
+	}
+	if b.Len() == 0 {
 		return a
-	case 1:
-		return AbsPath{filepath.Join(a.underlying, elem[0])}
-	default:
-		return AbsPath{filepath.Join(a.underlying, filepath.Join(elem...))}
+	}
+	return AbsPath{b.String()}
+}
+
+// JoinOne is the same as Join() with one parameter, but it is faster since it does not need a variadic parameter.
+//
+// Example:
+//	a, _ := abspath.New("/path/to")
+//	a.JoinOne("file.txt") // => "/path/to/file.txt"
+func (a AbsPath) JoinOne(elem string) AbsPath {
+	if a.underlying == "" || hasParentComponent(elem) {
+		return AbsPath{filepath.Join(a.underlying, elem)}
+	}
+	var b strings.Builder
+	appendComponents(&b, a.underlying, len(a.underlying)+len(elem)+1, elem)
+	if b.Len() == 0 {
+		return a
+	}
+	return AbsPath{b.String()}
+}
+
+// hasParentComponent returns whether the slash or separator separated path contains a '..' component.
+func hasParentComponent(p string) bool {
+	for i := 0; i+1 < len(p); i++ {
+		if p[i] == '.' && p[i+1] == '.' && (i == 0 || os.IsPathSeparator(p[i-1])) && (i+2 == len(p) || os.IsPathSeparator(p[i+2])) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendComponents appends non-empty components of the path except for '.' to the builder.  When the builder is
+// empty, base is written first with the capacity of size.  Nothing is written when the path has no component to
+// append.
+func appendComponents(b *strings.Builder, base string, size int, p string) {
+	start := 0
+	for i := 0; i <= len(p); i++ {
+		if i < len(p) && !os.IsPathSeparator(p[i]) {
+			continue
+		}
+		c := p[start:i]
+		start = i + 1
+		if c == "" || c == "." {
+			continue
+		}
+		if b.Len() == 0 {
+			b.Grow(size)
+			b.WriteString(base)
+		}
+		s := b.String()
+		if !os.IsPathSeparator(s[len(s)-1]) {
+			b.WriteByte(filepath.Separator)
+		}
+		b.WriteString(c)
 	}
 }
 
@@ -181,6 +350,17 @@ func (a AbsPath) Match(pattern string) (bool, error) {
 	return filepath.Match(pattern, a.underlying)
 }
 
+// MatchRegexp returns whether the regular expression matches the slash-separated path.  Since separators are
+// normalized to '/', the same regular expression can be used on all platforms.  It is useful for patterns which glob
+// cannot express.
+//
+// Example:
+//	a, _ := abspath.New("/var/log/2024-05-01/app.log")
+//	a.MatchRegexp(regexp.MustCompile(`/\d{4}-\d{2}-\d{2}/`)) // => true
+func (a AbsPath) MatchRegexp(re *regexp.Regexp) bool {
+	return re.MatchString(filepath.ToSlash(a.underlying))
+}
+
 // Rel is equivalent to filepath.Rel().  It returns a string of relative path to the absolute path.
 //
 // Ref: https://golang.org/pkg/path/filepath/#Rel
@@ -214,7 +394,7 @@ func (a AbsPath) VolumeName() string {
 //
 // Ref: https://golang.org/pkg/path/filepath/#Walk
 func (a AbsPath) Walk(walkFn filepath.WalkFunc) error {
-	return filepath.Walk(a.underlying, walkFn)
+	return a.WalkWithOptions(walkFn, nil)
 }
 
 // String returns an underlying string value.  You can use this method to convert AbsPath value to string.
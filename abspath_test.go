@@ -2,9 +2,11 @@ package abspath
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
+	"regexp"
 	"runtime"
 	"strings"
 	"testing"
@@ -30,6 +32,31 @@ func tc(i, e string) TestCase {
 	return TestCase{filepath.FromSlash(fixAbsPath(i)), filepath.FromSlash(fixAbsPath(e))}
 }
 
+// makeTree creates files in a temporary directory and returns the directory.  Keys of the map are slash-separated
+// relative file paths and values are their contents.  Keys ending with '/' are created as empty directories.
+func makeTree(t *testing.T, files map[string]string) AbsPath {
+	root, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		p := root.Join(filepath.FromSlash(name)).String()
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(p, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
 func TestNew(t *testing.T) {
 	for _, c := range []TestCase{
 		tc("/path/to/file", "/path/to/file"),
@@ -60,6 +87,49 @@ func TestNew(t *testing.T) {
 	}
 }
 
+func TestIsClean(t *testing.T) {
+	for _, s := range []string{
+		"/",
+		"/foo",
+		"/foo/bar",
+		"/foo/bar/",
+		"//foo",
+		"/foo//bar",
+		"/foo/./bar",
+		"/foo/../bar",
+		"/.",
+		"/..",
+		"/foo/.",
+		"/foo/..",
+		"/.foo/..bar/...",
+		"/foo/bar.",
+		"/foo/bar..",
+		"/foo/bar\\baz",
+		"C:\\",
+		"C:\\foo\\bar",
+		"C:\\foo\\..\\bar",
+		"C:\\foo/bar",
+		"C:\\foo\\",
+		"\\\\server\\share\\foo",
+		"\\\\server\\share\\foo\\.",
+	} {
+		if !filepath.IsAbs(s) {
+			continue
+		}
+		want := filepath.Clean(s) == s
+		if isClean(s) && !want {
+			t.Errorf("%q should not be clean", s)
+		}
+		a, err := New(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.String() != filepath.Clean(s) {
+			t.Errorf("New(%q) should be %q but got %q", s, filepath.Clean(s), a)
+		}
+	}
+}
+
 func abs(s string) string {
 	r, err := filepath.Abs(s)
 	if err != nil {
@@ -205,6 +275,43 @@ func TestDir(t *testing.T) {
 	}
 }
 
+func TestBaseDirExtCompatibility(t *testing.T) {
+	for _, s := range []string{
+		"/",
+		"/foo",
+		"/foo/bar",
+		"/foo/bar.poyo",
+		"/foo/bar.tar.gz",
+		"/foo.d/bar",
+		"/.hidden",
+		"/foo/bar.",
+	} {
+		s = filepath.FromSlash(fixAbsPath(s))
+		a, err := New(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, want := a.Base().String(), filepath.Base(s); b != want {
+			t.Errorf("Base() of %q should be %q but got %q", s, want, b)
+		}
+		if d, want := a.Dir().String(), filepath.Dir(s); d != want {
+			t.Errorf("Dir() of %q should be %q but got %q", s, want, d)
+		}
+		if e, want := a.Ext(), filepath.Ext(s); e != want {
+			t.Errorf("Ext() of %q should be %q but got %q", s, want, e)
+		}
+	}
+
+	a, _ := FromSlash(fixAbsPath("/path/to/entry.txt"))
+	if n := testing.AllocsPerRun(100, func() {
+		a.Base()
+		a.Dir()
+		a.Ext()
+	}); n != 0 {
+		t.Errorf("Base(), Dir() and Ext() should not allocate but allocated %v times", n)
+	}
+}
+
 func TestEvalSymlinks(t *testing.T) {
 	if isWindows {
 		t.Skip("Symlink in Git repo is actually not symbolic link on Windows")
@@ -271,6 +378,51 @@ func TestJoin(t *testing.T) {
 	}
 }
 
+func TestJoinCompatibility(t *testing.T) {
+	for _, base := range []string{"/", "/foo", "/foo/bar"} {
+		base = filepath.FromSlash(fixAbsPath(base))
+		a, _ := New(base)
+		for _, elem := range [][]string{
+			{},
+			{""},
+			{"."},
+			{"a"},
+			{"a", "b"},
+			{"a/b", "c"},
+			{"a//b/", "./c/."},
+			{"", "a", ""},
+			{"/a", "/b/"},
+			{".."},
+			{"a", "../.."},
+			{"a/../../b"},
+			{"..a", "b.."},
+			{"...", ".a."},
+		} {
+			want := filepath.Join(append([]string{base}, elem...)...)
+			if have := a.Join(elem...).String(); have != want {
+				t.Errorf("Join(%q, %q) should be %q but got %q", base, elem, want, have)
+			}
+			if len(elem) == 1 {
+				if have := a.JoinOne(elem[0]).String(); have != want {
+					t.Errorf("JoinOne(%q, %q) should be %q but got %q", base, elem[0], want, have)
+				}
+			}
+		}
+	}
+
+	a, _ := FromSlash(fixAbsPath("/path/to"))
+	if n := testing.AllocsPerRun(100, func() {
+		a.JoinOne("file.txt")
+	}); n > 1 {
+		t.Errorf("JoinOne() should allocate only once but allocated %v times", n)
+	}
+	if n := testing.AllocsPerRun(100, func() {
+		a.Join("dir", "file.txt")
+	}); n > 1 {
+		t.Errorf("Join() should allocate only once but allocated %v times", n)
+	}
+}
+
 func TestMatch(t *testing.T) {
 	a, _ := FromSlash(fixAbsPath("/foo/bar"))
 	b, err := a.Match(filepath.FromSlash(fixAbsPath("/*/*")))
@@ -282,6 +434,16 @@ func TestMatch(t *testing.T) {
 	}
 }
 
+func TestMatchRegexp(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/var/log/2024-05-01/app.log"))
+	if !a.MatchRegexp(regexp.MustCompile(`/log/\d{4}-\d{2}-\d{2}/[^/]+$`)) {
+		t.Errorf("%s should match to the regexp with slash separators", a)
+	}
+	if a.MatchRegexp(regexp.MustCompile(`/\d{4}/`)) {
+		t.Errorf("%s should not match to the regexp", a)
+	}
+}
+
 func TestRel(t *testing.T) {
 	a, _ := FromSlash(fixAbsPath("/a"))
 	s, err := a.Rel(filepath.FromSlash(fixAbsPath("/b/c")))
@@ -401,6 +563,52 @@ func BenchmarkBaseMethod(b *testing.B) {
 	})
 }
 
+func BenchmarkNew(b *testing.B) {
+	clean := filepath.FromSlash(fixAbsPath("/path/to/some/entry.txt"))
+	unclean := filepath.FromSlash(fixAbsPath("/path/to/../some/./entry.txt"))
+
+	b.Run("Clean input", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			New(clean)
+		}
+	})
+
+	b.Run("Unclean input", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			New(unclean)
+		}
+	})
+
+	b.Run("Raw Clean() function", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			filepath.Clean(clean)
+		}
+	})
+}
+
+func BenchmarkJoin(b *testing.B) {
+	a, _ := FromSlash(fixAbsPath("/path/to/some"))
+	s := a.String()
+
+	b.Run("Join() method", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			a.Join("dir", "entry.txt")
+		}
+	})
+
+	b.Run("JoinOne() method", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			a.JoinOne("entry.txt")
+		}
+	})
+
+	b.Run("Raw Join() function", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			filepath.Join(s, "dir", "entry.txt")
+		}
+	})
+}
+
 func BenchmarkLongPath(b *testing.B) {
 	p := ""
 	for i := 0; i < 10; i++ {
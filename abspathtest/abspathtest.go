@@ -0,0 +1,152 @@
+// Package abspathtest provides helpers for tests of code using abspath package.  Assertions access the filesystem via
+// abspath.CurrentFS() so that they also work with a fake filesystem set by abspath.SetFS().
+//
+// Example:
+//	func TestSomething(t *testing.T) {
+//		root := abspathtest.TempTree(t, map[string]string{
+//			"src/main.go": "package main",
+//			"out/":        "",
+//		})
+//		// Run the code to test...
+//		abspathtest.RequireFile(t, root.Join("out", "main"))
+//	}
+package abspathtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rhysd/abspath"
+	"github.com/rhysd/abspath/memfs"
+)
+
+// TempTree creates a directory tree in a temporary directory and returns the root of the tree.  Keys of the map are
+// slash-separated paths relative to the root and values are contents of the files.  A key ending with '/' creates a
+// directory and its value is ignored.  Parent directories are created automatically.  The tree is always created on
+// the OS filesystem and removed when the test finishes.  Symbolic links in the path to the temporary directory are
+// resolved so that the returned path can be compared with paths returned from the filesystem.
+func TempTree(t testing.TB, files map[string]string) abspath.AbsPath {
+	t.Helper()
+	d, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := abspath.New(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		p := root.Join(filepath.FromSlash(name)).String()
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(p, 0755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func stat(t testing.TB, p abspath.AbsPath) os.FileInfo {
+	t.Helper()
+	s, err := abspath.CurrentFS().Stat(p.String())
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Fatalf("Path %q does not exist", p)
+		}
+		t.Fatalf("Cannot stat %q: %v", p, err)
+	}
+	return s
+}
+
+// RequireExists fails the test immediately when the path does not exist.
+func RequireExists(t testing.TB, p abspath.AbsPath) {
+	t.Helper()
+	stat(t, p)
+}
+
+// RequireNotExist fails the test immediately when the path exists.
+func RequireNotExist(t testing.TB, p abspath.AbsPath) {
+	t.Helper()
+	_, err := abspath.CurrentFS().Lstat(p.String())
+	if err == nil {
+		t.Fatalf("Path %q should not exist", p)
+	}
+	if !os.IsNotExist(err) {
+		t.Fatalf("Cannot stat %q: %v", p, err)
+	}
+}
+
+// RequireDir fails the test immediately when the path is not a directory.
+func RequireDir(t testing.TB, p abspath.AbsPath) {
+	t.Helper()
+	if s := stat(t, p); !s.IsDir() {
+		t.Fatalf("Path %q should be a directory but its mode is %s", p, s.Mode())
+	}
+}
+
+// RequireFile fails the test immediately when the path is not a regular file.
+func RequireFile(t testing.TB, p abspath.AbsPath) {
+	t.Helper()
+	if s := stat(t, p); !s.Mode().IsRegular() {
+		t.Fatalf("Path %q should be a regular file but its mode is %s", p, s.Mode())
+	}
+}
+
+// RequireContent fails the test immediately when the content of the file at the path is not equal to want.
+func RequireContent(t testing.TB, p abspath.AbsPath, want string) {
+	t.Helper()
+	f, err := abspath.CurrentFS().Open(p.String())
+	if err != nil {
+		t.Fatalf("Cannot open %q: %v", p, err)
+	}
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("Cannot read %q: %v", p, err)
+	}
+	if string(b) != want {
+		t.Fatalf("Content of %q should be %q but got %q", p, want, b)
+	}
+}
+
+// EqualPaths reports a test error and returns false when two lists of paths are not equal.  Paths are compared with
+// abspath.AbsPath.Equal() so that they are compared in case-insensitive on Windows and macOS.
+func EqualPaths(t testing.TB, want, got []abspath.AbsPath) bool {
+	t.Helper()
+	ok := len(want) == len(got)
+	for i := 0; ok && i < len(want); i++ {
+		ok = want[i].Equal(got[i])
+	}
+	if !ok {
+		var b strings.Builder
+		for _, p := range want {
+			b.WriteString("\n  want: " + p.String())
+		}
+		for _, p := range got {
+			b.WriteString("\n  got:  " + p.String())
+		}
+		t.Errorf("Paths are not equal (want %d paths, got %d paths):%s", len(want), len(got), b.String())
+	}
+	return ok
+}
+
+// UseMemFS sets a new in-memory filesystem by abspath.SetFS() and returns it.  The previous filesystem is restored
+// when the test finishes.  Since the filesystem is set for the whole process, tests calling this function must not
+// run in parallel.  To run tests in parallel, share one memfs.FS among them and use distinct directories.
+func UseMemFS(t testing.TB) *memfs.FS {
+	t.Helper()
+	fs := memfs.New()
+	prev := abspath.SetFS(fs)
+	t.Cleanup(func() { abspath.SetFS(prev) })
+	return fs
+}
@@ -0,0 +1,95 @@
+package abspathtest
+
+import (
+	"testing"
+
+	"github.com/rhysd/abspath"
+)
+
+// fakeT records failures instead of failing the test
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.failed = true
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.failed = true
+	// Stop the assertion as t.Fatalf() does
+	panic(t)
+}
+
+func failed(f func(t testing.TB)) (ret bool) {
+	ft := &fakeT{}
+	defer func() {
+		if r := recover(); r != nil && r != ft {
+			panic(r)
+		}
+		ret = ft.failed
+	}()
+	f(ft)
+	return
+}
+
+func TestTempTreeAndRequire(t *testing.T) {
+	root := TempTree(t, map[string]string{
+		"a/b.txt": "hello",
+		"c/":      "",
+	})
+
+	RequireDir(t, root.Join("a"))
+	RequireDir(t, root.Join("c"))
+	RequireFile(t, root.Join("a", "b.txt"))
+	RequireExists(t, root.Join("a", "b.txt"))
+	RequireContent(t, root.Join("a", "b.txt"), "hello")
+	RequireNotExist(t, root.Join("d"))
+
+	for name, f := range map[string]func(t testing.TB){
+		"RequireDir":      func(t testing.TB) { RequireDir(t, root.Join("a", "b.txt")) },
+		"RequireFile":     func(t testing.TB) { RequireFile(t, root.Join("c")) },
+		"RequireExists":   func(t testing.TB) { RequireExists(t, root.Join("d")) },
+		"RequireContent":  func(t testing.TB) { RequireContent(t, root.Join("a", "b.txt"), "bye") },
+		"RequireNotExist": func(t testing.TB) { RequireNotExist(t, root.Join("a")) },
+	} {
+		if !failed(f) {
+			t.Errorf("%s should fail", name)
+		}
+	}
+}
+
+func TestEqualPaths(t *testing.T) {
+	root := TempTree(t, nil)
+	a, b := root.Join("a"), root.Join("b")
+
+	if !EqualPaths(t, []abspath.AbsPath{a, b}, []abspath.AbsPath{a, b}) {
+		t.Error("Same paths should be equal")
+	}
+	for _, got := range [][]abspath.AbsPath{{a}, {b, a}, {a, b, b}} {
+		if !failed(func(t testing.TB) { EqualPaths(t, []abspath.AbsPath{a, b}, got) }) {
+			t.Errorf("%v should not be equal", got)
+		}
+	}
+}
+
+func TestUseMemFS(t *testing.T) {
+	prev := abspath.CurrentFS()
+	t.Run("memfs", func(t *testing.T) {
+		fs := UseMemFS(t)
+		if abspath.CurrentFS() != abspath.FS(fs) {
+			t.Fatal("In-memory filesystem should be set")
+		}
+		p := TempTree(t, nil).Join("file.txt") // Only to get some absolute path
+		if err := fs.WriteFile(p.String(), []byte("hello"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		RequireContent(t, p, "hello")
+	})
+	if abspath.CurrentFS() != prev {
+		t.Fatal("Previous filesystem should be restored")
+	}
+}
@@ -0,0 +1,199 @@
+// Package aferofs provides interoperability between abspath.AbsPath and afero.Fs.
+//
+// Rooted() creates an afero.Fs rooted at an absolute path on the OS filesystem.  Fs wraps any afero.Fs such as
+// afero.MemMapFs and provides file operations which accept abspath.AbsPath values so that code using AbsPath can be
+// tested without touching the disk.
+//
+// Ref: https://github.com/spf13/afero
+package aferofs
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rhysd/abspath"
+	"github.com/spf13/afero"
+)
+
+// Rooted returns an afero.Fs which is restricted to the directory tree at the root on the OS filesystem.  Paths
+// given to the returned afero.Fs are interpreted relative to the root.
+//
+// Example:
+//	root, _ := abspath.ExpandFrom("~/.config/myapp")
+//	fs := aferofs.Rooted(root)
+//	afero.WriteFile(fs, "/config.json", data, 0644) // Writes ~/.config/myapp/config.json
+func Rooted(root abspath.AbsPath) afero.Fs {
+	return afero.NewBasePathFs(afero.NewOsFs(), root.String())
+}
+
+// Fs executes file operations for absolute paths against an afero.Fs.
+type Fs struct {
+	fs afero.Fs
+}
+
+// New creates a new Fs which executes file operations against the given afero.Fs.
+//
+// Example:
+//	fs := aferofs.New(afero.NewMemMapFs())
+//	p, _ := abspath.New("/path/to/file.txt")
+//	fs.WriteFile(p, []byte("hello"), 0644)
+func New(fs afero.Fs) *Fs {
+	return &Fs{fs}
+}
+
+// OS returns a new Fs which executes file operations against the OS filesystem.
+func OS() *Fs {
+	return &Fs{afero.NewOsFs()}
+}
+
+// Afero returns the underlying afero.Fs.
+func (f *Fs) Afero() afero.Fs {
+	return f.fs
+}
+
+// Stat returns the file info of the path.
+func (f *Fs) Stat(p abspath.AbsPath) (os.FileInfo, error) {
+	return f.fs.Stat(p.String())
+}
+
+// Exists returns whether the path exists.
+func (f *Fs) Exists(p abspath.AbsPath) (bool, error) {
+	return afero.Exists(f.fs, p.String())
+}
+
+// IsDir returns whether the path is a directory.
+func (f *Fs) IsDir(p abspath.AbsPath) (bool, error) {
+	return afero.IsDir(f.fs, p.String())
+}
+
+// Open opens the file at the path for reading.
+func (f *Fs) Open(p abspath.AbsPath) (afero.File, error) {
+	return f.fs.Open(p.String())
+}
+
+// Create creates or truncates the file at the path.
+func (f *Fs) Create(p abspath.AbsPath) (afero.File, error) {
+	return f.fs.Create(p.String())
+}
+
+// OpenFile opens the file at the path with the flag and the permission.  It is equivalent to os.OpenFile().
+func (f *Fs) OpenFile(p abspath.AbsPath, flag int, perm os.FileMode) (afero.File, error) {
+	return f.fs.OpenFile(p.String(), flag, perm)
+}
+
+// ReadFile reads the whole content of the file at the path.
+func (f *Fs) ReadFile(p abspath.AbsPath) ([]byte, error) {
+	return afero.ReadFile(f.fs, p.String())
+}
+
+// WriteFile writes the data to the file at the path.  The file is created with the permission if it does not exist.
+func (f *Fs) WriteFile(p abspath.AbsPath, data []byte, perm os.FileMode) error {
+	return afero.WriteFile(f.fs, p.String(), data, perm)
+}
+
+// ReadDir returns absolute paths of entries in the directory at the path sorted by their names.
+func (f *Fs) ReadDir(p abspath.AbsPath) ([]abspath.AbsPath, error) {
+	infos, err := afero.ReadDir(f.fs, p.String())
+	if err != nil {
+		return nil, err
+	}
+	ps := make([]abspath.AbsPath, 0, len(infos))
+	for _, i := range infos {
+		ps = append(ps, p.Join(i.Name()))
+	}
+	return ps, nil
+}
+
+// Mkdir creates a directory at the path.
+func (f *Fs) Mkdir(p abspath.AbsPath, perm os.FileMode) error {
+	return f.fs.Mkdir(p.String(), perm)
+}
+
+// MkdirAll creates a directory at the path along with any necessary parents.
+func (f *Fs) MkdirAll(p abspath.AbsPath, perm os.FileMode) error {
+	return f.fs.MkdirAll(p.String(), perm)
+}
+
+// Rename renames the file at the path from to the path to.
+func (f *Fs) Rename(from, to abspath.AbsPath) error {
+	return f.fs.Rename(from.String(), to.String())
+}
+
+// Remove removes the file or the empty directory at the path.
+func (f *Fs) Remove(p abspath.AbsPath) error {
+	return f.fs.Remove(p.String())
+}
+
+// RemoveAll removes the path and any children it contains.
+func (f *Fs) RemoveAll(p abspath.AbsPath) error {
+	return f.fs.RemoveAll(p.String())
+}
+
+// Walk walks the file tree rooted at the path.  It is equivalent to abspath.AbsPath.Walk() but paths passed to the
+// callback are absolute paths.
+func (f *Fs) Walk(root abspath.AbsPath, walkFn func(p abspath.AbsPath, info os.FileInfo, err error) error) error {
+	return afero.Walk(f.fs, root.String(), func(p string, info os.FileInfo, err error) error {
+		a, aerr := abspath.New(filepath.Clean(p))
+		if aerr != nil {
+			return aerr
+		}
+		return walkFn(a, info, err)
+	})
+}
+
+type backend struct {
+	afero.Fs
+}
+
+// Backend returns an abspath.FS which executes file operations against the given afero.Fs.  Setting it by
+// abspath.SetFS() makes all methods of abspath.AbsPath access the afero.Fs.  Symbolic links are supported only when
+// the afero.Fs supports them.
+//
+// Example:
+//	prev := abspath.SetFS(aferofs.Backend(afero.NewMemMapFs()))
+//	defer abspath.SetFS(prev)
+func Backend(fs afero.Fs) abspath.FS {
+	return backend{fs}
+}
+
+func (b backend) Open(name string) (abspath.File, error) {
+	f, err := b.Fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b backend) OpenFile(name string, flag int, perm os.FileMode) (abspath.File, error) {
+	f, err := b.Fs.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b backend) Lstat(name string) (os.FileInfo, error) {
+	if l, ok := b.Fs.(afero.Lstater); ok {
+		s, _, err := l.LstatIfPossible(name)
+		return s, err
+	}
+	return b.Fs.Stat(name)
+}
+
+func (b backend) ReadDir(name string) ([]os.FileInfo, error) {
+	return afero.ReadDir(b.Fs, name)
+}
+
+func (b backend) Readlink(name string) (string, error) {
+	if l, ok := b.Fs.(afero.LinkReader); ok {
+		return l.ReadlinkIfPossible(name)
+	}
+	return "", &os.PathError{Op: "readlink", Path: name, Err: afero.ErrNoReadlink}
+}
+
+func (b backend) Symlink(oldname, newname string) error {
+	if l, ok := b.Fs.(afero.Linker); ok {
+		return l.SymlinkIfPossible(oldname, newname)
+	}
+	return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: afero.ErrNoSymlink}
+}
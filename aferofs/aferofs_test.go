@@ -0,0 +1,143 @@
+package aferofs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rhysd/abspath"
+	"github.com/spf13/afero"
+)
+
+func root(t *testing.T) abspath.AbsPath {
+	s := "/"
+	if runtime.GOOS == "windows" {
+		s = `C:\`
+	}
+	p, err := abspath.New(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestMemMapFs(t *testing.T) {
+	fs := New(afero.NewMemMapFs())
+	dir := root(t).Join("path", "to")
+	file := dir.Join("file.txt")
+
+	if err := fs.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile(file, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := fs.Exists(file); err != nil || !ok {
+		t.Fatalf("%s should exist: %v", file, err)
+	}
+	if ok, err := fs.IsDir(dir); err != nil || !ok {
+		t.Fatalf("%s should be a directory: %v", dir, err)
+	}
+	b, err := fs.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("Unexpected content: %q", b)
+	}
+
+	ps, err := fs.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 1 || ps[0] != file {
+		t.Errorf("Unexpected entries: %v", ps)
+	}
+
+	walked := []abspath.AbsPath{}
+	err = fs.Walk(dir, func(p abspath.AbsPath, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		walked = append(walked, p)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(walked) != 2 || walked[0] != dir || walked[1] != file {
+		t.Errorf("Unexpected walked paths: %v", walked)
+	}
+
+	moved := dir.Join("moved.txt")
+	if err := fs.Rename(file, moved); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := fs.Exists(file); ok {
+		t.Errorf("%s should not exist after rename", file)
+	}
+	if err := fs.RemoveAll(root(t).Join("path")); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := fs.Exists(moved); ok {
+		t.Errorf("%s should not exist after removal", moved)
+	}
+}
+
+func TestRooted(t *testing.T) {
+	d, err := ioutil.TempDir("", "abspath-aferofs-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d)
+	dir, err := abspath.New(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := Rooted(dir)
+	if err := afero.WriteFile(fs, "/foo.txt", []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(d, "foo.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "foo" {
+		t.Errorf("Unexpected content: %q", b)
+	}
+}
+
+func TestBackend(t *testing.T) {
+	mem := afero.NewMemMapFs()
+	prev := abspath.SetFS(Backend(mem))
+	defer abspath.SetFS(prev)
+
+	dir := root(t).Join("src")
+	if err := afero.WriteFile(mem, dir.Join("a", "b.txt").String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := root(t).Join("dst")
+	if err := dir.CopyDir(dst, nil); err != nil {
+		t.Fatal(err)
+	}
+	b, err := afero.ReadFile(mem, dst.Join("a", "b.txt").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("Unexpected content: %q", b)
+	}
+
+	s, err := dst.Count(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Files != 1 || s.Dirs != 1 {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+}
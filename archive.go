@@ -0,0 +1,107 @@
+package abspath
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// UnsafeEntryNameError is an error returned from SafeChild() when a name of an archive entry cannot be extracted
+// under the destination directory safely.
+type UnsafeEntryNameError struct {
+	// Name is the name of the archive entry.
+	Name string
+	// Reason describes why the name is unsafe.
+	Reason string
+}
+
+func (err *UnsafeEntryNameError) Error() string {
+	return fmt.Sprintf("Archive entry name %q is unsafe: %s", err.Name, err.Reason)
+}
+
+// windowsReservedNames is a list of device names which cannot be used as file names on Windows even with extensions.
+var windowsReservedNames = []string{
+	"CON", "PRN", "AUX", "NUL",
+	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
+}
+
+// checkWindowsComponent returns the reason when the component cannot be used as a file name on Windows.
+func checkWindowsComponent(c string) string {
+	for _, r := range c {
+		if r < 0x20 || strings.ContainsRune(`<>:"|?*`, r) {
+			return fmt.Sprintf("component %q contains invalid character %q", c, r)
+		}
+	}
+	if c != "." && strings.HasSuffix(c, ".") || strings.HasSuffix(c, " ") {
+		return fmt.Sprintf("component %q ends with '.' or ' '", c)
+	}
+	stem := c
+	if i := strings.IndexByte(stem, '.'); i >= 0 {
+		stem = stem[:i]
+	}
+	if containsName(windowsReservedNames, strings.ToUpper(strings.TrimRight(stem, " "))) {
+		return fmt.Sprintf("component %q is a reserved device name", c)
+	}
+	return ""
+}
+
+// SafeChild validates the name of an archive entry (e.g. a member of zip or tar archive) and returns the path where
+// the entry should be extracted under the destination directory.  Names are slash-separated as archive formats define.
+// It returns *UnsafeEntryNameError when the name is empty, absolute, starts with a drive letter, contains a NUL
+// character or contains a '..' component.  On Windows, '\' is also treated as a separator and names which cannot be
+// created such as names containing '<>:"|?*' or reserved device names like 'CON' are rejected as well.  This prevents
+// the 'zip slip' vulnerability where a crafted archive writes files outside the destination.  Note that the check is
+// lexical.  Symbolic links extracted from the archive itself must be checked separately.
+//
+// Example:
+//	dest, _ := abspath.New("/path/to/dest")
+//	for _, f := range zipReader.File {
+//		p, err := dest.SafeChild(f.Name)
+//		if err != nil {
+//			return err
+//		}
+//		// Extract f to p
+//	}
+func (a AbsPath) SafeChild(archiveEntryName string) (AbsPath, error) {
+	name := archiveEntryName
+	unsafe := func(format string, args ...interface{}) (AbsPath, error) {
+		return AbsPath{""}, &UnsafeEntryNameError{archiveEntryName, fmt.Sprintf(format, args...)}
+	}
+
+	if name == "" {
+		return unsafe("name is empty")
+	}
+	if strings.IndexByte(name, 0) >= 0 {
+		return unsafe("name contains NUL character")
+	}
+
+	windows := filepath.Separator == '\\'
+	if windows {
+		name = strings.Replace(name, `\`, "/", -1)
+	}
+	if strings.HasPrefix(name, "/") {
+		return unsafe("name is absolute")
+	}
+	if len(name) >= 2 && name[1] == ':' && ('a' <= name[0] && name[0] <= 'z' || 'A' <= name[0] && name[0] <= 'Z') {
+		return unsafe("name starts with drive letter %q", name[:2])
+	}
+
+	for _, c := range strings.Split(name, "/") {
+		if c == ".." {
+			return unsafe("name escapes from the destination with '..'")
+		}
+		if !windows || c == "" {
+			continue
+		}
+		if r := checkWindowsComponent(c); r != "" {
+			return unsafe("%s", r)
+		}
+	}
+
+	p := a.Join(filepath.FromSlash(name))
+	if !a.ContainsPath(p) {
+		return unsafe("name is not under the destination '%s'", a.underlying)
+	}
+	return p, nil
+}
@@ -0,0 +1,91 @@
+package abspath
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSafeChildOK(t *testing.T) {
+	dest, err := FromSlash(fixAbsPath("/path/to/dest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		name     string
+		expected string
+	}{
+		{"foo.txt", "foo.txt"},
+		{"dir/foo.txt", "dir/foo.txt"},
+		{"dir/", "dir"},
+		{"./dir/./foo.txt", "dir/foo.txt"},
+		{"dir//foo.txt", "dir/foo.txt"},
+		{"..foo/bar..", "..foo/bar.."},
+		{"./", ""},
+	} {
+		p, err := dest.SafeChild(c.name)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", c.name, err)
+			continue
+		}
+		expected := dest.Join(filepath.FromSlash(c.expected))
+		if p != expected {
+			t.Errorf("Expected %s for %q but got %s", expected, c.name, p)
+		}
+	}
+}
+
+func TestSafeChildUnsafe(t *testing.T) {
+	dest, err := FromSlash(fixAbsPath("/path/to/dest"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{
+		"",
+		"/etc/passwd",
+		"../evil",
+		"dir/../../evil",
+		"dir/..",
+		"C:/Windows/evil",
+		"c:evil",
+		"foo\x00.txt",
+	}
+	if isWindows {
+		names = append(names, `..\evil`, `\evil`, `dir\..\..\evil`, "foo:bar", "a<b", "CON", "nul.txt", "dir/foo.", "foo ")
+	}
+
+	for _, n := range names {
+		p, err := dest.SafeChild(n)
+		if err == nil {
+			t.Errorf("Error should occur for %q but got %s", n, p)
+			continue
+		}
+		var uerr *UnsafeEntryNameError
+		if !errors.As(err, &uerr) {
+			t.Errorf("Unexpected error type for %q: %T %s", n, err, err)
+			continue
+		}
+		if uerr.Name != n {
+			t.Errorf("Name in error should be %q but got %q", n, uerr.Name)
+		}
+		if p != (AbsPath{""}) {
+			t.Errorf("Empty path should be returned on error but got %q", p)
+		}
+	}
+}
+
+func TestSafeChildBackslashOnUnix(t *testing.T) {
+	if isWindows {
+		t.Skip("Backslash is a separator on Windows")
+	}
+	dest, _ := FromSlash("/dest")
+	p, err := dest.SafeChild(`..\evil`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Dir() != dest {
+		t.Errorf("Backslash should be a part of file name on Unix: %s", p)
+	}
+}
@@ -0,0 +1,104 @@
+package abspath
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// AuditEvent is an event of a mutating filesystem operation reported to the audit hook.  Each operation is reported
+// twice, before and after it is performed.
+type AuditEvent struct {
+	// Op is the name of the operation such as "remove", "rename" or "mkdir".  Names of operations via FS are the
+	// lowercased names of FS methods.  Opening a file is reported as "open" only when it may modify the file.
+	Op string
+	// Paths is a list of paths involved in the operation.  For "rename", they are the old path and the new path.
+	Paths []AbsPath
+	// Detail is additional information of the operation in human readable form such as the permission for "chmod" or
+	// the link target for "symlink".  It may be empty.
+	Detail string
+	// Done is false before the operation is performed and true after it.
+	Done bool
+	// Err is the result of the operation.  It is always nil when Done is false.
+	Err error
+}
+
+// AuditHook is a function called before and after every mutating filesystem operation performed by this package.
+// Since operations may be performed concurrently, it must be safe for concurrent use.
+type AuditHook func(e *AuditEvent)
+
+type auditHolder struct {
+	hook AuditHook
+}
+
+var currentAuditHook atomic.Value
+
+func init() {
+	currentAuditHook.Store(auditHolder{})
+}
+
+func auditHook() AuditHook {
+	return currentAuditHook.Load().(auditHolder).hook
+}
+
+// SetAuditHook sets the hook called on every mutating filesystem operation (creating, writing, removing, renaming,
+// changing permissions, ...) performed by this package and returns the previous one.  When nil is given, auditing is
+// disabled.  It is useful to produce an audit trail without wrapping every call site.  The hook is applied to both the
+// OS filesystem and an FS set by SetFS().  Writes to files opened by the operations are not reported individually.
+//
+// Example:
+//	abspath.SetAuditHook(func(e *abspath.AuditEvent) {
+//		if e.Done {
+//			log.Printf("audit: op=%s paths=%v detail=%q err=%v", e.Op, e.Paths, e.Detail, e.Err)
+//		}
+//	})
+func SetAuditHook(h AuditHook) AuditHook {
+	return currentAuditHook.Swap(auditHolder{h}).(auditHolder).hook
+}
+
+// audit performs the mutating operation via the middlewares and the audit hook when they are set.
+func audit(op, detail string, f func() error, paths ...string) error {
+	return intercept(op, detail, true, f, paths...)
+}
+
+// auditMiddleware returns a middleware which reports mutating operations to the audit hook.
+func auditMiddleware(h AuditHook) Middleware {
+	return func(next Op) Op {
+		return func(info *OpInfo) error {
+			if !info.Mutating {
+				return next(info)
+			}
+			h(&AuditEvent{Op: info.Name, Paths: info.Paths, Detail: info.Detail})
+			err := next(info)
+			h(&AuditEvent{Op: info.Name, Paths: info.Paths, Detail: info.Detail, Done: true, Err: err})
+			return err
+		}
+	}
+}
+
+// mutatingFlags is a set of flags of OpenFile() which may modify the file.
+const mutatingFlags = os.O_WRONLY | os.O_RDWR | os.O_CREATE | os.O_TRUNC | os.O_APPEND
+
+func openFlagString(flag int) string {
+	fs := []string{"O_RDONLY"}
+	switch {
+	case flag&os.O_RDWR != 0:
+		fs[0] = "O_RDWR"
+	case flag&os.O_WRONLY != 0:
+		fs[0] = "O_WRONLY"
+	}
+	for _, f := range []struct {
+		flag int
+		name string
+	}{
+		{os.O_APPEND, "O_APPEND"},
+		{os.O_CREATE, "O_CREATE"},
+		{os.O_EXCL, "O_EXCL"},
+		{os.O_TRUNC, "O_TRUNC"},
+	} {
+		if flag&f.flag != 0 {
+			fs = append(fs, f.name)
+		}
+	}
+	return strings.Join(fs, "|")
+}
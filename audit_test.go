@@ -0,0 +1,167 @@
+package abspath
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type auditRecorder struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (r *auditRecorder) hook(e *AuditEvent) {
+	r.mu.Lock()
+	r.events = append(r.events, *e)
+	r.mu.Unlock()
+}
+
+func (r *auditRecorder) ops() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ops := []string{}
+	for _, e := range r.events {
+		if e.Done {
+			ops = append(ops, e.Op)
+		}
+	}
+	return ops
+}
+
+func TestAuditHookReportsMutations(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	a := root.Join("a.txt")
+	b := root.Join("b.txt")
+
+	r := &auditRecorder{}
+	prev := SetAuditHook(r.hook)
+	defer SetAuditHook(prev)
+
+	if err := a.Truncate(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.RenameRetry(b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RemoveRetry(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.events) != 6 {
+		t.Fatalf("Each operation should be reported twice but got %d events: %v", len(r.events), r.events)
+	}
+	for i, want := range []AuditEvent{
+		{Op: "truncate", Paths: []AbsPath{a}, Detail: "size=2"},
+		{Op: "truncate", Paths: []AbsPath{a}, Detail: "size=2", Done: true},
+		{Op: "rename", Paths: []AbsPath{a, b}},
+		{Op: "rename", Paths: []AbsPath{a, b}, Done: true},
+		{Op: "remove", Paths: []AbsPath{b}},
+		{Op: "remove", Paths: []AbsPath{b}, Done: true},
+	} {
+		if !reflect.DeepEqual(r.events[i], want) {
+			t.Errorf("Event #%d should be %+v but got %+v", i, want, r.events[i])
+		}
+	}
+}
+
+func TestAuditHookReportsErrors(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+	missing := root.Join("missing")
+
+	r := &auditRecorder{}
+	prev := SetAuditHook(r.hook)
+	defer SetAuditHook(prev)
+
+	err := missing.Truncate(0)
+	if err == nil {
+		t.Fatal("Error did not occur")
+	}
+	if len(r.events) != 2 {
+		t.Fatalf("Wanted 2 events but got %v", r.events)
+	}
+	if r.events[0].Err != nil {
+		t.Errorf("Event before the operation should not have error: %v", r.events[0].Err)
+	}
+	if !errors.Is(err, r.events[1].Err) {
+		t.Errorf("Event after the operation should have the error %v but got %v", err, r.events[1].Err)
+	}
+}
+
+func TestAuditHookIgnoresReads(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	a := root.Join("a.txt")
+
+	r := &auditRecorder{}
+	prev := SetAuditHook(r.hook)
+	defer SetAuditHook(prev)
+
+	f, err := fsys().OpenFile(a.String(), os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Fatalf("Unexpected content %q", b)
+	}
+	if _, err := fsys().Stat(a.String()); err != nil {
+		t.Fatal(err)
+	}
+	if ops := r.ops(); len(ops) != 0 {
+		t.Errorf("Reading operations should not be reported but got %v", ops)
+	}
+
+	f, err = fsys().OpenFile(a.String(), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	want := AuditEvent{Op: "open", Paths: []AbsPath{a}, Detail: "flag=O_WRONLY|O_APPEND perm=0", Done: true}
+	if len(r.events) != 2 || !reflect.DeepEqual(r.events[1], want) {
+		t.Errorf("Opening file for writing should be reported as %+v but got %+v", want, r.events)
+	}
+}
+
+func TestAuditHookKeepsCurrentFS(t *testing.T) {
+	prev := SetAuditHook(func(e *AuditEvent) {})
+	defer SetAuditHook(prev)
+
+	if CurrentFS() != OSFS {
+		t.Errorf("CurrentFS() should not be wrapped by audit hook: %T", CurrentFS())
+	}
+	if !usesOSFS() {
+		t.Error("OS filesystem should be detected while audit hook is set")
+	}
+
+	fs := &recordingFS{FS: OSFS}
+	prevFS := SetFS(fs)
+	defer SetFS(prevFS)
+	if CurrentFS() != FS(fs) {
+		t.Errorf("CurrentFS() should return the FS set by SetFS() but got %T", CurrentFS())
+	}
+}
+
+func TestSetAuditHookReturnsPrevious(t *testing.T) {
+	called := false
+	h := func(e *AuditEvent) { called = true }
+	prev := SetAuditHook(h)
+	defer SetAuditHook(prev)
+
+	if got := SetAuditHook(nil); got == nil {
+		t.Error("Previous hook should be returned")
+	}
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	if err := root.Join("a.txt").Truncate(0); err != nil {
+		t.Fatal(err)
+	}
+	if called {
+		t.Error("Hook should not be called after it is unset")
+	}
+}
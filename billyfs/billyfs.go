@@ -0,0 +1,58 @@
+// Package billyfs provides interoperability between abspath.AbsPath and billy.Filesystem used by go-git.
+//
+// Ref: https://github.com/go-git/go-billy
+package billyfs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/rhysd/abspath"
+)
+
+// Rooted returns a billy.Filesystem on the OS filesystem rooted at the directory.  Paths given to the returned
+// filesystem are interpreted relative to the root and paths escaping from the root are rejected.
+//
+// Example:
+//	wt, _ := abspath.ExpandFrom("~/repos/myrepo")
+//	dot := wt.Join(".git")
+//	s := filesystem.NewStorage(billyfs.Rooted(dot), cache.NewObjectLRUDefault())
+//	repo, err := git.Open(s, billyfs.Rooted(wt))
+func Rooted(root abspath.AbsPath) billy.Filesystem {
+	return osfs.New(root.String())
+}
+
+// Root returns the root directory of the filesystem as an absolute path.
+func Root(fs billy.Filesystem) (abspath.AbsPath, error) {
+	return abspath.New(fs.Root())
+}
+
+// Rel returns the path relative to the root of the filesystem so that it can be passed to methods of the filesystem.
+// It returns an error when the path is not under the root.
+//
+// Example:
+//	fs := billyfs.Rooted(root)
+//	rel, err := billyfs.Rel(fs, root.Join("path", "to", "file.txt")) // => "path/to/file.txt"
+//	f, err := fs.Open(rel)
+func Rel(fs billy.Filesystem, p abspath.AbsPath) (string, error) {
+	root, err := Root(fs)
+	if err != nil {
+		return "", err
+	}
+	if !root.ContainsPath(p) {
+		return "", fmt.Errorf("path '%s' is not under the root of filesystem '%s'", p, root)
+	}
+	return filepath.Rel(root.String(), p.String())
+}
+
+// Chroot returns a new filesystem rooted at the directory.  The directory must be under the root of the filesystem.
+// It is useful to derive a filesystem for a subdirectory such as .git from a filesystem of a worktree.
+func Chroot(fs billy.Filesystem, dir abspath.AbsPath) (billy.Filesystem, error) {
+	rel, err := Rel(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Chroot(rel)
+}
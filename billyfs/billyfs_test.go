@@ -0,0 +1,88 @@
+package billyfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/rhysd/abspath"
+)
+
+func tempDir(t *testing.T) abspath.AbsPath {
+	d, err := ioutil.TempDir("", "abspath-billyfs-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(d) })
+	p, err := abspath.New(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestRootedAndRel(t *testing.T) {
+	root := tempDir(t)
+	fs := Rooted(root)
+
+	r, err := Root(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != root {
+		t.Errorf("Root should be %s but got %s", root, r)
+	}
+
+	file := root.Join("path", "to", "file.txt")
+	rel, err := Rel(fs, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel != filepath.Join("path", "to", "file.txt") {
+		t.Errorf("Unexpected relative path: %q", rel)
+	}
+	if err := util.WriteFile(fs, rel, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(file.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("Unexpected content: %q", b)
+	}
+
+	if _, err := Rel(fs, root.Dir()); err == nil {
+		t.Errorf("Path outside the root should cause an error")
+	}
+}
+
+func TestChroot(t *testing.T) {
+	root := tempDir(t)
+	fs := Rooted(root)
+	sub := root.Join("sub")
+
+	c, err := Chroot(fs, sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := util.WriteFile(c, "foo.txt", []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(sub.Join("foo.txt").String()); err != nil {
+		t.Errorf("File should be created in chroot directory: %v", err)
+	}
+	r, err := Root(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != sub {
+		t.Errorf("Root should be %s but got %s", sub, r)
+	}
+
+	if _, err := Chroot(fs, root.Dir()); err == nil {
+		t.Errorf("Directory outside the root should cause an error")
+	}
+}
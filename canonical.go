@@ -0,0 +1,85 @@
+package abspath
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// resolveCase returns the path with the casing of each component stored on disk.  It reads each parent directory and
+// finds the entry whose name matches the component.  An exact match is preferred.  Otherwise names are compared in
+// case-insensitive after normalizing them in NFC.
+func resolveCase(a AbsPath) (AbsPath, error) {
+	v, ss := a.SplitAll()
+	cur := Volume{v}.Root()
+	for _, s := range ss {
+		entries, err := fsys().ReadDir(cur.underlying)
+		if err != nil {
+			return AbsPath{""}, err
+		}
+
+		found := ""
+		want := norm.NFC.String(s)
+		for _, e := range entries {
+			n := e.Name()
+			if n == s {
+				found = n
+				break
+			}
+			if found == "" && strings.EqualFold(norm.NFC.String(n), want) {
+				found = n
+			}
+		}
+		if found == "" {
+			return AbsPath{""}, &os.PathError{Op: "open", Path: cur.Join(s).underlying, Err: os.ErrNotExist}
+		}
+		cur = cur.Join(found)
+	}
+	return cur, nil
+}
+
+// CaseCorrect returns the path with the exact casing of each component stored on disk.  On case-insensitive filesystems,
+// a path can be accessed with any casing, but display strings and string comparison should use the actual casing.  On
+// Windows it uses GetFinalPathNameByHandle, which also resolves symbolic links and junctions.  On other platforms it
+// reads each parent directory and finds the entry matching the component.  Since it accesses the filesystem, the path
+// must exist.
+//
+// Example:
+//	a, _ := abspath.New(`c:\users\foo\documents`)
+//	c, err := a.CaseCorrect() // => `C:\Users\foo\Documents`
+func (a AbsPath) CaseCorrect() (_ AbsPath, err error) {
+	defer annotate(&err, "correct case of", a)
+	return caseCorrect(a)
+}
+
+// normalizingOS is true when filesystems treat names in different Unicode normalization forms as the same name by
+// default.  On other platforms such as Linux, names in NFC and NFD are distinct files.
+const normalizingOS = runtime.GOOS == "darwin"
+
+// Canonicalize returns the canonical form of the path.  It resolves all symbolic links, resolves the casing of each
+// component stored on disk (only on Windows and macOS, where filesystems are case-insensitive by default) and normalizes
+// the path in Unicode NFC (only on macOS, where filesystems are normalization-insensitive).  The result identifies the
+// file uniquely so it is suitable for keys to deduplicate paths or for keys of caches.  Since it accesses the
+// filesystem, the path must exist.
+//
+// Example:
+//	a, _ := abspath.ExpandFrom("~/Documents/../Documents/Link-To-File")
+//	c, err := a.Canonicalize()
+func (a AbsPath) Canonicalize() (_ AbsPath, err error) {
+	defer annotate(&err, "canonicalize", a)
+	c, err := a.EvalSymlinks()
+	if err != nil {
+		return AbsPath{""}, err
+	}
+	if caseInsensitiveOS {
+		if c, err = c.CaseCorrect(); err != nil {
+			return AbsPath{""}, err
+		}
+	}
+	if normalizingOS {
+		c = c.NormalizeUnicode(NFC)
+	}
+	return c, nil
+}
@@ -0,0 +1,148 @@
+package abspath
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestResolveCase(t *testing.T) {
+	root := makeTree(t, map[string]string{"Foo/Bar.txt": "", "Foo/café": ""})
+	root, err := root.EvalSymlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		input    []string
+		expected []string
+	}{
+		{[]string{"Foo", "Bar.txt"}, []string{"Foo", "Bar.txt"}},
+		{[]string{"FOO", "BAR.TXT"}, []string{"Foo", "Bar.txt"}},
+		{[]string{"foo", "CAFÉ"}, []string{"Foo", "café"}},
+	} {
+		r, err := resolveCase(root.Join(c.input...))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		e := root.Join(c.expected...)
+		if r != e {
+			t.Errorf("Expected %s but actually %s", e, r)
+		}
+	}
+
+	if !caseInsensitiveOS {
+		for _, n := range []string{"exact", "EXACT"} {
+			if err := os.Mkdir(root.Join(n).String(), 0755); err != nil {
+				t.Fatal(err)
+			}
+			r, err := resolveCase(root.Join(n))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r != root.Join(n) {
+				t.Errorf("Exact match should be preferred but actually %s", r)
+			}
+		}
+	}
+
+	if _, err := resolveCase(root.Join("Foo", "not-exist")); !os.IsNotExist(err) {
+		t.Errorf("Expected not exist error but actually %v", err)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	root := makeTree(t, map[string]string{"dir/café": "hello"})
+	root, err := root.EvalSymlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := root.Join("dir", "café")
+
+	inputs := []AbsPath{root.Join("dir", "café")}
+	if !isWindows {
+		l := root.Join("link")
+		if err := os.Symlink(root.Join("dir").String(), l.String()); err != nil {
+			t.Fatal(err)
+		}
+		inputs = append(inputs, l.Join("café"))
+	}
+	if caseInsensitiveOS {
+		inputs = append(inputs, root.Join("DIR", "CAFÉ"))
+	}
+
+	for _, i := range inputs {
+		c, err := i.Canonicalize()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if c != target {
+			t.Errorf("Expected %q but actually %q for %q", target, c, i)
+		}
+	}
+
+	if _, err := root.Join("not-exist").Canonicalize(); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+}
+
+func TestCanonicalizeKeepsNormalizationOnLinux(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Only Linux filesystems are known to distinguish names in NFC and NFD")
+	}
+	nfc, nfd := "caf\u00e9", "cafe\u0301"
+	root := makeTree(t, map[string]string{nfc: "nfc", nfd: "nfd"})
+	root, err := root.EvalSymlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []string{nfc, nfd} {
+		p := root.Join(n)
+		c, err := p.Canonicalize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c != p {
+			t.Errorf("Expected %q but actually %q", p, c)
+		}
+	}
+
+	ps, err := Dedupe([]AbsPath{root.Join(nfc), root.Join(nfd)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 2 {
+		t.Errorf("Distinct files in NFC and NFD should not be deduplicated: %q", ps)
+	}
+}
+
+func TestCaseCorrect(t *testing.T) {
+	root := makeTree(t, map[string]string{"Foo/Bar.txt": ""})
+	root, err := root.EvalSymlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := root.Join("Foo", "Bar.txt")
+
+	inputs := []AbsPath{e}
+	if caseInsensitiveOS {
+		inputs = append(inputs, root.Join("foo", "BAR.TXT"))
+	}
+	for _, i := range inputs {
+		c, err := i.CaseCorrect()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if c != e {
+			t.Errorf("Expected %s but actually %s", e, c)
+		}
+	}
+
+	if _, err := root.Join("not-exist").CaseCorrect(); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+// +build !windows
+
+package abspath
+
+// caseCorrect returns the path with the casing stored on disk by reading each parent directory.
+func caseCorrect(a AbsPath) (AbsPath, error) {
+	return resolveCase(a)
+}
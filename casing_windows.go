@@ -0,0 +1,52 @@
+package abspath
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// caseCorrect returns the path with the casing stored on disk using GetFinalPathNameByHandle.
+func caseCorrect(a AbsPath) (AbsPath, error) {
+	p, err := windows.UTF16PtrFromString(a.underlying)
+	if err != nil {
+		return AbsPath{""}, err
+	}
+	h, err := windows.CreateFile(
+		p,
+		0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil,
+		windows.OPEN_EXISTING,
+		windows.FILE_FLAG_BACKUP_SEMANTICS,
+		0,
+	)
+	if err != nil {
+		return AbsPath{""}, &os.PathError{Op: "CreateFile", Path: a.underlying, Err: err}
+	}
+	defer windows.CloseHandle(h)
+
+	// FILE_NAME_NORMALIZED | VOLUME_NAME_DOS
+	const flags = 0
+	buf := make([]uint16, windows.MAX_PATH)
+	for {
+		n, err := windows.GetFinalPathNameByHandle(h, &buf[0], uint32(len(buf)), flags)
+		if err != nil {
+			return AbsPath{""}, &os.PathError{Op: "GetFinalPathNameByHandle", Path: a.underlying, Err: err}
+		}
+		if int(n) < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]uint16, n)
+	}
+
+	s := windows.UTF16ToString(buf)
+	if strings.HasPrefix(s, `\\?\UNC\`) {
+		s = `\\` + s[len(`\\?\UNC\`):]
+	} else {
+		s = strings.TrimPrefix(s, `\\?\`)
+	}
+	return New(s)
+}
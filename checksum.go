@@ -0,0 +1,324 @@
+package abspath
+
+import (
+	"context"
+	"crypto"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	// Commonly used hash functions are available by name without importing them
+	_ "crypto/md5"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// cryptoHashes maps names of hash functions to crypto.Hash values.  Packages implementing them must be linked into the
+// binary except for MD5, SHA-1 and SHA-2 family.
+var cryptoHashes = map[string]crypto.Hash{
+	"md5":         crypto.MD5,
+	"sha1":        crypto.SHA1,
+	"sha224":      crypto.SHA224,
+	"sha256":      crypto.SHA256,
+	"sha384":      crypto.SHA384,
+	"sha512":      crypto.SHA512,
+	"sha512-224":  crypto.SHA512_224,
+	"sha512-256":  crypto.SHA512_256,
+	"sha3-224":    crypto.SHA3_224,
+	"sha3-256":    crypto.SHA3_256,
+	"sha3-384":    crypto.SHA3_384,
+	"sha3-512":    crypto.SHA3_512,
+	"blake2s-256": crypto.BLAKE2s_256,
+	"blake2b-256": crypto.BLAKE2b_256,
+	"blake2b-384": crypto.BLAKE2b_384,
+	"blake2b-512": crypto.BLAKE2b_512,
+}
+
+var hashRegistry = struct {
+	sync.RWMutex
+	m map[string]func() hash.Hash
+}{m: map[string]func() hash.Hash{}}
+
+// RegisterHash registers a hash function with the name so that it can be selected by NewHash() and HashByName().
+// Names are case-insensitive.  A registered function takes precedence over the built-in one with the same name.  It
+// is intended to be called in init() of packages providing non-standard hash functions like xxhash subpackage.
+//
+// Example:
+//	func init() {
+//		abspath.RegisterHash("crc32", func() hash.Hash { return crc32.NewIEEE() })
+//	}
+func RegisterHash(name string, f func() hash.Hash) {
+	hashRegistry.Lock()
+	hashRegistry.m[strings.ToLower(name)] = f
+	hashRegistry.Unlock()
+}
+
+// NewHash creates a hash function selected by the name.  Names are case-insensitive.  Functions registered by
+// RegisterHash() and the following built-in names are available: "md5", "sha1", "sha224", "sha256", "sha384",
+// "sha512", "sha512-224", "sha512-256", "sha3-224", "sha3-256", "sha3-384", "sha3-512", "blake2s-256", "blake2b-256",
+// "blake2b-384" and "blake2b-512".  MD5, SHA-1 and SHA-2 functions are always available.  For other built-in names, the package
+// implementing them must be imported (e.g. golang.org/x/crypto/blake2b).  Otherwise an error is returned.
+//
+// Example:
+//	import _ "golang.org/x/crypto/blake2b"
+//
+//	h, err := abspath.NewHash("blake2b-256")
+func NewHash(name string) (hash.Hash, error) {
+	n := strings.ToLower(name)
+
+	hashRegistry.RLock()
+	f, ok := hashRegistry.m[n]
+	hashRegistry.RUnlock()
+	if ok {
+		return f(), nil
+	}
+
+	h, ok := cryptoHashes[n]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash function %q", name)
+	}
+	if !h.Available() {
+		return nil, fmt.Errorf("hash function %q is not available. import the package implementing it", name)
+	}
+	return h.New(), nil
+}
+
+// knownHash returns whether the name is a built-in or registered hash function.  The name must be in lower case.
+func knownHash(name string) bool {
+	if _, ok := cryptoHashes[name]; ok {
+		return true
+	}
+	hashRegistry.RLock()
+	_, ok := hashRegistry.m[name]
+	hashRegistry.RUnlock()
+	return ok
+}
+
+// hashFile streams the file content through the hash function and returns the digest and the number of hashed bytes.
+// The reading is limited by the rate limiter.  l can be nil.
+func hashFile(ctx context.Context, p string, h hash.Hash, l *RateLimiter) ([]byte, int64, error) {
+	f, err := fsys().Open(p)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	n, err := io.CopyBuffer(h, l.reader(ctx, contextReader{ctx, f}), make([]byte, hashBufferSize))
+	addBytes("hash", n)
+	if err != nil {
+		return nil, n, err
+	}
+	return h.Sum(nil), n, nil
+}
+
+// HashByName is the same as Hash() but the hash function is selected by the name as NewHash() does.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/file")
+//	sum, err := a.HashByName("sha512")
+func (a AbsPath) HashByName(name string) (_ []byte, err error) {
+	defer annotate(&err, "hash", a)
+	h, err := NewHash(name)
+	if err != nil {
+		return nil, err
+	}
+	sum, _, err := hashFile(context.Background(), a.underlying, h, nil)
+	return sum, err
+}
+
+// DigestWriter is an io.Writer which writes data to the underlying writer and calculates its digest at the same
+// time.  It is useful to verify data while copying it in one pass.
+//
+// Example:
+//	h, _ := abspath.NewHash("sha256")
+//	w := abspath.NewDigestWriter(dst, h)
+//	if _, err := io.Copy(w, src); err != nil {
+//		panic(err)
+//	}
+//	fmt.Println(w.SumString())
+type DigestWriter struct {
+	w io.Writer
+	h hash.Hash
+	n int64
+}
+
+// NewDigestWriter creates a DigestWriter which writes to w and calculates digest with h.  When w is nil, data is only
+// hashed.
+func NewDigestWriter(w io.Writer, h hash.Hash) *DigestWriter {
+	if w == nil {
+		w = ioutil.Discard
+	}
+	return &DigestWriter{w, h, 0}
+}
+
+// Write writes the data to the underlying writer and hashes the part which was written successfully.
+func (d *DigestWriter) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	d.h.Write(p[:n])
+	d.n += int64(n)
+	return n, err
+}
+
+// Size returns the number of bytes written so far.
+func (d *DigestWriter) Size() int64 {
+	return d.n
+}
+
+// Sum returns the digest of the data written so far.
+func (d *DigestWriter) Sum() []byte {
+	return d.h.Sum(nil)
+}
+
+// SumString returns the digest of the data written so far as a hex encoded string.
+func (d *DigestWriter) SumString() string {
+	return hex.EncodeToString(d.Sum())
+}
+
+// ChecksumResult is a result of verifying one file listed in a checksum file by VerifyChecksums().
+type ChecksumResult struct {
+	// Path is the path of the verified file.
+	Path AbsPath
+	// Algorithm is the name of the hash function used for the verification.
+	Algorithm string
+	// Expected is the hex encoded digest listed in the checksum file.
+	Expected string
+	// Actual is the hex encoded digest of the file.  It is empty when Err is not nil.
+	Actual string
+	// Err is an error on reading the file such as a missing file.
+	Err error
+}
+
+// OK returns whether the file was read successfully and its digest matches to the expected one.
+func (r *ChecksumResult) OK() bool {
+	return r.Err == nil && r.Expected == r.Actual
+}
+
+// algorithmByLength guesses the hash function from the length of the hex encoded digest as sha256sum-style tools.
+var algorithmByLength = map[int]string{
+	32:  "md5",
+	40:  "sha1",
+	56:  "sha224",
+	64:  "sha256",
+	96:  "sha384",
+	128: "sha512",
+}
+
+// unescapeChecksumPath unescapes a file name in a line starting with '\' in GNU coreutils format.
+func unescapeChecksumPath(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' {
+				b.WriteByte('\n')
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// parseChecksumLine parses one line of a checksum file and returns the algorithm, the hex encoded digest and the file
+// name.  Both GNU format ("<digest>  <file>" or "<digest> *<file>") and BSD tagged format ("SHA256 (<file>) =
+// <digest>") are supported.  A line is parsed in BSD format only when its tag is a known hash function and its digest
+// is hex encoded.  Otherwise it is parsed in GNU format so that a file name like 'foo (1) = x' is not misparsed.
+func parseChecksumLine(l string) (string, string, string, bool) {
+	escaped := strings.HasPrefix(l, "\\")
+	if escaped {
+		l = l[1:]
+	}
+
+	if i := strings.Index(l, " ("); i > 0 {
+		if j := strings.LastIndex(l, ") = "); j > i {
+			algo := strings.ToLower(l[:i])
+			if algo == "blake2b" {
+				algo = "blake2b-512" // b2sum --tag
+			}
+			sum := strings.ToLower(l[j+4:])
+			if _, err := hex.DecodeString(sum); err == nil && sum != "" && knownHash(algo) {
+				name := l[i+2 : j]
+				if escaped {
+					name = unescapeChecksumPath(name)
+				}
+				return algo, sum, name, true
+			}
+		}
+	}
+
+	i := strings.IndexByte(l, ' ')
+	if i <= 0 || i+2 > len(l) || (l[i+1] != ' ' && l[i+1] != '*') {
+		return "", "", "", false
+	}
+	sum, name := strings.ToLower(l[:i]), l[i+2:]
+	if _, err := hex.DecodeString(sum); err != nil {
+		return "", "", "", false
+	}
+	algo, ok := algorithmByLength[len(sum)]
+	if !ok {
+		return "", "", "", false
+	}
+	if escaped {
+		name = unescapeChecksumPath(name)
+	}
+	return algo, sum, name, true
+}
+
+// VerifyChecksums parses the checksum file in the format of sha256sum and similar tools and verifies every listed
+// file.  Relative paths in the checksum file are resolved from baseDir.  The hash function is determined by the length
+// of each digest (md5, sha1, sha224, sha256, sha384 or sha512) or by the tag in BSD format like 'SHA256 (file) =
+// digest'.  Empty lines and lines starting with '#' are ignored.  Results for all listed files are returned in the
+// same order as the checksum file.  An error is returned only when the checksum file cannot be read or contains a
+// malformed line.  Check OK() of each result to know whether the file was verified.
+//
+// Example:
+//	dir, _ := abspath.New("/path/to/downloads")
+//	results, err := abspath.VerifyChecksums(dir.Join("SHA256SUMS"), dir)
+//	if err != nil {
+//		panic(err)
+//	}
+//	for _, r := range results {
+//		if !r.OK() {
+//			fmt.Println("FAILED:", r.Path)
+//		}
+//	}
+func VerifyChecksums(checksumFile AbsPath, baseDir AbsPath) (_ []ChecksumResult, err error) {
+	defer annotate(&err, "verify checksums in", checksumFile)
+	lines, err := checksumFile.ReadLines()
+	if err != nil {
+		return nil, err
+	}
+
+	results := []ChecksumResult{}
+	for i, l := range lines {
+		if strings.TrimSpace(l) == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		algo, sum, name, ok := parseChecksumLine(l)
+		if !ok {
+			return nil, fmt.Errorf("malformed line at %s:%d: %q", checksumFile.underlying, i+1, l)
+		}
+
+		var p AbsPath
+		if filepath.IsAbs(name) {
+			p = AbsPath{filepath.Clean(name)}
+		} else {
+			p = baseDir.Join(filepath.FromSlash(name))
+		}
+
+		r := ChecksumResult{Path: p, Algorithm: algo, Expected: sum}
+		if b, err := p.HashByName(algo); err != nil {
+			r.Err = err
+		} else {
+			r.Actual = hex.EncodeToString(b)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
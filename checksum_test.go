@@ -0,0 +1,260 @@
+package abspath
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewHash(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{"sha256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{"SHA256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{"sha512-256", "e30d87cfa2a75db545eac4d61baf970366a8357c7f72fa95b52d0accb698f13a"},
+	} {
+		h, err := NewHash(tc.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h.Write([]byte("hello"))
+		if s := hex.EncodeToString(h.Sum(nil)); s != tc.want {
+			t.Errorf("Unexpected digest of %q: %s", tc.name, s)
+		}
+	}
+
+	if _, err := NewHash("unknown"); err == nil || !strings.Contains(err.Error(), "unknown hash function") {
+		t.Errorf("Unknown hash function should cause an error: %v", err)
+	}
+	if _, err := NewHash("blake2b-256"); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("Hash function not linked should cause an error: %v", err)
+	}
+}
+
+func TestRegisterHash(t *testing.T) {
+	RegisterHash("CRC32-test", func() hash.Hash { return crc32.NewIEEE() })
+	defer func() {
+		hashRegistry.Lock()
+		delete(hashRegistry.m, "crc32-test")
+		hashRegistry.Unlock()
+	}()
+
+	root := makeTree(t, map[string]string{"f": "hello"})
+	sum, err := root.Join("f").HashByName("crc32-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := hex.EncodeToString(sum); s != "3610a686" {
+		t.Errorf("Unexpected CRC32 digest: %s", s)
+	}
+
+	sum, err = root.Join("f").HashByName("sha256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sha256.Sum256([]byte("hello"))
+	if !bytes.Equal(sum, want[:]) {
+		t.Errorf("Unexpected SHA256 digest: %x", sum)
+	}
+
+	if _, err := root.Join("missing").HashByName("sha256"); err == nil {
+		t.Errorf("Error should occur for missing file")
+	}
+}
+
+type failWriter struct {
+	limit int
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		return w.limit, errors.New("short write")
+	}
+	return len(p), nil
+}
+
+func TestDigestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewDigestWriter(&buf, sha256.New())
+	w.Write([]byte("hel"))
+	w.Write([]byte("lo"))
+	if buf.String() != "hello" {
+		t.Errorf("Data should be written to underlying writer: %q", buf.String())
+	}
+	if w.Size() != 5 {
+		t.Errorf("Unexpected size %d", w.Size())
+	}
+	if w.SumString() != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Errorf("Unexpected digest %s", w.SumString())
+	}
+
+	w = NewDigestWriter(&failWriter{2}, sha256.New())
+	if n, err := w.Write([]byte("hello")); err == nil || n != 2 {
+		t.Errorf("Short write should be reported: %d %v", n, err)
+	}
+	want := sha256.Sum256([]byte("he"))
+	if !bytes.Equal(w.Sum(), want[:]) {
+		t.Errorf("Only written part should be hashed: %x", w.Sum())
+	}
+
+	w = NewDigestWriter(nil, sha256.New())
+	w.Write([]byte("hello"))
+	if w.Size() != 5 {
+		t.Errorf("Data should be hashed without writer")
+	}
+}
+
+func TestCopyFileDigest(t *testing.T) {
+	root := makeTree(t, map[string]string{"src": "hello"})
+	h := sha256.New()
+	if err := root.Join("src").CopyFile(root.Join("dst"), &CopyOptions{Digest: h}); err != nil {
+		t.Fatal(err)
+	}
+	want := sha256.Sum256([]byte("hello"))
+	if !bytes.Equal(h.Sum(nil), want[:]) {
+		t.Errorf("Digest should be calculated while copying: %x", h.Sum(nil))
+	}
+	b, err := ioutil.ReadFile(root.Join("dst").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("Unexpected copied content %q", b)
+	}
+}
+
+func TestVerifyChecksumsMD5AndSHA1(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"a.txt": "hello",
+		"MD5SUMS": "5d41402abc4b2a76b9719d911017c592  a.txt\n" +
+			"MD5 (a.txt) = 5d41402abc4b2a76b9719d911017c592\n",
+		"SHA1SUMS": "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d  a.txt\n",
+	})
+	for _, c := range []struct {
+		file string
+		algo string
+		num  int
+	}{
+		{"MD5SUMS", "md5", 2},
+		{"SHA1SUMS", "sha1", 1},
+	} {
+		rs, err := VerifyChecksums(root.Join(c.file), root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rs) != c.num {
+			t.Fatalf("Unexpected number of results for %s: %+v", c.file, rs)
+		}
+		for _, r := range rs {
+			if r.Algorithm != c.algo || !r.OK() || r.Err != nil {
+				t.Errorf("Unexpected result for %s: %+v", c.file, r)
+			}
+		}
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"a.txt":     "hello",
+		"dir/b.txt": "world",
+		"bad.txt":   "tampered",
+		"new\nline": "hello",
+	})
+	sha := func(s string) string {
+		b := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(b[:])
+	}
+	md := "5d41402abc4b2a76b9719d911017c592" // md5("hello")
+	abs := root.Join("a.txt").String()
+	content := strings.Join([]string{
+		"# comment",
+		sha("hello") + "  a.txt",
+		strings.ToUpper(sha("world")) + " *dir/b.txt",
+		"",
+		sha("hello") + "  bad.txt",
+		sha("hello") + "  missing.txt",
+		"SHA256 (a.txt) = " + sha("hello"),
+		`\` + sha("hello") + `  new\nline`,
+		sha("hello") + "  " + abs,
+		md + "  a.txt",
+	}, "\n") + "\n"
+	sums := root.Join("SHA256SUMS")
+	if err := ioutil.WriteFile(sums.String(), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rs, err := VerifyChecksums(sums, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		path string
+		algo string
+		ok   bool
+		err  bool
+	}{
+		{"a.txt", "sha256", true, false},
+		{"dir/b.txt", "sha256", true, false},
+		{"bad.txt", "sha256", false, false},
+		{"missing.txt", "sha256", false, true},
+		{"a.txt", "sha256", true, false},
+		{"new\nline", "sha256", true, false},
+		{"a.txt", "sha256", true, false},
+		{"a.txt", "md5", true, false},
+	}
+	if len(rs) != len(want) {
+		t.Fatalf("Unexpected number of results: %+v", rs)
+	}
+	for i, w := range want {
+		r := rs[i]
+		if r.Path != root.Join(filepath.FromSlash(w.path)) || r.Algorithm != w.algo || r.OK() != w.ok || (r.Err != nil) != w.err {
+			t.Errorf("Unexpected result at %d: %+v", i, r)
+		}
+	}
+	if rs[2].Actual != sha("tampered") {
+		t.Errorf("Actual digest should be set on mismatch: %+v", rs[2])
+	}
+
+	if err := ioutil.WriteFile(sums.String(), []byte(sha("hello")+"  a.txt\nthis is not a checksum\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyChecksums(sums, root); err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("Malformed line should be reported with line number: %v", err)
+	}
+	if _, err := VerifyChecksums(root.Join("missing"), root); err == nil {
+		t.Errorf("Error should occur for missing checksum file")
+	}
+}
+
+func TestParseChecksumLine(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	for _, tc := range []struct {
+		line string
+		algo string
+		sum  string
+		name string
+		ok   bool
+	}{
+		{"SHA256 (a.txt) = " + sum, "sha256", sum, "a.txt", true},
+		{"BLAKE2b (a (1).txt) = " + sum, "blake2b-512", sum, "a (1).txt", true},
+		{sum + "  foo (1) = x", "sha256", sum, "foo (1) = x", true},
+		{sum + " *SHA256 (a.txt) = x", "sha256", sum, "SHA256 (a.txt) = x", true},
+		{"UNKNOWN (a.txt) = " + sum, "", "", "", false},
+		{"SHA256 (a.txt) = not-hex", "", "", "", false},
+		{"SHA256 (a.txt) = ", "", "", "", false},
+	} {
+		algo, sum, name, ok := parseChecksumLine(tc.line)
+		if algo != tc.algo || sum != tc.sum || name != tc.name || ok != tc.ok {
+			t.Errorf("Unexpected result for %q: %q %q %q %v", tc.line, algo, sum, name, ok)
+		}
+	}
+}
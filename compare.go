@@ -0,0 +1,213 @@
+package abspath
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// compareChunkSize is a size of chunk read from each file at once when comparing contents.
+const compareChunkSize = 64 * 1024
+
+// ContentEqual returns whether contents of the file at the path and the file at the other path are the same.
+// When both paths point to the same file, it returns true without reading them.  When their sizes differ, it returns
+// false without reading them.  Otherwise contents are compared chunk by chunk and it returns at the first difference.
+// Both paths must point to regular files.  Otherwise it returns an error.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/src")
+//	b, _ := abspath.New("/path/to/dst")
+//	same, err := a.ContentEqual(b)
+func (a AbsPath) ContentEqual(b AbsPath) (bool, error) {
+	return a.ContentEqualContext(context.Background(), b)
+}
+
+// ContentEqualContext is the same as ContentEqual() but accepts a context.  Comparing is stopped between chunks when
+// the context is done.
+func (a AbsPath) ContentEqualContext(ctx context.Context, b AbsPath) (_ bool, err error) {
+	defer annotate(&err, "compare", a, b)
+	sa, err := fsys().Stat(a.underlying)
+	if err != nil {
+		return false, err
+	}
+	sb, err := fsys().Stat(b.underlying)
+	if err != nil {
+		return false, err
+	}
+	for _, s := range []os.FileInfo{sa, sb} {
+		if !s.Mode().IsRegular() {
+			return false, fmt.Errorf("cannot compare content of '%s' since it is not a regular file", s.Name())
+		}
+	}
+
+	if os.SameFile(sa, sb) {
+		return true, nil
+	}
+	if sa.Size() != sb.Size() {
+		return false, nil
+	}
+
+	fa, err := fsys().Open(a.underlying)
+	if err != nil {
+		return false, err
+	}
+	defer fa.Close()
+	fb, err := fsys().Open(b.underlying)
+	if err != nil {
+		return false, err
+	}
+	defer fb.Close()
+
+	ba := make([]byte, compareChunkSize)
+	bb := make([]byte, compareChunkSize)
+	for {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		na, erra := io.ReadFull(fa, ba)
+		nb, errb := io.ReadFull(fb, bb)
+		addBytes("compare", int64(na+nb))
+		if !bytes.Equal(ba[:na], bb[:nb]) {
+			return false, nil
+		}
+		doneA := erra == io.EOF || erra == io.ErrUnexpectedEOF
+		doneB := errb == io.EOF || errb == io.ErrUnexpectedEOF
+		if erra != nil && !doneA {
+			return false, erra
+		}
+		if errb != nil && !doneB {
+			return false, errb
+		}
+		if doneA || doneB {
+			// Sizes may be changed after stat
+			return doneA && doneB, nil
+		}
+	}
+}
+
+// containsPath returns whether child is parent or is under parent by comparing path components.
+func containsPath(parent, child AbsPath, fold bool) bool {
+	eq := func(x, y string) bool {
+		if fold {
+			return strings.EqualFold(x, y)
+		}
+		return x == y
+	}
+
+	// Volume names are case-insensitive on Windows
+	if !strings.EqualFold(filepath.VolumeName(parent.underlying), filepath.VolumeName(child.underlying)) {
+		return false
+	}
+
+	ps, cs := parent.Segments(), child.Segments()
+	if len(cs) < len(ps) {
+		return false
+	}
+	for i, s := range ps {
+		if !eq(s, cs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsPath returns whether the other path is the same as the path or is under the path.  Unlike HasPrefix(), paths
+// are compared by their components so '/foo' does not contain '/foobar'.  Components are compared in case-sensitive.
+// Use ContainsPathFold() for case-insensitive filesystems.  Components are compared as returned from Segments() so
+// redundant separators, '.' components and a trailing separator kept by NoClean() or KeepTrailingSeparator() option
+// are ignored.  However '..' components are compared as they are since it does not access the filesystem.
+//
+// Example:
+//	a, _ := abspath.New("/foo")
+//	b, _ := abspath.New("/foo/bar")
+//	c, _ := abspath.New("/foobar")
+//	a.ContainsPath(b) // => true
+//	a.ContainsPath(c) // => false
+func (a AbsPath) ContainsPath(b AbsPath) bool {
+	return containsPath(a, b, false)
+}
+
+// ContainsPathFold is the same as ContainsPath() but components are compared in case-insensitive.
+func (a AbsPath) ContainsPathFold(b AbsPath) bool {
+	return containsPath(a, b, true)
+}
+
+// IsDescendantOf returns whether the path is under the other path.  Unlike ContainsPath(), it returns false when both
+// paths are the same.  Components are compared in case-sensitive.  Use IsDescendantOfFold() for case-insensitive
+// filesystems.
+//
+// Example:
+//	a, _ := abspath.New("/foo/bar")
+//	b, _ := abspath.New("/foo")
+//	a.IsDescendantOf(b) // => true
+//	b.IsDescendantOf(b) // => false
+func (a AbsPath) IsDescendantOf(b AbsPath) bool {
+	return a.Depth() > b.Depth() && containsPath(b, a, false)
+}
+
+// IsDescendantOfFold is the same as IsDescendantOf() but components are compared in case-insensitive.
+func (a AbsPath) IsDescendantOfFold(b AbsPath) bool {
+	return a.Depth() > b.Depth() && containsPath(b, a, true)
+}
+
+// caseInsensitiveOS is true when the default filesystem of the platform is case-insensitive.
+const caseInsensitiveOS = runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+
+// Equal returns whether two paths are the same following the default rule of the platform.  Paths are compared in
+// case-insensitive on Windows and macOS, and in case-sensitive on other platforms.  Note that it does not access the
+// filesystem.  Use os.SameFile() to know whether two paths point to the same file actually.
+//
+// Example:
+//	a, _ := abspath.New(`C:\Users\foo`)
+//	b, _ := abspath.New(`c:\users\FOO`)
+//	a.Equal(b) // => true on Windows
+func (a AbsPath) Equal(b AbsPath) bool {
+	if caseInsensitiveOS {
+		return a.EqualFold(b)
+	}
+	return a.underlying == b.underlying
+}
+
+// EqualFold returns whether two paths are the same in case-insensitive.
+//
+// Example:
+//	a, _ := abspath.New("/foo/bar")
+//	b, _ := abspath.New("/FOO/Bar")
+//	a.EqualFold(b) // => true
+func (a AbsPath) EqualFold(b AbsPath) bool {
+	return strings.EqualFold(a.underlying, b.underlying)
+}
+
+// Key returns a representation of the path intended to be used as a key of maps or an identity of caches.  Two paths
+// which are equal in EqualNormalized() return the same key.  The path is normalized in NFC and case-folded on
+// case-insensitive platforms (Windows and macOS).  A trailing separator kept by KeepTrailingSeparator() or NoClean()
+// option is removed so that '/foo/' and '/foo' return the same key.  Note that it does not access the filesystem and
+// the returned string is not for displaying.  Use String() to get the path as the user spelled it.
+//
+// Example:
+//	a, _ := abspath.New(`C:\Users\Foo\`)
+//	m := map[string]int{a.Key(): 42}
+//	b, _ := abspath.New(`c:\users\foo`)
+//	m[b.Key()] // => 42 on Windows
+func (a AbsPath) Key() string {
+	k := trimTrailingSeparator(a.NormalizeUnicode(NFC).underlying)
+	if caseInsensitiveOS {
+		k = strings.ToLower(k)
+	}
+	return k
+}
+
+// trimTrailingSeparator removes trailing separators of the path.  The separator of the root directory is kept.
+func trimTrailingSeparator(p string) string {
+	vol := len(filepath.VolumeName(p))
+	i := len(p)
+	for i > vol+1 && os.IsPathSeparator(p[i-1]) {
+		i--
+	}
+	return p[:i]
+}
@@ -0,0 +1,170 @@
+package abspath
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContentEqual(t *testing.T) {
+	large := strings.Repeat("x", compareChunkSize*2+10)
+	root := makeTree(t, map[string]string{
+		"a":        "hello",
+		"b":        "hello",
+		"c":        "world",
+		"d":        "hello!",
+		"large1":   large,
+		"large2":   large,
+		"large3":   large[:len(large)-1] + "y",
+		"empty1":   "",
+		"empty2":   "",
+		"dir/file": "",
+	})
+
+	for _, c := range []struct {
+		lhs      string
+		rhs      string
+		expected bool
+	}{
+		{"a", "a", true},
+		{"a", "b", true},
+		{"a", "c", false},
+		{"a", "d", false},
+		{"large1", "large2", true},
+		{"large1", "large3", false},
+		{"empty1", "empty2", true},
+		{"empty1", "a", false},
+	} {
+		actual, err := root.Join(c.lhs).ContentEqual(root.Join(c.rhs))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if actual != c.expected {
+			t.Errorf("Expected %v for %s and %s but actually %v", c.expected, c.lhs, c.rhs, actual)
+		}
+	}
+
+	for _, c := range [][2]string{
+		{"a", "not-exist"},
+		{"not-exist", "a"},
+		{"a", "dir"},
+		{"dir", "a"},
+	} {
+		if _, err := root.Join(c[0]).ContentEqual(root.Join(c[1])); err == nil {
+			t.Errorf("Error was expected for %s and %s", c[0], c[1])
+		}
+	}
+}
+
+func TestContainsPath(t *testing.T) {
+	for _, c := range []struct {
+		parent     string
+		child      string
+		contains   bool
+		fold       bool
+		descendant bool
+	}{
+		{"/foo", "/foo/bar", true, true, true},
+		{"/foo", "/foo", true, true, false},
+		{"/foo", "/foobar", false, false, false},
+		{"/foo/bar", "/foo", false, false, false},
+		{"/", "/foo", true, true, true},
+		{"/", "/", true, true, false},
+		{"/foo", "/FOO/bar", false, true, false},
+		{"/foo/bar", "/foo/baz/bar", false, false, false},
+	} {
+		p, _ := FromSlash(fixAbsPath(c.parent))
+		ch, _ := FromSlash(fixAbsPath(c.child))
+		if r := p.ContainsPath(ch); r != c.contains {
+			t.Errorf("Expected %v for %s.ContainsPath(%s) but actually %v", c.contains, p, ch, r)
+		}
+		if r := p.ContainsPathFold(ch); r != c.fold {
+			t.Errorf("Expected %v for %s.ContainsPathFold(%s) but actually %v", c.fold, p, ch, r)
+		}
+		if r := ch.IsDescendantOf(p); r != c.descendant {
+			t.Errorf("Expected %v for %s.IsDescendantOf(%s) but actually %v", c.descendant, ch, p, r)
+		}
+		if r := ch.IsDescendantOfFold(p); r != (c.fold && ch.Depth() > p.Depth()) {
+			t.Errorf("Unexpected result %v for %s.IsDescendantOfFold(%s)", r, ch, p)
+		}
+	}
+
+	if isWindows {
+		p, _ := New(`C:\foo`)
+		c, _ := New(`c:\foo\bar`)
+		if !p.ContainsPath(c) {
+			t.Errorf("Volume names should be compared in case-insensitive")
+		}
+		d, _ := New(`D:\foo\bar`)
+		if p.ContainsPath(d) {
+			t.Errorf("Paths on different volumes should not contain each other")
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/foo/bar"))
+	b, _ := FromSlash(fixAbsPath("/foo/bar"))
+	c, _ := FromSlash(fixAbsPath("/FOO/Bar"))
+	d, _ := FromSlash(fixAbsPath("/foo/baz"))
+
+	if !a.Equal(b) || !a.EqualFold(b) {
+		t.Errorf("%s and %s should be equal", a, b)
+	}
+	if a.Equal(d) || a.EqualFold(d) {
+		t.Errorf("%s and %s should not be equal", a, d)
+	}
+	if !a.EqualFold(c) {
+		t.Errorf("%s and %s should be equal in case-insensitive", a, c)
+	}
+	if a.Equal(c) != caseInsensitiveOS {
+		t.Errorf("Unexpected result of Equal() for %s and %s", a, c)
+	}
+}
+
+func TestKey(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/foo/café"))
+	b, _ := FromSlash(fixAbsPath("/foo/café/"))
+	c, _ := FromSlash(fixAbsPath("/FOO/Café"))
+	d, _ := FromSlash(fixAbsPath("/foo/cafe"))
+
+	if a.Key() != b.Key() {
+		t.Errorf("Keys of %q and %q should be equal: %q vs %q", a, b, a.Key(), b.Key())
+	}
+	if a.Key() == d.Key() {
+		t.Errorf("Keys of %q and %q should not be equal", a, d)
+	}
+	if (a.Key() == c.Key()) != caseInsensitiveOS {
+		t.Errorf("Unexpected key equality for %q and %q: %q vs %q", a, c, a.Key(), c.Key())
+	}
+	if a.String() != filepath.FromSlash(fixAbsPath("/foo/café")) {
+		t.Errorf("String() should preserve the original spelling: %q", a.String())
+	}
+}
+
+func TestKeyTrailingSeparator(t *testing.T) {
+	a, err := NewWithOptions(filepath.FromSlash(fixAbsPath("/foo")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opt := range []Option{KeepTrailingSeparator(), NoClean()} {
+		for _, s := range []string{"/foo/", "/foo//"} {
+			b, err := NewWithOptions(filepath.FromSlash(fixAbsPath(s)), opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Key() != b.Key() {
+				t.Errorf("Keys of %q and %q should be equal: %q vs %q", a, b, a.Key(), b.Key())
+			}
+		}
+	}
+
+	r, err := NewWithOptions(filepath.FromSlash(fixAbsPath("/")), KeepTrailingSeparator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k := r.Key(); k != filepath.FromSlash(fixAbsPath("/")) {
+		t.Errorf("Separator of root directory should be kept: %q", k)
+	}
+}
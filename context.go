@@ -0,0 +1,163 @@
+package abspath
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ioChunkSize is a size of chunk read or written at once by methods accepting a context.  The context is checked
+// between chunks.
+const ioChunkSize = 64 * 1024
+
+// contextReader is a reader which stops reading with the error of the context when it is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(b []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if len(b) > ioChunkSize {
+		b = b[:ioChunkSize]
+	}
+	return r.r.Read(b)
+}
+
+// ReadFile reads the whole content of the file at the path as ioutil.ReadFile().
+//
+// Example:
+//	a, _ := abspath.New("/path/to/file")
+//	b, err := a.ReadFile()
+func (a AbsPath) ReadFile() ([]byte, error) {
+	return a.ReadFileContext(context.Background())
+}
+
+// ReadFileContext is the same as ReadFile() but accepts a context.  The file is read chunk by chunk and reading is
+// stopped with the error of the context when the context is done.
+//
+// Example:
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		b, err := report.ReadFileContext(r.Context())
+//		if err != nil {
+//			http.Error(w, err.Error(), http.StatusInternalServerError)
+//			return
+//		}
+//		w.Write(b)
+//	}
+func (a AbsPath) ReadFileContext(ctx context.Context) (_ []byte, err error) {
+	defer annotate(&err, "read", a)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f, err := fsys().Open(a.underlying)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b, err := ioutil.ReadAll(contextReader{ctx, f})
+	addBytes("read", int64(len(b)))
+	return b, err
+}
+
+// WriteFile writes the data to the file at the path as ioutil.WriteFile().  When the file does not exist, it is created
+// with the permission (before the umask).
+//
+// Example:
+//	a, _ := abspath.New("/path/to/file")
+//	err := a.WriteFile([]byte("hello"), 0644)
+func (a AbsPath) WriteFile(data []byte, perm os.FileMode) error {
+	return a.WriteFileContext(context.Background(), data, perm)
+}
+
+// WriteFileContext is the same as WriteFile() but accepts a context.  The data is written chunk by chunk.  When the
+// context is done, writing is stopped and the partially written file is removed.
+func (a AbsPath) WriteFileContext(ctx context.Context, data []byte, perm os.FileMode) (err error) {
+	defer annotate(&err, "write", a)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f, err := fsys().OpenFile(a.underlying, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			fsys().Remove(a.underlying)
+		}
+	}()
+
+	for len(data) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := len(data)
+		if n > ioChunkSize {
+			n = ioChunkSize
+		}
+		w, err := f.Write(data[:n])
+		addBytes("write", int64(w))
+		if err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return f.Close()
+}
+
+// RemoveAllContext removes the path and all its children as os.RemoveAll() does but accepts a context.  Entries are
+// removed one by one and removing is stopped when the context is done.  Entries not removed before the cancellation
+// are left.  When the path does not exist, it returns nil.
+//
+// Example:
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	err := cacheDir.RemoveAllContext(ctx)
+func (a AbsPath) RemoveAllContext(ctx context.Context) (err error) {
+	defer annotate(&err, "remove", a)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s, err := fsys().Lstat(a.underlying)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return removeAllContext(ctx, a.underlying, s)
+}
+
+func removeAllContext(ctx context.Context, p string, info os.FileInfo) error {
+	if info.IsDir() {
+		entries, err := fsys().ReadDir(p)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for _, e := range entries {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			child := filepath.Join(p, e.Name())
+			s, err := fsys().Lstat(child)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return err
+			}
+			if err := removeAllContext(ctx, child, s); err != nil {
+				return err
+			}
+		}
+	}
+	if err := fsys().Remove(p); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package abspath
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	_ "crypto/sha256"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestReadWriteFileContext(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+	p := root.Join("a.txt")
+
+	data := bytes.Repeat([]byte("0123456789"), ioChunkSize/5)
+	if err := p.WriteFile(data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := p.ReadFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, data) {
+		t.Fatalf("Read content is different from written one (%d bytes vs %d bytes)", len(b), len(data))
+	}
+
+	if _, err := p.ReadFileContext(canceledContext()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Reading should be canceled but got %v", err)
+	}
+	if _, err := root.Join("missing").ReadFile(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
+
+func TestReadFileContextCanceledWhileReading(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": strings.Repeat("x", ioChunkSize*3)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	f, err := os.Open(root.Join("a.txt").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	r := contextReader{ctx, f}
+	buf := make([]byte, ioChunkSize*3)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n > ioChunkSize {
+		t.Errorf("At most one chunk should be read at once but got %d bytes", n)
+	}
+	cancel()
+	if _, err := r.Read(buf); err != context.Canceled {
+		t.Errorf("Reading should be canceled between chunks but got %v", err)
+	}
+}
+
+func TestWriteFileContextCanceled(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	p := root.Join("a.txt")
+
+	if err := p.WriteFileContext(canceledContext(), []byte("world"), 0644); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Writing should be canceled but got %v", err)
+	}
+	assertContent(t, p, "hello")
+}
+
+func TestRemoveAllContext(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"d/a.txt":     "a",
+		"d/e/b.txt":   "b",
+		"d/e/f/c.txt": "c",
+	})
+	d := root.Join("d")
+
+	if err := d.RemoveAllContext(canceledContext()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Removing should be canceled but got %v", err)
+	}
+	if _, err := os.Stat(d.String()); err != nil {
+		t.Fatalf("Directory should not be removed after cancellation: %v", err)
+	}
+
+	if err := d.RemoveAllContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(d.String()); !os.IsNotExist(err) {
+		t.Fatalf("Directory should be removed: %v", err)
+	}
+	if err := d.RemoveAllContext(context.Background()); err != nil {
+		t.Fatalf("Removing missing path should succeed: %v", err)
+	}
+}
+
+func TestRemoveAllContextDoesNotFollowSymlinks(t *testing.T) {
+	if isWindows {
+		t.Skip("Creating symbolic links requires privilege on Windows")
+	}
+	root := makeTree(t, map[string]string{"target/a.txt": "a", "d/b.txt": "b"})
+	if err := os.Symlink(root.Join("target").String(), root.Join("d", "link").String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.Join("d").RemoveAllContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("target", "a.txt"), "a")
+}
+
+func TestCopyContext(t *testing.T) {
+	root := makeTree(t, map[string]string{"src/a.txt": "a", "src/d/b.txt": "b", "file.txt": "f"})
+
+	if err := root.Join("src").Copy(root.Join("dst"), nil); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("dst", "a.txt"), "a")
+	assertContent(t, root.Join("dst", "d", "b.txt"), "b")
+
+	if err := root.Join("file.txt").Copy(root.Join("copied.txt"), nil); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("copied.txt"), "f")
+
+	err := root.Join("src").CopyContext(canceledContext(), root.Join("dst2"), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Copying should be canceled but got %v", err)
+	}
+}
+
+func TestWalkContext(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "a", "d/b.txt": "b"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	visited := []string{}
+	err := root.WalkContext(ctx, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, filepath.Base(p))
+		if len(visited) == 2 {
+			cancel()
+		}
+		return nil
+	}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Walking should be canceled but got %v", err)
+	}
+	if len(visited) != 2 {
+		t.Errorf("Walking should stop after cancellation but visited %v", visited)
+	}
+}
+
+func TestCountHashCompareContext(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello", "b.txt": "hello"})
+	ctx := canceledContext()
+
+	if _, err := root.CountContext(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Counting should be canceled but got %v", err)
+	}
+	if _, err := root.Join("a.txt").HashContext(ctx, crypto.SHA256); !errors.Is(err, context.Canceled) {
+		t.Errorf("Hashing should be canceled but got %v", err)
+	}
+	if _, err := root.Join("a.txt").ContentEqualContext(ctx, root.Join("b.txt")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Comparing should be canceled but got %v", err)
+	}
+
+	s, err := root.CountContext(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Files != 2 {
+		t.Errorf("Wanted 2 files but got %+v", s)
+	}
+}
+
+func TestSyncContext(t *testing.T) {
+	root := makeTree(t, map[string]string{"src/a.txt": "a"})
+	src, dst := root.Join("src"), root.Join("dst")
+
+	if _, err := SyncContext(canceledContext(), src, dst, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Syncing should be canceled but got %v", err)
+	}
+	if _, err := os.Stat(dst.String()); !os.IsNotExist(err) {
+		t.Errorf("Nothing should be synced after cancellation: %v", err)
+	}
+
+	if _, err := SyncContext(context.Background(), src, dst, nil); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(dst.Join("a.txt").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "a" {
+		t.Errorf("Unexpected content %q", b)
+	}
+}
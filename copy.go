@@ -0,0 +1,467 @@
+package abspath
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// copyBufferSize is a size of chunk to copy file contents.  Cancellation and progress are checked per chunk.
+const copyBufferSize = 64 * 1024
+
+// CopyProgress is a progress of copy reported to CopyOptions.Progress callback.
+type CopyProgress struct {
+	// File is the source file currently being copied.
+	File AbsPath
+	// FileCopied is the number of bytes of the current file copied so far.
+	FileCopied int64
+	// FileSize is the size of the current file in bytes.
+	FileSize int64
+	// TotalCopied is the number of bytes copied so far in the whole operation.
+	TotalCopied int64
+}
+
+// CopyOptions is a set of options for CopyFile() and CopyDir() methods.
+type CopyOptions struct {
+	// Progress is called every time a chunk of file is copied.  It is also called when starting to copy each file with
+	// FileCopied set to zero.
+	Progress func(CopyProgress)
+	// Digest is a hash function to which contents of copied files are written while copying so that the digest can be
+	// calculated without reading the files again.  It is not reset between files.
+	Digest hash.Hash
+	// Filter is called for each entry under the source directory by CopyDir() before copying it.  src is the path of
+	// the entry in the source directory and d is its directory entry.  The returned decision tells how to copy the
+	// entry.  When it returns an error, copying stops with the error.  It is not called for the source directory itself
+	// and is ignored by CopyFile().
+	Filter func(src AbsPath, d fs.DirEntry) (CopyDecision, error)
+	// RateLimiter limits the rate of copying.  Each entry copied by CopyDir() is counted as one operation and bytes
+	// are counted while reading source files.  When it is nil, the rate is not limited.
+	RateLimiter *RateLimiter
+	// Bandwidth limits the throughput of copying to the number of bytes per second (e.g. 20*1024*1024 for 20MiB/s).
+	// Unlike RateLimiter, the limit is applied only to this copy.  Bursts up to one second of the throughput are allowed.
+	// When it is zero, the throughput is not limited.
+	Bandwidth int64
+	// Verify makes copying re-read each copied file and compare its digest calculated with the hash function against
+	// the digest of the written content.  On mismatch, the destination file is removed and *VerifyError is returned.
+	// The package implementing the hash function must be linked into the binary (e.g. by importing crypto/sha256).
+	// When it is zero, copied files are not verified.
+	Verify crypto.Hash
+	// Symlinks is the policy of how CopyDir() handles symbolic links under the source directory.  By default, they are
+	// copied as symbolic links with the same targets.
+	Symlinks SymlinkPolicy
+}
+
+// VerifyError is an error returned when the content of a copied file does not match the content written to it.  It is
+// returned when CopyOptions.Verify is set.
+type VerifyError struct {
+	// Src is the source file.
+	Src AbsPath
+	// Dst is the destination file.
+	Dst AbsPath
+	// Hash is the hash function used for the verification.
+	Hash crypto.Hash
+	// Want is the digest of the content written to the destination.
+	Want []byte
+	// Got is the digest of the content read from the destination after writing.
+	Got []byte
+}
+
+func (err *VerifyError) Error() string {
+	return fmt.Sprintf("Verification of copying '%s' to '%s' failed: digest %x was expected but got %x",
+		err.Src.underlying, err.Dst.underlying, err.Want, err.Got)
+}
+
+// CopyDecision is a decision returned from CopyOptions.Filter callback.  The zero value means copying the entry as it
+// is.
+//
+// Example:
+//	err := tmpl.CopyDir(dst, &abspath.CopyOptions{
+//		Filter: func(src abspath.AbsPath, d fs.DirEntry) (abspath.CopyDecision, error) {
+//			switch {
+//			case d.Name() == ".git":
+//				return abspath.CopyDecision{Skip: true}, nil
+//			case src.Ext() == ".tmpl":
+//				return abspath.CopyDecision{
+//					Name: src.TrimExt().Base().String(),
+//					Transform: func(w io.Writer, r io.Reader) error {
+//						return render(w, r, params)
+//					},
+//				}, nil
+//			}
+//			return abspath.CopyDecision{}, nil
+//		},
+//	})
+type CopyDecision struct {
+	// Skip makes the entry not copied.  When the entry is a directory, entries under it are not copied either.
+	Skip bool
+	// Name is a new name of the entry in the destination.  Entries under a renamed directory are copied into the
+	// renamed directory.  It must be a base name without path separators.  When it is empty, the name is not changed.
+	Name string
+	// Transform converts the content of a regular file while copying.  It reads the source content from r and writes
+	// converted content to w.  It is ignored for directories and symbolic links.
+	Transform func(w io.Writer, r io.Reader) error
+}
+
+// copier holds a state while copying files.
+type copier struct {
+	ctx       context.Context
+	opts      *CopyOptions
+	total     int64
+	bandwidth *RateLimiter
+}
+
+func newCopier(ctx context.Context, opts *CopyOptions) *copier {
+	if opts == nil {
+		opts = &CopyOptions{}
+	}
+	c := &copier{ctx: ctx, opts: opts}
+	if opts.Bandwidth > 0 {
+		c.bandwidth = NewRateLimiter(0, float64(opts.Bandwidth))
+	}
+	return c
+}
+
+func (c *copier) report(src string, copied, size int64) {
+	if c.opts.Progress != nil {
+		c.opts.Progress(CopyProgress{AbsPath{src}, copied, size, c.total})
+	}
+}
+
+// copyFile copies the regular file at src to dst.  Permission bits and modification time of the source file are
+// preserved.  When dst already exists, it is overwritten.  When copying fails in the middle, dst is removed.
+func (c *copier) copyFile(src, dst string, info os.FileInfo, transform func(io.Writer, io.Reader) error) (err error) {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+	if h := c.opts.Verify; h != 0 && !h.Available() {
+		return fmt.Errorf("hash function %v is not available. import the package implementing it", h)
+	}
+
+	size := info.Size()
+	var copied int64
+	if size >= largeFileSpanSize {
+		attrs := []SpanAttr{{"abspath.path", src}, {"abspath.dst", dst}, int64Attr("abspath.size", size)}
+		_, span := startSpan(c.ctx, "abspath.CopyFile", attrs...)
+		defer func() {
+			span.SetAttributes(int64Attr("abspath.bytes", copied))
+			span.End(err)
+		}()
+	}
+
+	// Opening dst truncates src when they are the same file
+	if src == dst {
+		return fmt.Errorf("cannot copy '%s' to itself", src)
+	}
+	if s, err := fsys().Stat(dst); err == nil && os.SameFile(info, s) {
+		return fmt.Errorf("cannot copy '%s' to '%s' since they are the same file", src, dst)
+	}
+
+	r, err := fsys().Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := fsys().OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			w.Close()
+			fsys().Remove(dst)
+		}
+	}()
+
+	in := c.bandwidth.reader(c.ctx, c.opts.RateLimiter.reader(c.ctx, r))
+	var out io.Writer = w
+	if c.opts.Digest != nil {
+		out = NewDigestWriter(out, c.opts.Digest)
+	}
+	var verifier *DigestWriter
+	if c.opts.Verify != 0 {
+		verifier = NewDigestWriter(out, c.opts.Verify.New())
+		out = verifier
+	}
+
+	c.report(src, copied, size)
+	if transform != nil {
+		pw := &progressWriter{c, out, src, size, 0}
+		if err := transform(pw, in); err != nil {
+			return err
+		}
+		return c.finishFile(w, src, dst, info, verifier)
+	}
+	buf := make([]byte, copyBufferSize)
+	for {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		n, rerr := in.Read(buf)
+		if n > 0 {
+			if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+			copied += int64(n)
+			addBytes("copy", int64(n))
+			c.total += int64(n)
+			c.report(src, copied, size)
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return rerr
+		}
+	}
+	return c.finishFile(w, src, dst, info, verifier)
+}
+
+// finishFile closes the destination file, copies the metadata of the source file and verifies the content written via
+// the verifier.  verifier is nil when verification is not necessary.
+func (c *copier) finishFile(w File, src, dst string, info os.FileInfo, verifier *DigestWriter) error {
+	if err := w.Close(); err != nil {
+		return err
+	}
+	// When the file already existed, OpenFile() does not change its permission
+	if err := fsys().Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := fsys().Chtimes(dst, info.ModTime(), info.ModTime()); err != nil {
+		return err
+	}
+	if verifier == nil {
+		return nil
+	}
+
+	want := verifier.Sum()
+	got, _, err := hashFile(c.ctx, dst, c.opts.Verify.New(), c.opts.RateLimiter)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(want, got) {
+		return &VerifyError{AbsPath{src}, AbsPath{dst}, c.opts.Verify, want, got}
+	}
+	return nil
+}
+
+// copyFile copies the regular file at src to dst without progress.
+func copyFile(ctx context.Context, src, dst string, info os.FileInfo) error {
+	return newCopier(ctx, nil).copyFile(src, dst, info, nil)
+}
+
+// progressWriter is a writer which reports progress of writing a transformed file.
+type progressWriter struct {
+	c      *copier
+	w      io.Writer
+	src    string
+	size   int64
+	copied int64
+}
+
+func (w *progressWriter) Write(b []byte) (int, error) {
+	if err := w.c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := w.w.Write(b)
+	w.copied += int64(n)
+	addBytes("copy", int64(n))
+	w.c.total += int64(n)
+	w.c.report(w.src, w.copied, w.size)
+	return n, err
+}
+
+// decide calls the filter callback and returns the decision for the entry.
+func (c *copier) decide(p string, info os.FileInfo) (CopyDecision, error) {
+	if c.opts.Filter == nil {
+		return CopyDecision{}, nil
+	}
+	d, err := c.opts.Filter(AbsPath{p}, fs.FileInfoToDirEntry(info))
+	if err != nil {
+		return CopyDecision{}, err
+	}
+	if d.Name == "." || d.Name == ".." || strings.IndexFunc(d.Name, isSeparatorRune) >= 0 {
+		return CopyDecision{}, fmt.Errorf("invalid name %q to copy '%s'", d.Name, p)
+	}
+	return d, nil
+}
+
+// createdDir is a directory created while copying a directory tree.  info is the source directory.
+type createdDir struct {
+	path string
+	info os.FileInfo
+}
+
+// restoreDirs applies permission bits and modification times of the source directories to the created directories.  It
+// must be called after all entries are written since directories are created with 0700 to write entries in them even
+// if the source directories are read-only.  Children are restored before their parents.
+func restoreDirs(dirs []createdDir) error {
+	for i := len(dirs) - 1; i >= 0; i-- {
+		d := dirs[i]
+		if err := fsys().Chmod(d.path, d.info.Mode().Perm()); err != nil {
+			return err
+		}
+		if err := fsys().Chtimes(d.path, d.info.ModTime(), d.info.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyDir copies the directory tree at src to dst.
+func (c *copier) copyDir(src, dst string) error {
+	// Destinations of directories, which may be renamed by the filter
+	dirs := map[string]string{src: dst}
+	var created []createdDir
+	w := walk
+	if c.opts.Symlinks.follows() {
+		w = walkFollow
+	}
+	err := w(src, c.opts.RateLimiter.walkFunc(c.ctx, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+
+		to := dst
+		var d CopyDecision
+		if p != src {
+			d, err = c.decide(p, info)
+			if err != nil {
+				return err
+			}
+			if d.Skip {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			name := d.Name
+			if name == "" {
+				name = filepath.Base(p)
+			}
+			to = filepath.Join(dirs[filepath.Dir(p)], name)
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			dirs[p] = to
+			created = append(created, createdDir{to, info})
+			return fsys().MkdirAll(to, 0700)
+		case mode&os.ModeSymlink != 0:
+			target, err := c.opts.Symlinks.target(p, src, dst)
+			if err != nil {
+				return err
+			}
+			if err := fsys().Remove(to); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			return fsys().Symlink(target, to)
+		case mode.IsRegular():
+			return c.copyFile(p, to, info, d.Transform)
+		default:
+			return fmt.Errorf("cannot copy '%s' since it is not a regular file, directory nor symbolic link", p)
+		}
+	}))
+	if err != nil {
+		return err
+	}
+	return restoreDirs(created)
+}
+
+// CopyFile copies the regular file at the path to dst.  When dst already exists, it is overwritten.  Permission bits
+// and modification time are preserved.  opts can be nil.
+//
+// Example:
+//	src, _ := abspath.New("/path/to/src")
+//	dst, _ := abspath.New("/path/to/dst")
+//	err := src.CopyFile(dst, nil)
+func (a AbsPath) CopyFile(dst AbsPath, opts *CopyOptions) error {
+	return a.CopyFileContext(context.Background(), dst, opts)
+}
+
+// CopyFileContext is the same as CopyFile() but accepts a context.  Copying is stopped and the partially written
+// destination file is removed when the context is done.
+func (a AbsPath) CopyFileContext(ctx context.Context, dst AbsPath, opts *CopyOptions) (err error) {
+	defer annotate(&err, "copy", a, dst)
+	s, err := fsys().Stat(a.underlying)
+	if err != nil {
+		return err
+	}
+	if !s.Mode().IsRegular() {
+		return fmt.Errorf("cannot copy '%s' since it is not a regular file", a.underlying)
+	}
+	return newCopier(ctx, opts).copyFile(a.underlying, dst.underlying, s, nil)
+}
+
+// CopyDir copies the directory tree at the path to dst recursively.  dst is created when it does not exist.  Existing
+// files in dst are overwritten.  Permission bits and modification times of files and directories are preserved.  By
+// default, symbolic links are copied as symbolic links.  It can be changed with Symlinks option.  Each entry can be
+// skipped, renamed or transformed with Filter option.  opts can be nil.
+//
+// Example:
+//	src, _ := abspath.ExpandFrom("~/Documents")
+//	dst, _ := abspath.New("/mnt/backup/Documents")
+//	err := src.CopyDir(dst, &abspath.CopyOptions{
+//		Progress: func(p abspath.CopyProgress) {
+//			fmt.Printf("\r%s: %d/%d bytes", p.File.Base(), p.FileCopied, p.FileSize)
+//		},
+//	})
+func (a AbsPath) CopyDir(dst AbsPath, opts *CopyOptions) error {
+	return a.CopyDirContext(context.Background(), dst, opts)
+}
+
+// CopyDirContext is the same as CopyDir() but accepts a context.  Copying is stopped when the context is done.  Files
+// copied before the cancellation are left in dst.
+func (a AbsPath) CopyDirContext(ctx context.Context, dst AbsPath, opts *CopyOptions) (err error) {
+	defer annotate(&err, "copy", a, dst)
+	ctx, span := startSpan(ctx, "abspath.CopyDir", pathAttr("abspath.path", a), pathAttr("abspath.dst", dst))
+	c := newCopier(ctx, opts)
+	defer func() {
+		span.SetAttributes(int64Attr("abspath.bytes", c.total))
+		span.End(err)
+	}()
+
+	s, err := fsys().Stat(a.underlying)
+	if err != nil {
+		return err
+	}
+	if !s.IsDir() {
+		return fmt.Errorf("cannot copy '%s' since it is not a directory", a.underlying)
+	}
+	return c.copyDir(a.underlying, dst.underlying)
+}
+
+// Copy copies the file or the directory tree at the path to dst.  It is the same as CopyDir() when the path is a
+// directory.  Otherwise it is the same as CopyFile().  opts can be nil.
+//
+// Example:
+//	src, _ := abspath.New("/path/to/src")
+//	dst, _ := abspath.New("/path/to/dst")
+//	err := src.Copy(dst, nil)
+func (a AbsPath) Copy(dst AbsPath, opts *CopyOptions) error {
+	return a.CopyContext(context.Background(), dst, opts)
+}
+
+// CopyContext is the same as Copy() but accepts a context.  Copying is stopped when the context is done as
+// CopyFileContext() and CopyDirContext() do.
+func (a AbsPath) CopyContext(ctx context.Context, dst AbsPath, opts *CopyOptions) (err error) {
+	defer annotate(&err, "copy", a, dst)
+	s, err := fsys().Stat(a.underlying)
+	if err != nil {
+		return err
+	}
+	if s.IsDir() {
+		return a.CopyDirContext(ctx, dst, opts)
+	}
+	return a.CopyFileContext(ctx, dst, opts)
+}
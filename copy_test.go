@@ -0,0 +1,409 @@
+package abspath
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	_ "crypto/sha256"
+	"errors"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopyFile(t *testing.T) {
+	content := strings.Repeat("x", copyBufferSize*2+1)
+	root := makeTree(t, map[string]string{"src": content, "dst": "old", "dir/": ""})
+	src := root.Join("src")
+
+	var calls int
+	var last CopyProgress
+	opts := &CopyOptions{
+		Progress: func(p CopyProgress) {
+			calls++
+			last = p
+		},
+	}
+
+	for _, name := range []string{"new", "dst"} {
+		calls = 0
+		dst := root.Join(name)
+		if err := src.CopyFile(dst, opts); err != nil {
+			t.Fatal(err)
+		}
+		assertContent(t, dst, content)
+		if calls != 4 {
+			t.Errorf("Expected progress callback is called 4 times but actually %d", calls)
+		}
+		size := int64(len(content))
+		if last.File != src || last.FileCopied != size || last.FileSize != size || last.TotalCopied != size {
+			t.Errorf("Unexpected last progress %+v", last)
+		}
+	}
+
+	if err := root.Join("dir").CopyFile(root.Join("foo"), nil); err == nil {
+		t.Errorf("Copying directory must cause an error")
+	}
+	if err := root.Join("not-exist").CopyFile(root.Join("foo"), nil); err == nil {
+		t.Errorf("Copying not existing file must cause an error")
+	}
+}
+
+func TestCopyFileSameFile(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	a := root.Join("a.txt")
+
+	if err := a.CopyFile(a, nil); err == nil {
+		t.Error("Copying file to itself should cause an error")
+	}
+	assertContent(t, a, "hello")
+
+	l := root.Join("link.txt")
+	if err := os.Link(a.String(), l.String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.CopyFile(l, nil); err == nil {
+		t.Error("Copying file to its hard link should cause an error")
+	}
+	assertContent(t, a, "hello")
+}
+
+func TestCopyFileContext(t *testing.T) {
+	content := strings.Repeat("x", copyBufferSize*3)
+	root := makeTree(t, map[string]string{"src": content})
+	dst := root.Join("dst")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := &CopyOptions{
+		Progress: func(p CopyProgress) {
+			if p.FileCopied > 0 {
+				cancel()
+			}
+		},
+	}
+	if err := root.Join("src").CopyFileContext(ctx, dst, opts); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation error but actually %v", err)
+	}
+	if _, err := os.Stat(dst.String()); !os.IsNotExist(err) {
+		t.Errorf("Partially copied file should be removed: %v", err)
+	}
+}
+
+func TestCopyDir(t *testing.T) {
+	src := makeTree(t, map[string]string{
+		"a":       "aaa",
+		"sub/b":   "bb",
+		"sub/c/d": "d",
+		"empty/":  "",
+	})
+	if !isWindows {
+		if err := os.Symlink("a", src.Join("link").String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dst := makeTree(t, map[string]string{"sub/b": "old"}).Join("copied")
+
+	var total int64
+	var files []string
+	opts := &CopyOptions{
+		Progress: func(p CopyProgress) {
+			total = p.TotalCopied
+			if p.FileCopied == 0 {
+				files = append(files, p.File.Base().String())
+			}
+		},
+	}
+	if err := src.CopyDir(dst, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	assertContent(t, dst.Join("a"), "aaa")
+	assertContent(t, dst.Join("sub", "b"), "bb")
+	assertContent(t, dst.Join("sub", "c", "d"), "d")
+	if s, err := os.Stat(dst.Join("empty").String()); err != nil || !s.IsDir() {
+		t.Errorf("Empty directory should be copied: %v", err)
+	}
+	if !isWindows {
+		l, err := os.Readlink(dst.Join("link").String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l != "a" {
+			t.Errorf("Expected symlink to 'a' but actually %q", l)
+		}
+	}
+	if total != 6 {
+		t.Errorf("Expected 6 bytes were copied in total but actually %d", total)
+	}
+	if strings.Join(files, ",") != "a,b,d" {
+		t.Errorf("Unexpected files were reported: %v", files)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := src.CopyDirContext(ctx, makeTree(t, nil), nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation error but actually %v", err)
+	}
+
+	if err := src.Join("a").CopyDir(dst, nil); err == nil {
+		t.Errorf("Copying file with CopyDir must cause an error")
+	}
+}
+
+// permFS denies creating entries in directories without write permission even when tests are run by root
+type permFS struct {
+	FS
+}
+
+func (f permFS) checkParent(name string) error {
+	if s, err := f.FS.Stat(filepath.Dir(name)); err == nil && s.Mode().Perm()&0200 == 0 {
+		return &os.PathError{Op: "create", Path: name, Err: os.ErrPermission}
+	}
+	return nil
+}
+
+func (f permFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if flag&os.O_CREATE != 0 {
+		if err := f.checkParent(name); err != nil {
+			return nil, err
+		}
+	}
+	return f.FS.OpenFile(name, flag, perm)
+}
+
+func (f permFS) Mkdir(name string, perm os.FileMode) error {
+	if err := f.checkParent(name); err != nil {
+		return err
+	}
+	return f.FS.Mkdir(name, perm)
+}
+
+func (f permFS) MkdirAll(name string, perm os.FileMode) error {
+	if _, err := f.FS.Stat(name); os.IsNotExist(err) {
+		if err := f.checkParent(name); err != nil {
+			return err
+		}
+	}
+	return f.FS.MkdirAll(name, perm)
+}
+
+func (f permFS) Symlink(oldname, newname string) error {
+	if err := f.checkParent(newname); err != nil {
+		return err
+	}
+	return f.FS.Symlink(oldname, newname)
+}
+
+func TestCopyDirReadOnlyDirectory(t *testing.T) {
+	if isWindows {
+		t.Skip("Permission bits of directories are not available on Windows")
+	}
+	src := makeTree(t, map[string]string{"ro/sub/a.txt": "a", "ro/b.txt": "b"})
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, p := range []AbsPath{src.Join("ro", "sub"), src.Join("ro")} {
+		if err := os.Chtimes(p.String(), mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(p.String(), 0555); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chmod(p.String(), 0755)
+	}
+	prev := SetFS(permFS{OSFS})
+	defer SetFS(prev)
+
+	dst := makeTree(t, nil)
+	for name, run := range map[string]func(d AbsPath) error{
+		"copied": func(d AbsPath) error { return src.CopyDir(d, nil) },
+		"synced": func(d AbsPath) error {
+			_, err := Sync(src, d, nil)
+			return err
+		},
+	} {
+		d := dst.Join(name)
+		if err := run(d); err != nil {
+			t.Fatal(err)
+		}
+		for _, p := range []AbsPath{d.Join("ro", "sub"), d.Join("ro")} {
+			defer os.Chmod(p.String(), 0755)
+		}
+		assertContent(t, d.Join("ro", "sub", "a.txt"), "a")
+		assertContent(t, d.Join("ro", "b.txt"), "b")
+		for _, p := range []AbsPath{d.Join("ro", "sub"), d.Join("ro")} {
+			assertPerm(t, p, 0555)
+			s, err := os.Stat(p.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !s.ModTime().Equal(mtime) {
+				t.Errorf("Modification time of %s should be %v but got %v", p, mtime, s.ModTime())
+			}
+		}
+	}
+}
+
+func TestCopyDirFilter(t *testing.T) {
+	src := makeTree(t, map[string]string{
+		"README.md.tmpl":      "# {{name}}",
+		"main.go":             "package main",
+		".git/HEAD":           "ref",
+		"tmpl_dir/a.txt":      "a",
+		"tmpl_dir/b.txt.tmpl": "{{name}}",
+		"skip.log":            "log",
+	})
+	dst := makeTree(t, nil).Join("project")
+
+	var total int64
+	opts := &CopyOptions{
+		Progress: func(p CopyProgress) { total = p.TotalCopied },
+		Filter: func(p AbsPath, d fs.DirEntry) (CopyDecision, error) {
+			switch name := d.Name(); {
+			case name == ".git" || strings.HasSuffix(name, ".log"):
+				return CopyDecision{Skip: true}, nil
+			case name == "tmpl_dir" && d.IsDir():
+				return CopyDecision{Name: "myapp"}, nil
+			case strings.HasSuffix(name, ".tmpl"):
+				return CopyDecision{
+					Name: strings.TrimSuffix(name, ".tmpl"),
+					Transform: func(w io.Writer, r io.Reader) error {
+						b, err := ioutil.ReadAll(r)
+						if err != nil {
+							return err
+						}
+						_, err = io.WriteString(w, strings.Replace(string(b), "{{name}}", "myapp", -1))
+						return err
+					},
+				}, nil
+			}
+			return CopyDecision{}, nil
+		},
+	}
+	if err := src.CopyDir(dst, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	assertContent(t, dst.Join("README.md"), "# myapp")
+	assertContent(t, dst.Join("main.go"), "package main")
+	assertContent(t, dst.Join("myapp", "a.txt"), "a")
+	assertContent(t, dst.Join("myapp", "b.txt"), "myapp")
+	for _, p := range []string{".git", "skip.log", "tmpl_dir", "README.md.tmpl", "myapp/b.txt.tmpl"} {
+		if _, err := os.Lstat(dst.Join(filepath.FromSlash(p)).String()); !os.IsNotExist(err) {
+			t.Errorf("%q should not be copied: %v", p, err)
+		}
+	}
+	if want := int64(len("# myapp") + len("package main") + len("a") + len("myapp")); total != want {
+		t.Errorf("Transformed bytes should be counted: want %d but got %d", want, total)
+	}
+
+	for _, name := range []string{"a/b", "..", "."} {
+		err := src.CopyDir(makeTree(t, nil), &CopyOptions{
+			Filter: func(AbsPath, fs.DirEntry) (CopyDecision, error) {
+				return CopyDecision{Name: name}, nil
+			},
+		})
+		if err == nil || !strings.Contains(err.Error(), "invalid name") {
+			t.Errorf("Invalid name %q should cause an error but got %v", name, err)
+		}
+	}
+
+	want := errors.New("filter error")
+	err := src.CopyDir(makeTree(t, nil), &CopyOptions{
+		Filter: func(AbsPath, fs.DirEntry) (CopyDecision, error) {
+			return CopyDecision{}, want
+		},
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("Error from filter should be returned but got %v", err)
+	}
+}
+
+func TestCopyBandwidth(t *testing.T) {
+	content := strings.Repeat("x", 3000)
+	root := makeTree(t, map[string]string{"src/a": content[:1000], "src/b": content[1000:]})
+	dst := root.Join("dst")
+
+	start := time.Now()
+	if err := root.Join("src").CopyDir(dst, &CopyOptions{Bandwidth: 2000}); err != nil {
+		t.Fatal(err)
+	}
+	// 2000 bytes are allowed as a burst and the rest 1000 bytes take 0.5 seconds
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("Copying 3000 bytes at 2000 bytes/sec should take 500ms but took %s", elapsed)
+	}
+	assertContent(t, dst.Join("a"), content[:1000])
+	assertContent(t, dst.Join("b"), content[1000:])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := root.Join("src", "b").CopyFileContext(ctx, root.Join("c"), &CopyOptions{Bandwidth: 1})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Copying should be stopped while waiting for bandwidth but got %v", err)
+	}
+	if _, err := os.Stat(root.Join("c").String()); !os.IsNotExist(err) {
+		t.Errorf("Partially copied file should be removed: %v", err)
+	}
+}
+
+// corruptingFS flips the first byte of every chunk written to files opened for writing
+type corruptingFS struct {
+	FS
+}
+
+type corruptingFile struct {
+	File
+}
+
+func (f corruptingFile) Write(b []byte) (int, error) {
+	c := append([]byte{}, b...)
+	if len(c) > 0 {
+		c[0] ^= 0xff
+	}
+	return f.File.Write(c)
+}
+
+func (f corruptingFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	file, err := f.FS.OpenFile(name, flag, perm)
+	if err != nil || flag&os.O_WRONLY == 0 {
+		return file, err
+	}
+	return corruptingFile{file}, nil
+}
+
+func TestCopyVerify(t *testing.T) {
+	root := makeTree(t, map[string]string{"src/a": "hello", "src/d/b": "world"})
+	src := root.Join("src")
+	opts := &CopyOptions{Verify: crypto.SHA256}
+
+	if err := src.CopyDir(root.Join("dst"), opts); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("dst", "d", "b"), "world")
+
+	prev := SetFS(corruptingFS{OSFS})
+	defer SetFS(prev)
+
+	dst := root.Join("corrupted")
+	err := src.Join("a").CopyFile(dst, opts)
+	var ve *VerifyError
+	if !errors.As(err, &ve) {
+		t.Fatalf("VerifyError should be returned but got %v", err)
+	}
+	if ve.Src != src.Join("a") || ve.Dst != dst || ve.Hash != crypto.SHA256 || bytes.Equal(ve.Want, ve.Got) {
+		t.Errorf("Unexpected error: %+v", ve)
+	}
+	if !strings.Contains(err.Error(), "Verification of copying") {
+		t.Errorf("Unexpected error message: %v", err)
+	}
+	if _, err := os.Stat(dst.String()); !os.IsNotExist(err) {
+		t.Errorf("Corrupted file should be removed: %v", err)
+	}
+
+	if err := src.Join("a").CopyFile(root.Join("c"), &CopyOptions{Verify: crypto.Hash(999)}); err == nil {
+		t.Error("Unavailable hash function should cause an error")
+	}
+}
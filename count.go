@@ -0,0 +1,110 @@
+package abspath
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+)
+
+// TreeStats is a result of Count() method.  It represents the numbers of entries in a directory tree.
+type TreeStats struct {
+	// Files is the number of regular files (and other non-directory, non-symlink entries).
+	Files int
+	// Dirs is the number of directories.  The root directory itself is not counted.
+	Dirs int
+	// Symlinks is the number of symbolic links.  Symbolic links are not followed.
+	Symlinks int
+	// Size is the total size of the counted files in bytes.
+	Size int64
+}
+
+// CountOptions is a set of options for Count() method.  Patterns are matched by path.Match() against both the
+// slash-separated path relative to the root and the base name of each entry.  Braces like '*.{go,mod}' in patterns are
+// expanded as ExpandBraces() does.
+type CountOptions struct {
+	// Include is a list of glob patterns.  When it is not empty, only files and symlinks matching one of them are counted.
+	Include []string
+	// Exclude is a list of glob patterns.  Matched entries are not counted and matched directories are not entered.
+	Exclude []string
+}
+
+// Count walks the directory tree rooted at the path and counts files, directories and symbolic links in one walk.
+// The total size of the counted files is also calculated.  When the path is not a directory, the path itself is counted.
+// opts can be nil.
+//
+// Example:
+//	a, _ := abspath.ExpandFrom("~/Documents")
+//	s, err := a.Count(&abspath.CountOptions{Exclude: []string{".git"}})
+//	if err != nil {
+//		panic(err)
+//	}
+//	fmt.Printf("%d files, %d bytes\n", s.Files, s.Size)
+func (a AbsPath) Count(opts *CountOptions) (TreeStats, error) {
+	return a.CountContext(context.Background(), opts)
+}
+
+// CountContext is the same as Count() but accepts a context.  Counting is stopped between entries when the context is
+// done.
+func (a AbsPath) CountContext(ctx context.Context, opts *CountOptions) (_ TreeStats, err error) {
+	defer annotate(&err, "count entries in", a)
+	if opts == nil {
+		opts = &CountOptions{}
+	}
+
+	var s TreeStats
+	err = walk(a.underlying, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		isRoot := p == a.underlying
+		if !isRoot {
+			rel, err := filepath.Rel(a.underlying, p)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+
+			m, err := matchPatterns(opts.Exclude, rel)
+			if err != nil {
+				return err
+			}
+			if m {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !info.IsDir() && len(opts.Include) > 0 {
+				m, err := matchPatterns(opts.Include, rel)
+				if err != nil {
+					return err
+				}
+				if !m {
+					return nil
+				}
+			}
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if !isRoot {
+				s.Dirs++
+			}
+		case mode&os.ModeSymlink != 0:
+			s.Symlinks++
+		default:
+			s.Files++
+			s.Size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return TreeStats{}, err
+	}
+	return s, nil
+}
@@ -0,0 +1,72 @@
+package abspath
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"a.txt":         "aaa",
+		"b.go":          "bb",
+		"sub/c.txt":     "c",
+		"sub/d/e.go":    "eeee",
+		"empty/":        "",
+		".git/config":   "xxxxxxxx",
+		".git/HEAD":     "yy",
+		"sub/.git/HEAD": "zz",
+	})
+
+	if !isWindows {
+		if err := os.Symlink(root.Join("a.txt").String(), root.Join("link").String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err := root.Count(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := TreeStats{Files: 7, Dirs: 5, Size: 22}
+	if !isWindows {
+		expected.Symlinks = 1
+	}
+	if s != expected {
+		t.Errorf("Expected %+v but actually %+v", expected, s)
+	}
+
+	s, err = root.Count(&CountOptions{Exclude: []string{".git"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = TreeStats{Files: 4, Dirs: 3, Size: 10, Symlinks: expected.Symlinks}
+	if s != expected {
+		t.Errorf("Expected %+v but actually %+v", expected, s)
+	}
+
+	s, err = root.Count(&CountOptions{Include: []string{"*.go"}, Exclude: []string{"sub/d"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = TreeStats{Files: 1, Dirs: 4, Size: 2}
+	if s != expected {
+		t.Errorf("Expected %+v but actually %+v", expected, s)
+	}
+
+	s, err = root.Join("a.txt").Count(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = TreeStats{Files: 1, Size: 3}
+	if s != expected {
+		t.Errorf("Expected %+v but actually %+v", expected, s)
+	}
+
+	if _, err := root.Count(&CountOptions{Exclude: []string{"["}}); err == nil {
+		t.Errorf("Malformed pattern must cause an error")
+	}
+
+	if _, err := root.Join("not-exist").Count(nil); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+}
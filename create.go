@@ -0,0 +1,98 @@
+package abspath
+
+import (
+	"os"
+)
+
+type createOptions struct {
+	exactPerm bool
+}
+
+// CreateOption is an option for Create() and CreateDir() methods.
+type CreateOption func(o *createOptions)
+
+// WithExactPerm makes Create() and CreateDir() change the permission to the given one after creating the file or the
+// directory.  Permissions passed to open(2) and mkdir(2) are masked by the umask of the process, so 0666 may result in
+// 0644.  This option defeats the umask.  The permission is also applied when the file or the directory already exists.
+func WithExactPerm() CreateOption {
+	return func(o *createOptions) {
+		o.exactPerm = true
+	}
+}
+
+// Create creates or truncates the file at the path and opens it for reading and writing as os.Create().  Unlike
+// os.Create(), the permission of a newly created file is the given one (before the umask).  The returned file must be
+// closed after use.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/script.sh")
+//	f, err := a.Create(0755, abspath.WithExactPerm())
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer f.Close()
+func (a AbsPath) Create(perm os.FileMode, opts ...CreateOption) (_ File, err error) {
+	defer annotate(&err, "create", a)
+	o := &createOptions{}
+	for _, f := range opts {
+		f(o)
+	}
+
+	f, err := fsys().OpenFile(a.underlying, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return nil, err
+	}
+	if o.exactPerm {
+		if err := fsys().Chmod(a.underlying, perm); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// CreateDir creates the directory at the path with the permission along with any necessary parents as os.MkdirAll().
+// When the directory already exists, it does nothing unless WithExactPerm() is given.  With WithExactPerm(), only the
+// directory at the path gets the exact permission.  Parents are created with the permission masked by the umask.
+//
+// Example:
+//	a, _ := abspath.New("/srv/shared/uploads")
+//	err := a.CreateDir(0775, abspath.WithExactPerm())
+func (a AbsPath) CreateDir(perm os.FileMode, opts ...CreateOption) (err error) {
+	defer annotate(&err, "create directory", a)
+	o := &createOptions{}
+	for _, f := range opts {
+		f(o)
+	}
+
+	if err := fsys().MkdirAll(a.underlying, perm); err != nil {
+		return err
+	}
+	if o.exactPerm {
+		return fsys().Chmod(a.underlying, perm)
+	}
+	return nil
+}
+
+// CreatePrivate is the same as Create(0600, WithExactPerm()).  The file is readable and writable only by the owner even
+// if it already existed with a wider permission.  It is useful to write secrets such as private keys and tokens.
+//
+// Example:
+//	key, _ := abspath.ExpandFrom("~/.config/myapp/token")
+//	f, err := key.CreatePrivate()
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer f.Close()
+func (a AbsPath) CreatePrivate() (File, error) {
+	return a.Create(0600, WithExactPerm())
+}
+
+// CreateDirPrivate is the same as CreateDir(0700, WithExactPerm()).  The directory is accessible only by the owner.
+//
+// Example:
+//	dir, _ := abspath.ExpandFrom("~/.ssh")
+//	err := dir.CreateDirPrivate()
+func (a AbsPath) CreateDirPrivate() error {
+	return a.CreateDir(0700, WithExactPerm())
+}
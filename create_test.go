@@ -0,0 +1,95 @@
+package abspath
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func assertPerm(t *testing.T, p AbsPath, want os.FileMode) {
+	t.Helper()
+	s, err := os.Stat(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if isWindows {
+		return
+	}
+	if have := s.Mode().Perm(); have != want {
+		t.Errorf("Permission of %s should be %o but got %o", p, want, have)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	root := makeTree(t, map[string]string{"existing.txt": "hello"})
+
+	p := root.Join("new.txt")
+	f, err := p.Create(0777, WithExactPerm())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("foo")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	assertPerm(t, p, 0777)
+	if b, _ := ioutil.ReadFile(p.String()); string(b) != "foo" {
+		t.Errorf("Unexpected content: %q", b)
+	}
+
+	e := root.Join("existing.txt")
+	if err := os.Chmod(e.String(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err = e.CreatePrivate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	assertPerm(t, e, 0600)
+	if b, _ := ioutil.ReadFile(e.String()); len(b) != 0 {
+		t.Errorf("Existing file should be truncated: %q", b)
+	}
+
+	f, err = e.Create(0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	assertPerm(t, e, 0600) // Permission of existing file is not changed without WithExactPerm
+
+	if _, err := root.Join("missing", "a.txt").Create(0644); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
+
+func TestCreateDir(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+
+	d := root.Join("a", "b", "c")
+	if err := d.CreateDir(0777, WithExactPerm()); err != nil {
+		t.Fatal(err)
+	}
+	assertPerm(t, d, 0777)
+
+	if err := d.CreateDirPrivate(); err != nil {
+		t.Fatal(err)
+	}
+	assertPerm(t, d, 0700)
+
+	if err := d.CreateDir(0755); err != nil {
+		t.Fatal(err)
+	}
+	assertPerm(t, d, 0700)
+
+	f := root.Join("file")
+	if err := ioutil.WriteFile(f.String(), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.CreateDirPrivate(); err == nil {
+		t.Error("Error should occur when a file exists at the path")
+	}
+}
@@ -0,0 +1,46 @@
+package abspath
+
+// DedupeOptions is a set of options for Dedupe() function.
+type DedupeOptions struct {
+	// RemoveCovered makes Dedupe() also remove paths which are under another path in the list.
+	RemoveCovered bool
+}
+
+// Dedupe removes duplicate paths from the list.  Paths are compared by their canonical forms returned from
+// Canonicalize() so that paths pointing to the same file via symbolic links, different casing or different Unicode
+// normalization (on platforms where they refer to the same file) are considered the same.  The first occurrence of duplicates is kept and the order of the list is
+// preserved.  Since it accesses the filesystem, all paths must exist.  It is useful to build a minimal set of paths to
+// watch or to back up.  opts can be nil.
+//
+// Example:
+//	ps, err := abspath.Dedupe(dirs, &abspath.DedupeOptions{RemoveCovered: true})
+func Dedupe(paths []AbsPath, opts *DedupeOptions) ([]AbsPath, error) {
+	if opts == nil {
+		opts = &DedupeOptions{}
+	}
+
+	keys := make([]AbsPath, 0, len(paths))
+	for _, p := range paths {
+		c, err := p.Canonicalize()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, c)
+	}
+
+	all := NewPathSet(keys...)
+	seen := &PathSet{}
+	ret := make([]AbsPath, 0, len(paths))
+	for i, p := range paths {
+		k := keys[i]
+		if seen.Contains(k) {
+			continue
+		}
+		if opts.RemoveCovered && !k.IsRoot() && all.ContainsOrParent(k.Dir()) {
+			continue
+		}
+		seen.Add(k)
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
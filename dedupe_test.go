@@ -0,0 +1,52 @@
+package abspath
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	root := makeTree(t, map[string]string{"a/b/c": "", "d/": "", "caf\u00e9/": ""})
+	root, err := root.EvalSymlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := root.Join("a")
+	b := root.Join("a", "b")
+	c := root.Join("a", "b", "c")
+	d := root.Join("d")
+	nfc := root.Join("caf\u00e9")
+	nfd := root.Join("cafe\u0301")
+
+	input := []AbsPath{d, a, b, d, c, a}
+	expected := []AbsPath{d, a, b, c}
+	if !isWindows {
+		l := root.Join("link-to-a")
+		if err := os.Symlink(a.String(), l.String()); err != nil {
+			t.Fatal(err)
+		}
+		input = append(input, l)
+	}
+	if _, err := os.Stat(nfd.String()); err == nil {
+		// Only when the filesystem does not distinguish NFC and NFD names (e.g. APFS)
+		input = append(input, nfc, nfd)
+		expected = append(expected, nfc)
+	}
+
+	ps, err := Dedupe(input, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPathsEqual(t, expected, ps)
+
+	ps, err = Dedupe([]AbsPath{c, b, d, a, b}, &DedupeOptions{RemoveCovered: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPathsEqual(t, []AbsPath{d, a}, ps)
+
+	if _, err := Dedupe([]AbsPath{a, root.Join("not-exist")}, nil); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+}
@@ -0,0 +1,150 @@
+package abspath
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DedupeMode is a mode of DedupeTree() which decides what is done for duplicate files.
+type DedupeMode int
+
+const (
+	// DedupeReportOnly only reports duplicate files without modifying them.  It is the default mode.
+	DedupeReportOnly DedupeMode = iota
+	// DedupeHardlink replaces duplicate files with hard links to the kept file.  Since the files share one inode, their
+	// permissions and modification times become the same.  All files must be on the same filesystem.
+	DedupeHardlink
+	// DedupeReflink replaces duplicate files with reflinks (copy-on-write clones) of the kept file.  Unlike hard links,
+	// the files stay independent and modifying one of them does not affect others.  Permission bits and modification
+	// times of the replaced files are preserved.  It is supported only on Linux (Btrfs, XFS and so on) and macOS (APFS)
+	// with the OS filesystem.
+	DedupeReflink
+)
+
+// DedupeTreeOptions is a set of options for DedupeTree() method.
+type DedupeTreeOptions struct {
+	// Mode is what is done for duplicate files.
+	Mode DedupeMode
+	// Hash is the hash function to compare file contents.  When it is zero, crypto.SHA256 is used.  The package
+	// implementing the hash function must be linked into the binary (e.g. by importing crypto/sha256).
+	Hash crypto.Hash
+	// MinSize is the minimum size of files to dedupe in bytes.  Empty files are always ignored.
+	MinSize int64
+	// Walk filters entries under the root.  It can be nil.
+	Walk *WalkOptions
+}
+
+// DedupeTreeResult is a report of DedupeTree() method.
+type DedupeTreeResult struct {
+	// Groups is a list of groups of files which have the same contents.  The first file of each group is kept and the
+	// rest are duplicates.
+	Groups [][]AbsPath
+	// Replaced is a list of duplicate files replaced with links.  It is empty with DedupeReportOnly mode.
+	Replaced []AbsPath
+	// Reclaimed is the number of bytes reclaimed by replacing duplicates.  With DedupeReportOnly mode, it is the number
+	// of bytes which would be reclaimed.
+	Reclaimed int64
+}
+
+// DedupeTree finds regular files which have the same contents under the directory tree at the path and replaces
+// duplicates with links to one of them depending on Mode option.  Candidates are narrowed down by file sizes before
+// hashing so that only files whose sizes collide are read.  Before replacing a duplicate, its content is compared with
+// the kept file byte by byte so that a hash collision never loses data.  Each duplicate is replaced atomically by
+// renaming a link created next to it.  Hard links to the same file are counted only once.  opts can be nil.
+//
+// Example:
+//	root, _ := abspath.New("/srv/media")
+//	res, err := root.DedupeTree(&abspath.DedupeTreeOptions{Mode: abspath.DedupeHardlink, MinSize: 4096})
+//	if err != nil {
+//		panic(err)
+//	}
+//	fmt.Println(res.Reclaimed, "bytes were reclaimed")
+func (a AbsPath) DedupeTree(opts *DedupeTreeOptions) (*DedupeTreeResult, error) {
+	return a.DedupeTreeContext(context.Background(), opts)
+}
+
+// DedupeTreeContext is the same as DedupeTree() but accepts a context.  Deduping is stopped between files and between
+// chunks of hashed files when the context is done.  Files replaced before the cancellation are left replaced.  When
+// deduping is stopped while replacing duplicates by the cancellation or an error, the result so far is returned with
+// the error.  Its Replaced and Reclaimed fields report the files already replaced.
+func (a AbsPath) DedupeTreeContext(ctx context.Context, opts *DedupeTreeOptions) (_ *DedupeTreeResult, err error) {
+	defer annotate(&err, "dedupe", a)
+	if opts == nil {
+		opts = &DedupeTreeOptions{}
+	}
+
+	var link func(src, dst string, info os.FileInfo) error
+	switch opts.Mode {
+	case DedupeHardlink:
+		l, ok := hardLinkFunc()
+		if !ok {
+			return nil, &os.PathError{Op: "link", Path: a.underlying, Err: ErrUnsupported}
+		}
+		link = func(src, dst string, _ os.FileInfo) error {
+			return audit("link", "target="+src, func() error { return l(src, dst) }, dst)
+		}
+	case DedupeReflink:
+		if !usesOSFS() {
+			return nil, &os.PathError{Op: "reflink", Path: a.underlying, Err: ErrUnsupported}
+		}
+		link = func(src, dst string, info os.FileInfo) error {
+			return audit("reflink", "target="+src, func() error {
+				if err := reflink(src, dst, info.Mode().Perm()); err != nil {
+					return err
+				}
+				return os.Chtimes(dst, info.ModTime(), info.ModTime())
+			}, dst)
+		}
+	}
+
+	groups, err := findDuplicates(ctx, []AbsPath{a}, &dupSearch{opts.Walk, opts.Hash, opts.MinSize})
+	if err != nil {
+		return nil, err
+	}
+
+	r := &DedupeTreeResult{Groups: make([][]AbsPath, 0, len(groups))}
+	for _, g := range groups {
+		r.Groups = append(r.Groups, dupPaths(g))
+
+		keep := g[0]
+		for _, f := range g[1:] {
+			if link == nil {
+				r.Reclaimed += f.info.Size()
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return r, err
+			}
+			eq, err := keep.path.ContentEqualContext(ctx, f.path)
+			if err != nil {
+				return r, err
+			}
+			if !eq {
+				continue
+			}
+			if err := replaceWithLink(keep.path.underlying, f, link); err != nil {
+				return r, err
+			}
+			r.Replaced = append(r.Replaced, f.path)
+			r.Reclaimed += f.info.Size()
+		}
+	}
+	return r, nil
+}
+
+// replaceWithLink creates a link to src next to the duplicate file and renames it to the duplicate atomically.
+func replaceWithLink(src string, dup dupFile, link func(src, dst string, info os.FileInfo) error) error {
+	dst := dup.path.underlying
+	tmp := fmt.Sprintf("%s.dedupe-%d-%d", dst, os.Getpid(), time.Now().UnixNano())
+	if err := link(src, tmp, dup.info); err != nil {
+		return err
+	}
+	if err := fsys().Rename(tmp, dst); err != nil {
+		fsys().Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func makeDupTree(t *testing.T) AbsPath {
+	root := makeTree(t, map[string]string{
+		"a.txt":   "hello",
+		"c.txt":   "world",
+		"d/b.txt": "hello",
+		"d/w.txt": "world",
+		"e.txt":   "hello!",
+		"f.txt":   "",
+		"g.txt":   "",
+	})
+	// Hard links to the same file are not duplicates
+	if err := os.Link(root.Join("e.txt").String(), root.Join("d", "e.txt").String()); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestDedupeTreeReportOnly(t *testing.T) {
+	root := makeDupTree(t)
+
+	res, err := root.DedupeTree(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]AbsPath{
+		{root.Join("a.txt"), root.Join("d", "b.txt")},
+		{root.Join("c.txt"), root.Join("d", "w.txt")},
+	}
+	if !reflect.DeepEqual(res.Groups, want) {
+		t.Errorf("Wanted groups %v but got %v", want, res.Groups)
+	}
+	if len(res.Replaced) != 0 || res.Reclaimed != 10 {
+		t.Errorf("Nothing should be replaced and 10 bytes should be reclaimable: %+v", res)
+	}
+	if s, err := os.Lstat(root.Join("d", "b.txt").String()); err != nil || s.Size() != 5 {
+		t.Errorf("Files should not be modified: %v", err)
+	}
+
+	res, err = root.DedupeTree(&DedupeTreeOptions{MinSize: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Groups) != 0 {
+		t.Errorf("Small files should be ignored: %v", res.Groups)
+	}
+}
+
+func TestDedupeTreeHardlink(t *testing.T) {
+	root := makeDupTree(t)
+
+	res, err := root.DedupeTree(&DedupeTreeOptions{Mode: DedupeHardlink})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []AbsPath{root.Join("d", "b.txt"), root.Join("d", "w.txt")}; !reflect.DeepEqual(res.Replaced, want) {
+		t.Errorf("Wanted replaced files %v but got %v", want, res.Replaced)
+	}
+	if res.Reclaimed != 10 {
+		t.Errorf("10 bytes should be reclaimed but got %d", res.Reclaimed)
+	}
+	for _, pair := range [][2]string{{"a.txt", "d/b.txt"}, {"c.txt", "d/w.txt"}} {
+		s, err := os.Stat(root.Join(pair[0]).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := os.Stat(root.Join(pair[1]).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !os.SameFile(s, d) {
+			t.Errorf("%q should be hard-linked to %q", pair[1], pair[0])
+		}
+	}
+	assertContent(t, root.Join("d", "b.txt"), "hello")
+
+	res, err = root.DedupeTree(&DedupeTreeOptions{Mode: DedupeHardlink})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Groups) != 0 {
+		t.Errorf("Deduped files should not be reported again: %v", res.Groups)
+	}
+}
+
+func TestDedupeTreeReflink(t *testing.T) {
+	root := makeDupTree(t)
+
+	res, err := root.DedupeTree(&DedupeTreeOptions{Mode: DedupeReflink})
+	if ClassifyError(err) == ErrorKindUnsupported {
+		t.Skip("Reflink is not supported:", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Replaced) != 2 || res.Reclaimed != 10 {
+		t.Errorf("2 files should be replaced: %+v", res)
+	}
+	assertContent(t, root.Join("d", "b.txt"), "hello")
+}
+
+func TestDedupeTreeUnsupportedFS(t *testing.T) {
+	root := makeDupTree(t)
+
+	prev := SetFS(&recordingFS{FS: OSFS})
+	defer SetFS(prev)
+
+	for _, m := range []DedupeMode{DedupeHardlink, DedupeReflink} {
+		if _, err := root.DedupeTree(&DedupeTreeOptions{Mode: m}); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Unsupported error should be returned for mode %d but got %v", m, err)
+		}
+	}
+}
+
+func TestDedupeTreePartialResult(t *testing.T) {
+	if isWindows {
+		t.Skip("Permission bits of directories are not available on Windows")
+	}
+	root := makeTree(t, map[string]string{"a.txt": "hello", "b.txt": "hello", "ro/c.txt": "hello"})
+	ro := root.Join("ro")
+	if err := os.Chmod(ro.String(), 0555); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(ro.String(), 0755)
+	prev := SetFS(permLinkFS{permFS{OSFS}})
+	defer SetFS(prev)
+
+	res, err := root.DedupeTree(&DedupeTreeOptions{Mode: DedupeHardlink})
+	if !errors.Is(err, os.ErrPermission) {
+		t.Fatalf("Permission error should be returned but got %v", err)
+	}
+	if res == nil {
+		t.Fatal("Partial result should be returned with the error")
+	}
+	if want := []AbsPath{root.Join("b.txt")}; !reflect.DeepEqual(res.Replaced, want) || res.Reclaimed != 5 {
+		t.Errorf("Only %v should be replaced but got %+v", want, res)
+	}
+}
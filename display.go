@@ -0,0 +1,245 @@
+package abspath
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// abbreviate replaces the home directory prefix of the path with '~'.
+func (a AbsPath) abbreviate(home string) string {
+	if home == "" {
+		return a.underlying
+	}
+	h, err := New(home)
+	if err != nil {
+		return a.underlying
+	}
+	if !h.ContainsPath(a) && !(caseInsensitiveOS && h.ContainsPathFold(a)) {
+		return a.underlying
+	}
+	ss := a.Segments()[h.Depth():]
+	if len(ss) == 0 {
+		return "~"
+	}
+	return "~" + string(os.PathSeparator) + strings.Join(ss, string(os.PathSeparator))
+}
+
+// Abbreviate returns a string representation of the path whose home directory prefix is replaced with '~'.  It is an
+// inverse of ExpandFrom() and useful for human-facing output.  The home directory is obtained in the same way as
+// HomeDir().  When the path is not under the home directory or the home directory cannot be obtained, it returns the
+// same value as String().
+//
+// Example:
+//	a, _ := abspath.ExpandFrom("~/Documents/foo.txt")
+//	fmt.Println(a.Abbreviate()) // => "~/Documents/foo.txt"
+func (a AbsPath) Abbreviate() string {
+	h, err := homeDir()
+	if err != nil {
+		return a.underlying
+	}
+	return a.abbreviate(h)
+}
+
+// AbbreviateEnv is the same as Abbreviate() but the home directory is obtained from $HOME environment variable
+// (%USERPROFILE% on Windows and $home on Plan 9).  It is useful when the home directory is overridden by the
+// environment variable.
+func (a AbsPath) AbbreviateEnv() string {
+	return a.abbreviate(os.Getenv(homeEnv))
+}
+
+// ellipsis is a character to represent omitted components in Shorten().
+const ellipsis = "…"
+
+// Shorten returns a string representation of the path which fits in maxWidth characters (counted in runes) by
+// replacing middle components with '…'.  The volume, the first component and the last component are preserved as much
+// as possible.  It is useful for status bars and tables where full paths do not fit.  When the path cannot fit even
+// with only the last component, the head of the last component is also replaced with '…'.
+//
+// Example:
+//	a, _ := abspath.New("/very/long/path/to/deep/file.txt")
+//	a.Shorten(21) // => "/very/…/deep/file.txt"
+func (a AbsPath) Shorten(maxWidth int) string {
+	width := func(s string) int { return len([]rune(s)) }
+	if width(a.underlying) <= maxWidth {
+		return a.underlying
+	}
+
+	sep := string(os.PathSeparator)
+	vol, ss := a.SplitAll()
+	if len(ss) > 2 {
+		head := vol + sep + ss[0] + sep + ellipsis + sep
+		for k := len(ss) - 2; k >= 1; k-- {
+			s := head + strings.Join(ss[len(ss)-k:], sep)
+			if width(s) <= maxWidth {
+				return s
+			}
+		}
+	}
+
+	if len(ss) == 0 {
+		return a.underlying
+	}
+	last := ss[len(ss)-1]
+	if s := ellipsis + sep + last; width(s) <= maxWidth {
+		return s
+	}
+	if maxWidth <= 1 {
+		return ellipsis
+	}
+	rs := []rune(last)
+	return ellipsis + string(rs[len(rs)-(maxWidth-1):])
+}
+
+// isShellSafe returns whether the string can be used in POSIX shell without quoting.
+func isShellSafe(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case strings.ContainsRune("_@%+=:,./-", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// ShellQuote returns a string representation of the path quoted for POSIX shell.  When the path contains no special
+// character, it is returned as it is.  Otherwise it is enclosed in single quotes.  The result can be safely embedded in
+// shell commands.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/it's file")
+//	a.ShellQuote() // => `'/path/to/it'\''s file'`
+func (a AbsPath) ShellQuote() string {
+	if isShellSafe(a.underlying) {
+		return a.underlying
+	}
+	return "'" + strings.Replace(a.underlying, "'", `'\''`, -1) + "'"
+}
+
+// WindowsArgQuote returns a string representation of the path quoted as a command line argument on Windows.  It follows
+// the rules of CommandLineToArgvW.  When the path contains no space, tab nor double quote, it is returned as it is.
+//
+// Example:
+//	a, _ := abspath.New(`C:\Program Files\foo`)
+//	a.WindowsArgQuote() // => `"C:\Program Files\foo"`
+func (a AbsPath) WindowsArgQuote() string {
+	s := a.underlying
+	if s != "" && !strings.ContainsAny(s, " \t\"") {
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	backslashes := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\\':
+			backslashes++
+			continue
+		case '"':
+			// Backslashes before a double quote must be escaped, and the double quote itself too
+			b.WriteString(strings.Repeat(`\`, backslashes*2+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, backslashes))
+		}
+		backslashes = 0
+		b.WriteByte(c)
+	}
+	// Backslashes before the closing double quote must be escaped
+	b.WriteString(strings.Repeat(`\`, backslashes*2))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// RelToCwd returns a relative path from the current working directory to the path.  It is a shortcut of Getwd() and
+// Rel().  Like Rel(), the relative path is returned as a string.  It is useful to print paths relative to where the user
+// ran the tool.
+//
+// Example:
+//	// When the current working directory is /home/foo
+//	a, _ := abspath.New("/home/foo/src/main.go")
+//	s, err := a.RelToCwd() // => "src/main.go"
+func (a AbsPath) RelToCwd() (string, error) {
+	cwd, err := getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(cwd, a.underlying)
+}
+
+// TryRel returns a relative path from base to the path when the path is under base (or equal to base).  Otherwise, for
+// example when the path is outside base or on a different volume, the second return value is false.  It is useful for
+// the common pattern to use a relative path if possible and keep the absolute path otherwise without handling errors.
+// Like Rel(), the relative path is returned as a string.
+//
+// Example:
+//	base, _ := abspath.New("/project")
+//	a, _ := abspath.New("/project/src/main.go")
+//	s, ok := a.TryRel(base) // => "src/main.go", true
+func (a AbsPath) TryRel(base AbsPath) (string, bool) {
+	if !base.ContainsPath(a) {
+		return "", false
+	}
+	ss := a.Segments()[base.Depth():]
+	if len(ss) == 0 {
+		return ".", true
+	}
+	return strings.Join(ss, string(os.PathSeparator)), true
+}
+
+// DisplayOptions is a set of options for DisplayFromWithOptions() method.
+type DisplayOptions struct {
+	// MaxParents is the maximum number of '..' components allowed in the relative form.
+	MaxParents int
+	// AbbreviateHome makes the absolute form abbreviate the home directory with '~' as Abbreviate() does.
+	AbbreviateHome bool
+}
+
+// DisplayFrom returns a string representation of the path for human-facing output.  When the relative path from base is
+// shorter than the absolute path and does not go up from base more than 2 levels, it returns the relative path.
+// Otherwise it returns the absolute path whose home directory is abbreviated with '~'.
+//
+// Example:
+//	cwd, _ := abspath.Getwd() // e.g. /home/foo/project
+//	a, _ := abspath.New("/home/foo/project/src/main.go")
+//	b, _ := abspath.New("/home/foo/.config/app.json")
+//	a.DisplayFrom(cwd) // => "src/main.go"
+//	b.DisplayFrom(cwd) // => "~/.config/app.json"
+func (a AbsPath) DisplayFrom(base AbsPath) string {
+	return a.DisplayFromWithOptions(base, &DisplayOptions{MaxParents: 2, AbbreviateHome: true})
+}
+
+// DisplayFromWithOptions is the same as DisplayFrom() but accepts options.  When opts is nil, the relative form must
+// not go up from base and the home directory is not abbreviated.
+func (a AbsPath) DisplayFromWithOptions(base AbsPath, opts *DisplayOptions) string {
+	if opts == nil {
+		opts = &DisplayOptions{}
+	}
+
+	abs := a.underlying
+	if opts.AbbreviateHome {
+		abs = a.Abbreviate()
+	}
+
+	rel, err := filepath.Rel(base.underlying, a.underlying)
+	if err != nil {
+		return abs
+	}
+	parents := 0
+	for _, c := range strings.Split(rel, string(os.PathSeparator)) {
+		if c != ".." {
+			break
+		}
+		parents++
+	}
+	if parents > opts.MaxParents || len(rel) >= len(abs) {
+		return abs
+	}
+	return rel
+}
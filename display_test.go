@@ -0,0 +1,235 @@
+package abspath
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+)
+
+func TestAbbreviate(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		panic(err)
+	}
+	home, err := New(u.HomeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sep := string(filepath.Separator)
+
+	for _, c := range []struct {
+		input    AbsPath
+		expected string
+	}{
+		{home, "~"},
+		{home.Join("Documents", "foo.txt"), "~" + sep + "Documents" + sep + "foo.txt"},
+		{AbsPath{home.String() + "foo"}, home.String() + "foo"},
+		{home.Dir(), home.Dir().String()},
+	} {
+		if s := c.input.Abbreviate(); s != c.expected {
+			t.Errorf("Expected %q but actually %q", c.expected, s)
+		}
+	}
+}
+
+func TestAbbreviateEnv(t *testing.T) {
+	env := homeEnv
+	prev, ok := os.LookupEnv(env)
+	defer func() {
+		if ok {
+			os.Setenv(env, prev)
+		} else {
+			os.Unsetenv(env)
+		}
+	}()
+
+	home, _ := FromSlash(fixAbsPath("/custom/home"))
+	os.Setenv(env, home.String())
+
+	a := home.Join("foo")
+	e := "~" + string(filepath.Separator) + "foo"
+	if s := a.AbbreviateEnv(); s != e {
+		t.Errorf("Expected %q but actually %q", e, s)
+	}
+
+	os.Unsetenv(env)
+	if s := a.AbbreviateEnv(); s != a.String() {
+		t.Errorf("Expected %q without home directory but actually %q", a, s)
+	}
+}
+
+func TestShorten(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/very/long/path/to/deep/file.txt"))
+	vol := a.VolumeName()
+	for _, c := range []struct {
+		width    int
+		expected string
+	}{
+		{100, "/very/long/path/to/deep/file.txt"},
+		{len(vol) + 32, "/very/long/path/to/deep/file.txt"},
+		{len(vol) + 31, "/very/…/path/to/deep/file.txt"},
+		{len(vol) + 24, "/very/…/to/deep/file.txt"},
+		{len(vol) + 21, "/very/…/deep/file.txt"},
+		{len(vol) + 18, "/very/…/file.txt"},
+		{len(vol) + 15, "…/file.txt"},
+		{10, "…/file.txt"},
+		{9, "…file.txt"},
+		{5, "….txt"},
+		{1, "…"},
+	} {
+		e := c.expected
+		if e[0] == '/' {
+			e = fixAbsPath(e)
+		}
+		e = filepath.FromSlash(e)
+		s := a.Shorten(c.width)
+		if s != e {
+			t.Errorf("Expected %q for width %d but actually %q", e, c.width, s)
+		}
+		if n := len([]rune(s)); n > c.width {
+			t.Errorf("%q is longer than %d", s, c.width)
+		}
+	}
+
+	if !isWindows {
+		// The example in the document of Shorten()
+		if s := a.Shorten(21); s != "/very/…/deep/file.txt" {
+			t.Errorf("Unexpected result for the documented example: %q", s)
+		}
+	}
+
+	b, _ := FromSlash(fixAbsPath("/a/日本語のファイル名.txt"))
+	if s := b.Shorten(8); s != "…イル名.txt" {
+		t.Errorf("Unexpected result for multi-byte path: %q", s)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected string
+	}{
+		{"/path/to/file.txt", "/path/to/file.txt"},
+		{"/path/to/my file", "'/path/to/my file'"},
+		{"/path/to/it's", `'/path/to/it'\''s'`},
+		{"/path/$HOME/`cmd`", "'/path/$HOME/`cmd`'"},
+		{"/path/to/日本語", "'/path/to/日本語'"},
+		{"/a-b_c@d%e+f=g:h,i", "/a-b_c@d%e+f=g:h,i"},
+	} {
+		if s := (AbsPath{c.input}).ShellQuote(); s != c.expected {
+			t.Errorf("Expected %s but actually %s", c.expected, s)
+		}
+	}
+}
+
+func TestWindowsArgQuote(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected string
+	}{
+		{`C:\path\to\file`, `C:\path\to\file`},
+		{`C:\Program Files\foo`, `"C:\Program Files\foo"`},
+		{`C:\Program Files\`, `"C:\Program Files\\"`},
+		{`C:\a"b`, `"C:\a\"b"`},
+		{`C:\a\"b c`, `"C:\a\\\"b c"`},
+		{`\\server\share\a b`, `"\\server\share\a b"`},
+	} {
+		if s := (AbsPath{c.input}).WindowsArgQuote(); s != c.expected {
+			t.Errorf("Expected %s but actually %s", c.expected, s)
+		}
+	}
+}
+
+func TestRelToCwd(t *testing.T) {
+	cwd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		input    AbsPath
+		expected string
+	}{
+		{cwd.Join("foo", "bar"), filepath.Join("foo", "bar")},
+		{cwd, "."},
+		{cwd.Dir().Join("sibling"), filepath.Join("..", "sibling")},
+	} {
+		s, err := c.input.RelToCwd()
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if s != c.expected {
+			t.Errorf("Expected %q but actually %q", c.expected, s)
+		}
+	}
+}
+
+func TestTryRel(t *testing.T) {
+	base, _ := FromSlash(fixAbsPath("/project"))
+	for _, c := range []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"/project/src/main.go", "src/main.go", true},
+		{"/project", ".", true},
+		{"/other/file", "", false},
+		{"/projects/file", "", false},
+		{"/", "", false},
+	} {
+		a, _ := FromSlash(fixAbsPath(c.input))
+		s, ok := a.TryRel(base)
+		if s != filepath.FromSlash(c.expected) || ok != c.ok {
+			t.Errorf("Expected (%q, %v) for %s but actually (%q, %v)", c.expected, c.ok, a, s, ok)
+		}
+	}
+
+	if isWindows {
+		a, _ := New(`D:\project\foo`)
+		b, _ := New(`C:\project`)
+		if _, ok := a.TryRel(b); ok {
+			t.Errorf("Path on different volume should not be relative")
+		}
+	}
+}
+
+func TestDisplayFrom(t *testing.T) {
+	base, _ := FromSlash(fixAbsPath("/long/path/to/project"))
+	for _, c := range []struct {
+		input    string
+		expected string
+	}{
+		{"/long/path/to/project/src/main.go", "src/main.go"},
+		{"/long/path/to/project", "."},
+		{"/long/path/to/other/file", "../other/file"},
+		{"/long/path/other/file", "../../other/file"},
+		{"/long/other/file", fixAbsPath("/long/other/file")},
+		{"/a", fixAbsPath("/a")},
+	} {
+		a, _ := FromSlash(fixAbsPath(c.input))
+		e := filepath.FromSlash(c.expected)
+		if s := a.DisplayFrom(base); s != e {
+			t.Errorf("Expected %q for %s but actually %q", e, a, s)
+		}
+	}
+
+	a, _ := FromSlash(fixAbsPath("/long/path/to/other/file"))
+	if s := a.DisplayFromWithOptions(base, nil); s != a.String() {
+		t.Errorf("Expected %q without allowing parents but actually %q", a, s)
+	}
+	e := filepath.FromSlash("../other/file")
+	if s := a.DisplayFromWithOptions(base, &DisplayOptions{MaxParents: 1}); s != e {
+		t.Errorf("Expected %q but actually %q", e, s)
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		panic(err)
+	}
+	home, _ := New(u.HomeDir)
+	h := home.Join(".config", "app.json")
+	if s := h.DisplayFrom(base); s != h.Abbreviate() {
+		t.Errorf("Expected %q but actually %q", h.Abbreviate(), s)
+	}
+}
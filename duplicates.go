@@ -0,0 +1,171 @@
+package abspath
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// FindDuplicatesOptions is a set of options for FindDuplicates() function.
+type FindDuplicatesOptions struct {
+	// Hash is the hash function to compare file contents.  When it is zero, crypto.SHA256 is used.  The package
+	// implementing the hash function must be linked into the binary (e.g. by importing crypto/sha256).
+	Hash crypto.Hash
+	// MinSize is the minimum size of files to find in bytes.  Empty files are always ignored.
+	MinSize int64
+	// Walk filters entries under the roots.  It can be nil.
+	Walk *WalkOptions
+}
+
+// FindDuplicates finds regular files which have the same contents under the directory trees at the roots and returns
+// groups of them.  Unlike DedupeTree(), it only reports duplicates and does not modify any file.  Files are compared
+// across all roots.  Candidates are narrowed down by file sizes before hashing so that only files whose sizes collide
+// are read.  Groups and files in each group are ordered as they are found while walking the roots in order.  Hard links
+// to the same file (including the same file reached via overlapping roots) are reported only once since they do not
+// occupy extra space.  opts can be nil.
+//
+// Example:
+//	photos, _ := abspath.ExpandFrom("~/Pictures")
+//	backup, _ := abspath.New("/mnt/backup/Pictures")
+//	groups, err := abspath.FindDuplicates([]abspath.AbsPath{photos, backup}, &abspath.FindDuplicatesOptions{
+//		MinSize: 1024,
+//		Walk:    &abspath.WalkOptions{SkipHidden: true},
+//	})
+//	if err != nil {
+//		panic(err)
+//	}
+//	for _, g := range groups {
+//		fmt.Println("Identical files:", g)
+//	}
+func FindDuplicates(roots []AbsPath, opts *FindDuplicatesOptions) ([][]AbsPath, error) {
+	return FindDuplicatesContext(context.Background(), roots, opts)
+}
+
+// FindDuplicatesContext is the same as FindDuplicates() but accepts a context.  Finding is stopped between entries and
+// between chunks of hashed files when the context is done.
+func FindDuplicatesContext(ctx context.Context, roots []AbsPath, opts *FindDuplicatesOptions) (_ [][]AbsPath, err error) {
+	defer annotate(&err, "find duplicates in", roots...)
+	if opts == nil {
+		opts = &FindDuplicatesOptions{}
+	}
+	groups, err := findDuplicates(ctx, roots, &dupSearch{opts.Walk, opts.Hash, opts.MinSize})
+	if err != nil {
+		return nil, err
+	}
+	ret := make([][]AbsPath, 0, len(groups))
+	for _, g := range groups {
+		ret = append(ret, dupPaths(g))
+	}
+	return ret, nil
+}
+
+// dupFile is a file found while searching duplicates.
+type dupFile struct {
+	path AbsPath
+	info os.FileInfo
+}
+
+// dupSearch is a set of parameters to search duplicate files.
+type dupSearch struct {
+	walk    *WalkOptions
+	hash    crypto.Hash
+	minSize int64
+}
+
+// findDuplicates returns groups of regular files which have the same contents under the roots.  Files are first grouped
+// by their sizes and only files whose sizes collide are hashed.  Files in each group and the groups are ordered as they
+// are found while walking the roots in order.  Hard links to the same file are reported only once since they do not
+// occupy extra space.  Empty files and files smaller than minSize are ignored.
+func findDuplicates(ctx context.Context, roots []AbsPath, s *dupSearch) ([][]dupFile, error) {
+	h := s.hash
+	if h == 0 {
+		h = crypto.SHA256
+	}
+	if !h.Available() {
+		return nil, fmt.Errorf("hash function %v is not available. import the package implementing it", h)
+	}
+	minSize := s.minSize
+	if minSize < 1 {
+		minSize = 1
+	}
+
+	var files []dupFile
+	seen := map[fileKey]struct{}{}
+	useID := usesOSFS()
+	for _, root := range roots {
+		err := root.WalkContext(ctx, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() || info.Size() < minSize {
+				return nil
+			}
+			k := fileKey{path: p}
+			if useID {
+				if id, ok := fileID(p, info); ok {
+					k = id
+				}
+			}
+			if _, ok := seen[k]; ok {
+				return nil
+			}
+			seen[k] = struct{}{}
+			files = append(files, dupFile{AbsPath{p}, info})
+			return nil
+		}, s.walk)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bySize := map[int64][]int{}
+	for i, f := range files {
+		bySize[f.info.Size()] = append(bySize[f.info.Size()], i)
+	}
+
+	var groups [][]int
+	for _, idx := range bySize {
+		if len(idx) < 2 {
+			continue
+		}
+		byDigest := map[string][]int{}
+		var digests []string
+		for _, i := range idx {
+			sum, _, err := hashFile(ctx, files[i].path.underlying, h.New(), nil)
+			if err != nil {
+				return nil, err
+			}
+			d := string(sum)
+			if _, ok := byDigest[d]; !ok {
+				digests = append(digests, d)
+			}
+			byDigest[d] = append(byDigest[d], i)
+		}
+		for _, d := range digests {
+			if g := byDigest[d]; len(g) > 1 {
+				groups = append(groups, g)
+			}
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+
+	ret := make([][]dupFile, 0, len(groups))
+	for _, g := range groups {
+		fs := make([]dupFile, 0, len(g))
+		for _, i := range g {
+			fs = append(fs, files[i])
+		}
+		ret = append(ret, fs)
+	}
+	return ret, nil
+}
+
+func dupPaths(files []dupFile) []AbsPath {
+	ps := make([]AbsPath, 0, len(files))
+	for _, f := range files {
+		ps = append(ps, f.path)
+	}
+	return ps
+}
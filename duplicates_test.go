@@ -0,0 +1,69 @@
+package abspath
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"x/a.txt":   "hello",
+		"x/b.txt":   "other",
+		"x/c.txt":   "world",
+		"y/a.txt":   "hello",
+		"y/d/w.txt": "world",
+		"y/e.txt":   "hello",
+		"y/.h.txt":  "hello",
+	})
+	x, y := root.Join("x"), root.Join("y")
+
+	groups, err := FindDuplicates([]AbsPath{x, y}, &FindDuplicatesOptions{Walk: &WalkOptions{SkipHidden: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]AbsPath{
+		{x.Join("a.txt"), y.Join("a.txt"), y.Join("e.txt")},
+		{x.Join("c.txt"), y.Join("d", "w.txt")},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("Wanted %v but got %v", want, groups)
+	}
+	assertContent(t, y.Join("e.txt"), "hello")
+
+	// Files reached via overlapping roots are reported once
+	groups, err = FindDuplicates([]AbsPath{x, root}, &FindDuplicatesOptions{Hash: crypto.SHA512, MinSize: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || len(groups[0]) != 4 {
+		t.Errorf("Unexpected groups: %v", groups)
+	}
+
+	groups, err = FindDuplicates([]AbsPath{x}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("No duplicate should be found: %v", groups)
+	}
+}
+
+func TestFindDuplicatesError(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "a", "b.txt": "a"})
+
+	_, err := FindDuplicates([]AbsPath{root, root.Join("missing")}, nil)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+	_, err = FindDuplicatesContext(canceledContext(), []AbsPath{root}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Canceled error should be returned but got %v", err)
+	}
+	if _, err := FindDuplicates([]AbsPath{root}, &FindDuplicatesOptions{Hash: crypto.Hash(999)}); err == nil {
+		t.Error("Unavailable hash function should cause an error")
+	}
+}
@@ -0,0 +1,118 @@
+package abspath
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// FileFormatError is an error returned when decoding or encoding a file in some format such as JSON fails.  It
+// annotates the underlying error with the path and the format.  Errors on accessing the file are returned as PathError.
+type FileFormatError struct {
+	// Path is the path of the file.
+	Path AbsPath
+	// Format is the name of the format like "JSON".
+	Format string
+	// Op is "decode" or "encode".
+	Op string
+	// Err is the underlying error returned from the decoder or the encoder.
+	Err error
+}
+
+func (err *FileFormatError) Error() string {
+	return fmt.Sprintf("Cannot %s %s file '%s': %s", err.Op, err.Format, err.Path.underlying, err.Err)
+}
+
+// Unwrap returns the underlying error.
+func (err *FileFormatError) Unwrap() error {
+	return err.Err
+}
+
+// UnmarshalFunc is a function to decode bytes into a value like json.Unmarshal().
+type UnmarshalFunc func(data []byte, v interface{}) error
+
+// MarshalFunc is a function to encode a value into bytes like json.Marshal().
+type MarshalFunc func(v interface{}) ([]byte, error)
+
+// ReadFormat reads the file and decodes its content into v with the unmarshal function.  The format is a name of the
+// format used in error messages.  When decoding fails, FileFormatError is returned.  It is useful to support formats
+// other than JSON.
+//
+// Example:
+//	var c Config
+//	err := a.ReadFormat("YAML", &c, yaml.Unmarshal)
+func (a AbsPath) ReadFormat(format string, v interface{}, unmarshal UnmarshalFunc) (err error) {
+	defer annotate(&err, "read", a)
+	f, err := fsys().Open(a.underlying)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	if err := unmarshal(b, v); err != nil {
+		return &FileFormatError{a, format, "decode", err}
+	}
+	return nil
+}
+
+// WriteFormat encodes v with the marshal function and writes the result to the file.  The format is a name of the
+// format used in error messages.  When encoding fails, FileFormatError is returned and the file is not touched.  When
+// the file does not exist, it is created with the permission.  Otherwise the file is truncated.
+//
+// Example:
+//	err := a.WriteFormat("YAML", &c, yaml.Marshal, 0644)
+func (a AbsPath) WriteFormat(format string, v interface{}, marshal MarshalFunc, perm os.FileMode) (err error) {
+	defer annotate(&err, "write", a)
+	b, err := marshal(v)
+	if err != nil {
+		return &FileFormatError{a, format, "encode", err}
+	}
+
+	f, err := fsys().OpenFile(a.underlying, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadJSON reads the JSON file and decodes it into v as json.Unmarshal() does.  When decoding fails, FileFormatError
+// which annotates the error with the path is returned.
+//
+// Example:
+//	var c struct {
+//		Name string `json:"name"`
+//	}
+//	a, _ := abspath.ExpandFrom("~/.config/myapp/config.json")
+//	if err := a.ReadJSON(&c); err != nil {
+//		panic(err)
+//	}
+func (a AbsPath) ReadJSON(v interface{}) error {
+	return a.ReadFormat("JSON", v, json.Unmarshal)
+}
+
+// WriteJSON encodes v as JSON and writes it to the file.  The JSON is indented with 2 spaces and ends with a newline.
+// When the file does not exist, it is created with the permission.  Otherwise the file is truncated.
+//
+// Example:
+//	a, _ := abspath.ExpandFrom("~/.config/myapp/config.json")
+//	err := a.WriteJSON(&c, 0644)
+func (a AbsPath) WriteJSON(v interface{}, perm os.FileMode) error {
+	return a.WriteFormat("JSON", v, marshalJSON, perm)
+}
+
+func marshalJSON(v interface{}) ([]byte, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,88 @@
+package abspath
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+type testConfig struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+}
+
+func TestReadWriteJSON(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"config.json": `{"name": "foo", "count": 3, "tags": ["a", "b"]}`,
+		"broken.json": `{"name": `,
+	})
+
+	var c testConfig
+	if err := root.Join("config.json").ReadJSON(&c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "foo" || c.Count != 3 || len(c.Tags) != 2 {
+		t.Errorf("Unexpected decoded value: %+v", c)
+	}
+
+	out := root.Join("out.json")
+	if err := out.WriteJSON(&c, 0644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(out.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"name\": \"foo\",\n  \"count\": 3,\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}\n"
+	if string(b) != want {
+		t.Errorf("Unexpected encoded JSON: %q", b)
+	}
+	var c2 testConfig
+	if err := out.ReadJSON(&c2); err != nil {
+		t.Fatal(err)
+	}
+	if c2.Name != c.Name || c2.Count != c.Count {
+		t.Errorf("Written JSON should be read as the same value: %+v", c2)
+	}
+
+	err = root.Join("broken.json").ReadJSON(&c)
+	var ferr *FileFormatError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("FileFormatError should be returned but %T: %v", err, err)
+	}
+	if ferr.Path != root.Join("broken.json") || ferr.Format != "JSON" || ferr.Op != "decode" {
+		t.Errorf("Unexpected error fields: %+v", ferr)
+	}
+	if !strings.Contains(err.Error(), root.Join("broken.json").String()) {
+		t.Errorf("Error message should contain the path: %q", err)
+	}
+	var serr *json.SyntaxError
+	if !errors.As(err, &serr) {
+		t.Errorf("Underlying error should be unwrapped: %v", err)
+	}
+
+	if err := root.Join("missing.json").ReadJSON(&c); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned: %v", err)
+	}
+}
+
+func TestWriteJSONError(t *testing.T) {
+	root := makeTree(t, map[string]string{"exist.json": "{}"})
+	p := root.Join("exist.json")
+	err := p.WriteJSON(map[string]interface{}{"ch": make(chan int)}, 0644)
+	var ferr *FileFormatError
+	if !errors.As(err, &ferr) || ferr.Op != "encode" {
+		t.Fatalf("FileFormatError should be returned but %v", err)
+	}
+	b, err := ioutil.ReadFile(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "{}" {
+		t.Errorf("File should not be touched when encoding fails: %q", b)
+	}
+}
@@ -0,0 +1,133 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrorKind is a category of failures of filesystem operations.  Platform-specific error codes such as errno on Unix
+// and error codes of Windows API are normalized into the same kind so that callers can branch on failures portably.
+type ErrorKind int
+
+const (
+	// ErrorKindNone means no error.
+	ErrorKindNone ErrorKind = iota
+	// ErrorKindOther is a kind of errors which are not categorized into any other kind.
+	ErrorKindOther
+	// ErrorKindNotExist means the file or the directory does not exist.
+	ErrorKindNotExist
+	// ErrorKindExist means the file or the directory already exists.
+	ErrorKindExist
+	// ErrorKindPermission means the permission was denied.
+	ErrorKindPermission
+	// ErrorKindNotDir means a directory was expected but a non-directory file was found.
+	ErrorKindNotDir
+	// ErrorKindIsDir means a non-directory file was expected but a directory was found.
+	ErrorKindIsDir
+	// ErrorKindNotEmpty means the directory is not empty.
+	ErrorKindNotEmpty
+	// ErrorKindCrossDevice means the operation such as renaming cannot be done across filesystems or volumes.
+	ErrorKindCrossDevice
+	// ErrorKindNameTooLong means the file name or the path is too long.
+	ErrorKindNameTooLong
+	// ErrorKindDiskFull means there is no space left on the device or the disk quota was exceeded.
+	ErrorKindDiskFull
+	// ErrorKindReadOnly means the filesystem is read-only.
+	ErrorKindReadOnly
+	// ErrorKindBusy means the file or the device is being used by others.
+	ErrorKindBusy
+	// ErrorKindUnsupported means the operation is not supported by the platform or the filesystem.
+	ErrorKindUnsupported
+)
+
+var errorKindNames = []string{
+	"none",
+	"other",
+	"not exist",
+	"exist",
+	"permission",
+	"not directory",
+	"is directory",
+	"not empty",
+	"cross device",
+	"name too long",
+	"disk full",
+	"read only",
+	"busy",
+	"unsupported",
+}
+
+// String returns the name of the kind.
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(errorKindNames) {
+		return "unknown"
+	}
+	return errorKindNames[k]
+}
+
+// ClassifyError returns the kind of the error.  Wrapped errors such as PathError and *os.PathError are unwrapped to
+// find the platform-specific error code.  It returns ErrorKindNone for nil and ErrorKindOther for errors which cannot
+// be categorized.
+//
+// Example:
+//	err := src.RenameRetry(dst, nil)
+//	switch abspath.ClassifyError(err) {
+//	case abspath.ErrorKindNone:
+//		// Renamed
+//	case abspath.ErrorKindCrossDevice:
+//		// Fall back to copying and removing
+//		err = src.Copy(dst, nil)
+//	default:
+//		return err
+//	}
+func ClassifyError(err error) ErrorKind {
+	if err == nil {
+		return ErrorKindNone
+	}
+	if errors.Is(err, ErrUnsupported) {
+		return ErrorKindUnsupported
+	}
+	if k := platformErrorKind(err); k != ErrorKindOther {
+		return k
+	}
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return ErrorKindNotExist
+	case errors.Is(err, os.ErrExist):
+		return ErrorKindExist
+	case errors.Is(err, os.ErrPermission):
+		return ErrorKindPermission
+	default:
+		return ErrorKindOther
+	}
+}
+
+// IsNotExist returns whether the error means the file or the directory does not exist.  Unlike os.IsNotExist(), errors
+// wrapped by this package or by fmt.Errorf() with %w are also checked.
+func IsNotExist(err error) bool {
+	return ClassifyError(err) == ErrorKindNotExist
+}
+
+// IsPermission returns whether the error means the permission was denied.  Unlike os.IsPermission(), errors wrapped by
+// this package or by fmt.Errorf() with %w are also checked.
+func IsPermission(err error) bool {
+	return ClassifyError(err) == ErrorKindPermission
+}
+
+// IsCrossDevice returns whether the error means the operation cannot be done across filesystems or volumes.  It is
+// typically returned from renaming a file to another device (EXDEV on Unix, ERROR_NOT_SAME_DEVICE on Windows).
+func IsCrossDevice(err error) bool {
+	return ClassifyError(err) == ErrorKindCrossDevice
+}
+
+// IsNameTooLong returns whether the error means the file name or the path is too long (ENAMETOOLONG on Unix,
+// ERROR_FILENAME_EXCED_RANGE on Windows).
+func IsNameTooLong(err error) bool {
+	return ClassifyError(err) == ErrorKindNameTooLong
+}
+
+// IsDiskFull returns whether the error means there is no space left on the device or the disk quota was exceeded
+// (ENOSPC or EDQUOT on Unix, ERROR_DISK_FULL, ERROR_HANDLE_DISK_FULL or ERROR_DISK_QUOTA_EXCEEDED on Windows).
+func IsDiskFull(err error) bool {
+	return ClassifyError(err) == ErrorKindDiskFull
+}
@@ -0,0 +1,39 @@
+package abspath
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+)
+
+// plan9ErrorKinds maps substrings of Plan 9 error strings to kinds.  Plan 9 has no error codes.
+var plan9ErrorKinds = []struct {
+	msg  string
+	kind ErrorKind
+}{
+	{"does not exist", ErrorKindNotExist},
+	{"not found", ErrorKindNotExist},
+	{"already exists", ErrorKindExist},
+	{"permission denied", ErrorKindPermission},
+	{"not a directory", ErrorKindNotDir},
+	{"is a directory", ErrorKindIsDir},
+	{"directory not empty", ErrorKindNotEmpty},
+	{"name too long", ErrorKindNameTooLong},
+	{"file system full", ErrorKindDiskFull},
+	{"read only", ErrorKindReadOnly},
+	{"file in use", ErrorKindBusy},
+}
+
+func platformErrorKind(err error) ErrorKind {
+	var s syscall.ErrorString
+	if !errors.As(err, &s) {
+		return ErrorKindOther
+	}
+	msg := strings.ToLower(string(s))
+	for _, k := range plan9ErrorKinds {
+		if strings.Contains(msg, k.msg) {
+			return k.kind
+		}
+	}
+	return ErrorKindOther
+}
@@ -0,0 +1,74 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestClassifyErrorFromFilesystem(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "a", "d/b.txt": "b"})
+
+	_, err := root.Join("missing").ReadFile()
+	if k := ClassifyError(err); k != ErrorKindNotExist {
+		t.Errorf("Wanted %v but got %v for %v", ErrorKindNotExist, k, err)
+	}
+	if !IsNotExist(err) {
+		t.Errorf("IsNotExist() should be true for %v", err)
+	}
+	if !IsNotExist(fmt.Errorf("wrapped: %w", err)) {
+		t.Errorf("IsNotExist() should be true for wrapped %v", err)
+	}
+
+	_, err = root.Join("a.txt", "b").ReadFile()
+	if k := ClassifyError(err); !isWindows && k != ErrorKindNotDir {
+		t.Errorf("Wanted %v but got %v for %v", ErrorKindNotDir, k, err)
+	}
+
+	err = os.Remove(root.Join("d").String())
+	if k := ClassifyError(err); k != ErrorKindNotEmpty && k != ErrorKindExist {
+		t.Errorf("Wanted %v but got %v for %v", ErrorKindNotEmpty, k, err)
+	}
+
+	err = os.Mkdir(root.Join("d").String(), 0755)
+	if k := ClassifyError(err); k != ErrorKindExist {
+		t.Errorf("Wanted %v but got %v for %v", ErrorKindExist, k, err)
+	}
+}
+
+func TestClassifyErrorSentinels(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want ErrorKind
+	}{
+		{nil, ErrorKindNone},
+		{fmt.Errorf("some error"), ErrorKindOther},
+		{os.ErrNotExist, ErrorKindNotExist},
+		{os.ErrExist, ErrorKindExist},
+		{os.ErrPermission, ErrorKindPermission},
+		{&UnsupportedError{Op: "Foo"}, ErrorKindUnsupported},
+		{&PathError{"read", nil, &os.PathError{Op: "open", Path: "/a", Err: os.ErrPermission}}, ErrorKindPermission},
+	} {
+		if have := ClassifyError(tc.err); have != tc.want {
+			t.Errorf("Wanted %v but got %v for %v", tc.want, have, tc.err)
+		}
+	}
+
+	if !IsPermission(os.ErrPermission) {
+		t.Error("IsPermission() should be true for os.ErrPermission")
+	}
+	if IsCrossDevice(os.ErrNotExist) || IsNameTooLong(os.ErrNotExist) || IsDiskFull(os.ErrNotExist) {
+		t.Error("Not-exist error should not be classified into other kinds")
+	}
+}
+
+func TestErrorKindString(t *testing.T) {
+	for k := ErrorKindNone; k <= ErrorKindUnsupported; k++ {
+		if s := k.String(); s == "" || s == "unknown" {
+			t.Errorf("Name of kind %d should be defined", int(k))
+		}
+	}
+	if s := ErrorKind(-1).String(); s != "unknown" {
+		t.Errorf("Unknown kind should be %q but got %q", "unknown", s)
+	}
+}
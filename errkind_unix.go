@@ -0,0 +1,50 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package abspath
+
+import (
+	"errors"
+	"syscall"
+)
+
+func platformErrorKind(err error) ErrorKind {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return ErrorKindOther
+	}
+	switch errno {
+	case syscall.ENOENT:
+		return ErrorKindNotExist
+	case syscall.EEXIST:
+		return ErrorKindExist
+	case syscall.EACCES, syscall.EPERM:
+		return ErrorKindPermission
+	case syscall.ENOTDIR:
+		return ErrorKindNotDir
+	case syscall.EISDIR:
+		return ErrorKindIsDir
+	case syscall.EXDEV:
+		return ErrorKindCrossDevice
+	case syscall.ENAMETOOLONG:
+		return ErrorKindNameTooLong
+	case syscall.ENOSPC, syscall.EDQUOT:
+		return ErrorKindDiskFull
+	case syscall.EROFS:
+		return ErrorKindReadOnly
+	case syscall.EBUSY:
+		return ErrorKindBusy
+	case syscall.ENOSYS, syscall.ENOTSUP:
+		return ErrorKindUnsupported
+	}
+	// ENOTEMPTY is the same value as EEXIST on AIX so it cannot be in the same switch.  There it is reported as
+	// ErrorKindExist since the two errors are not distinguishable
+	if errno == syscall.ENOTEMPTY {
+		return ErrorKindNotEmpty
+	}
+	// EOPNOTSUPP is the same value as ENOTSUP on some platforms so it cannot be in the same switch
+	if errno == syscall.EOPNOTSUPP {
+		return ErrorKindUnsupported
+	}
+	return ErrorKindOther
+}
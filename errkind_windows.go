@@ -0,0 +1,41 @@
+package abspath
+
+import (
+	"errors"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+func platformErrorKind(err error) ErrorKind {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return ErrorKindOther
+	}
+	switch errno {
+	case windows.ERROR_FILE_NOT_FOUND, windows.ERROR_PATH_NOT_FOUND:
+		return ErrorKindNotExist
+	case windows.ERROR_FILE_EXISTS, windows.ERROR_ALREADY_EXISTS:
+		return ErrorKindExist
+	case windows.ERROR_ACCESS_DENIED:
+		return ErrorKindPermission
+	case windows.ERROR_DIRECTORY:
+		return ErrorKindNotDir
+	case windows.ERROR_DIR_NOT_EMPTY:
+		return ErrorKindNotEmpty
+	case windows.ERROR_NOT_SAME_DEVICE:
+		return ErrorKindCrossDevice
+	case windows.ERROR_FILENAME_EXCED_RANGE:
+		return ErrorKindNameTooLong
+	case windows.ERROR_DISK_FULL, windows.ERROR_HANDLE_DISK_FULL, windows.ERROR_DISK_QUOTA_EXCEEDED:
+		return ErrorKindDiskFull
+	case windows.ERROR_WRITE_PROTECT:
+		return ErrorKindReadOnly
+	case windows.ERROR_SHARING_VIOLATION, windows.ERROR_LOCK_VIOLATION, windows.ERROR_BUSY:
+		return ErrorKindBusy
+	case windows.ERROR_NOT_SUPPORTED:
+		return ErrorKindUnsupported
+	default:
+		return ErrorKindOther
+	}
+}
@@ -0,0 +1,82 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PathError is an error returned from methods which access the filesystem.  It annotates the underlying error with
+// the name of the method's operation and the paths involved so that error messages always tell which path failed.
+// Every error returned from such methods is annotated, including errors of context cancellation, errors of this
+// package (e.g. GuardError) and errors returned from callbacks such as Filter option of CopyOptions.  The underlying
+// error can be checked with errors.Is() and errors.As().  Note that os.IsNotExist() and friends do not unwrap errors.
+//
+// Example:
+//	err := src.CopyDir(dst, nil)
+//	var pe *abspath.PathError
+//	if errors.As(err, &pe) {
+//		fmt.Println(pe.Op, pe.Paths) // e.g. "copy [/path/to/src /path/to/dst]"
+//	}
+//	if errors.Is(err, os.ErrNotExist) {
+//		// Source directory does not exist
+//	}
+type PathError struct {
+	// Op is the name of the operation such as "read", "copy" or "remove".
+	Op string
+	// Paths is a list of paths involved in the operation.  For example, a source and a destination for "copy".
+	Paths []AbsPath
+	// Err is the underlying error.
+	Err error
+}
+
+func (err *PathError) Error() string {
+	ps := make([]string, 0, len(err.Paths))
+	for _, p := range err.Paths {
+		ps = append(ps, "'"+p.underlying+"'")
+	}
+	return fmt.Sprintf("Cannot %s %s: %s", err.Op, strings.Join(ps, " and "), err.cause())
+}
+
+// cause returns the message of the underlying error without repeating the paths already shown.
+func (err *PathError) cause() string {
+	known := func(p string) bool {
+		for _, q := range err.Paths {
+			if q.underlying == p {
+				return true
+			}
+		}
+		return false
+	}
+	switch e := err.Err.(type) {
+	case *os.PathError:
+		if known(e.Path) {
+			return e.Op + ": " + e.Err.Error()
+		}
+	case *os.LinkError:
+		if known(e.Old) && known(e.New) {
+			return e.Op + ": " + e.Err.Error()
+		}
+	}
+	return err.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (err *PathError) Unwrap() error {
+	return err.Err
+}
+
+// wrapPathError annotates the error with the operation and the paths.  It returns nil for nil.
+func wrapPathError(op string, err error, paths ...AbsPath) error {
+	switch err.(type) {
+	case nil, *PathError:
+		return err // Already annotated by an inner method
+	}
+	return &PathError{op, paths, err}
+}
+
+// annotate wraps the error pointed by errp with PathError.  It is deferred at the beginning of methods with a named
+// error result.
+func annotate(errp *error, op string, paths ...AbsPath) {
+	*errp = wrapPathError(op, *errp, paths...)
+}
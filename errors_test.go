@@ -0,0 +1,118 @@
+package abspath
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPathErrorFromMethods(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "a"})
+	missing := root.Join("missing")
+
+	_, err := missing.ReadFile()
+	var pe *PathError
+	if !errors.As(err, &pe) {
+		t.Fatalf("PathError should be returned but got %T: %v", err, err)
+	}
+	if pe.Op != "read" || len(pe.Paths) != 1 || pe.Paths[0] != missing {
+		t.Errorf("Unexpected op or paths: %q %v", pe.Op, pe.Paths)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be unwrapped: %v", err)
+	}
+	var ope *os.PathError
+	if !errors.As(err, &ope) || ope.Path != missing.String() {
+		t.Errorf("*os.PathError should be unwrapped: %v", err)
+	}
+
+	err = missing.CopyFile(root.Join("b.txt"), nil)
+	if !errors.As(err, &pe) {
+		t.Fatalf("PathError should be returned but got %T: %v", err, err)
+	}
+	if pe.Op != "copy" || len(pe.Paths) != 2 || pe.Paths[0] != missing || pe.Paths[1] != root.Join("b.txt") {
+		t.Errorf("Unexpected op or paths: %q %v", pe.Op, pe.Paths)
+	}
+
+	err = missing.CopyDir(root.Join("dst"), nil)
+	if !errors.As(err, &pe) {
+		t.Fatalf("PathError should be returned but got %T: %v", err, err)
+	}
+	if pe.Op != "copy" || len(pe.Paths) != 2 || pe.Paths[0] != missing || pe.Paths[1] != root.Join("dst") {
+		t.Errorf("Unexpected op or paths: %q %v", pe.Op, pe.Paths)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be unwrapped: %v", err)
+	}
+}
+
+func mustNew(t *testing.T, s string) AbsPath {
+	t.Helper()
+	a, err := New(fixAbsPath(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestPathErrorMessage(t *testing.T) {
+	a := mustNew(t, "/path/to/a")
+	b := mustNew(t, "/path/to/b")
+	child := mustNew(t, "/path/to/a/c")
+
+	for _, tc := range []struct {
+		err  *PathError
+		want string
+	}{
+		{
+			&PathError{"read", []AbsPath{a}, &os.PathError{Op: "open", Path: a.String(), Err: os.ErrNotExist}},
+			fmt.Sprintf("Cannot read '%s': open: %s", a, os.ErrNotExist),
+		},
+		{
+			&PathError{"copy", []AbsPath{a, b}, &os.PathError{Op: "open", Path: child.String(), Err: os.ErrPermission}},
+			fmt.Sprintf("Cannot copy '%s' and '%s': open %s: %s", a, b, child, os.ErrPermission),
+		},
+		{
+			&PathError{"rename", []AbsPath{a, b}, &os.LinkError{Op: "rename", Old: a.String(), New: b.String(), Err: os.ErrExist}},
+			fmt.Sprintf("Cannot rename '%s' and '%s': rename: %s", a, b, os.ErrExist),
+		},
+		{
+			&PathError{"lock", []AbsPath{a}, ErrLocked},
+			fmt.Sprintf("Cannot lock '%s': %s", a, ErrLocked),
+		},
+	} {
+		if have := tc.err.Error(); have != tc.want {
+			t.Errorf("Wanted %q but got %q", tc.want, have)
+		}
+	}
+}
+
+func TestWrapPathErrorAlwaysWraps(t *testing.T) {
+	a := mustNew(t, "/path/to/a")
+	pe := &PathError{"read", []AbsPath{a}, os.ErrNotExist}
+
+	for _, err := range []error{nil, pe} {
+		if have := wrapPathError("copy", err, a); have != err {
+			t.Errorf("Error %v should be returned as it is but got %v", err, have)
+		}
+	}
+
+	ge := &GuardError{"remove", a, "it is not under the allowed roots"}
+	ope := &os.PathError{Op: "open", Path: a.String(), Err: os.ErrNotExist}
+	for _, want := range []error{context.Canceled, context.DeadlineExceeded, ErrLocked, ge, ope} {
+		err := wrapPathError("copy", want, a)
+		var pe *PathError
+		if !errors.As(err, &pe) || pe.Op != "copy" {
+			t.Errorf("Error %v should be wrapped with PathError but got %T: %v", want, err, err)
+		}
+		if !errors.Is(err, want) {
+			t.Errorf("Error %v should be unwrapped: %v", want, err)
+		}
+		if !strings.Contains(err.Error(), a.String()) {
+			t.Errorf("Path should be contained in error message: %v", err)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+)
+
+// NamedPipe is a named pipe created by CreatePipe() method.  It must be closed by Close() method after use.
+type NamedPipe struct {
+	path AbsPath
+	file *os.File
+}
+
+// Path returns the path of the named pipe.  Other processes can connect to the pipe by opening the path.
+func (p *NamedPipe) Path() AbsPath {
+	return p.path
+}
+
+// File returns the server end of the named pipe on Windows.  Since a FIFO on Unix is opened by each side as a normal
+// file, it returns nil on Unix.
+func (p *NamedPipe) File() *os.File {
+	return p.file
+}
+
+// Close removes the named pipe.  On Unix, the FIFO file is removed.  On Windows, the server end is closed and the pipe
+// disappears.
+func (p *NamedPipe) Close() error {
+	if p.file != nil {
+		return p.file.Close()
+	}
+	return audit("remove", "", func() error { return os.Remove(p.path.underlying) }, p.path.underlying)
+}
+
+// Mkfifo creates a FIFO special file at the path with the permission as mkfifo(3).  The permission is masked by the
+// umask.  Since Windows does not have FIFO files, it returns UnsupportedError on Windows.  Use CreatePipe() for
+// portable code.  It always accesses the OS filesystem.
+//
+// Example:
+//	a, _ := abspath.New("/tmp/myapp.fifo")
+//	if err := a.Mkfifo(0600); err != nil {
+//		panic(err)
+//	}
+func (a AbsPath) Mkfifo(perm os.FileMode) (err error) {
+	defer annotate(&err, "create FIFO", a)
+	return audit("mkfifo", fmt.Sprintf("perm=%#o", perm), func() error {
+		return mkfifo(a.underlying, perm)
+	}, a.underlying)
+}
+
+// CreatePipe creates a named pipe at the path for inter-process communication.  On Unix, a FIFO file is created by
+// Mkfifo().  On Windows, a named pipe is created with CreateNamedPipe() and the path must be in the pipe namespace like
+// '\\.\pipe\name'.  The permission is ignored on Windows.  Since a named pipe on Windows exists only while its server
+// end is open, the returned NamedPipe must be kept open while the pipe is used.  On platforms which do not support
+// named pipes, it returns UnsupportedError.
+//
+// Example:
+//	name := "/tmp/myapp.pipe"
+//	if runtime.GOOS == "windows" {
+//		name = `\\.\pipe\myapp`
+//	}
+//	a, _ := abspath.New(name)
+//	p, err := a.CreatePipe(0600)
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer p.Close()
+func (a AbsPath) CreatePipe(perm os.FileMode) (_ *NamedPipe, err error) {
+	defer annotate(&err, "create pipe", a)
+	var f *os.File
+	err = audit("createpipe", fmt.Sprintf("perm=%#o", perm), func() error {
+		var err error
+		f, err = createPipe(a.underlying, perm)
+		return err
+	}, a.underlying)
+	if err != nil {
+		return nil, err
+	}
+	return &NamedPipe{a, f}, nil
+}
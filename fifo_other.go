@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+// +build !unix,!windows
+
+package abspath
+
+import (
+	"os"
+)
+
+func mkfifo(p string, perm os.FileMode) error {
+	return &UnsupportedError{"Mkfifo", nil}
+}
+
+func createPipe(p string, perm os.FileMode) (*os.File, error) {
+	return nil, &UnsupportedError{"CreatePipe", nil}
+}
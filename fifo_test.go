@@ -0,0 +1,109 @@
+package abspath
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestMkfifo(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+	p := root.Join("fifo")
+
+	err := p.Mkfifo(0600)
+	if runtime.GOOS == "windows" || runtime.GOOS == "plan9" || runtime.GOOS == "js" {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Fatalf("Unsupported error should be returned but got %v", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := os.Lstat(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode()&os.ModeNamedPipe == 0 {
+		t.Fatalf("FIFO should be created but got mode %s", s.Mode())
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- ioutil.WriteFile(p.String(), []byte("hello"), 0)
+	}()
+	b, err := ioutil.ReadFile(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("Unexpected data via FIFO: %q", b)
+	}
+
+	if err := p.Mkfifo(0600); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Already-exists error should be returned but got %v", err)
+	}
+}
+
+func TestCreatePipe(t *testing.T) {
+	var p AbsPath
+	switch runtime.GOOS {
+	case "plan9", "js":
+		root := makeTree(t, map[string]string{})
+		if _, err := root.Join("pipe").CreatePipe(0600); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Unsupported error should be returned but got %v", err)
+		}
+		return
+	case "windows":
+		var err error
+		p, err = New(fmt.Sprintf(`\\.\pipe\abspath-test-%d`, os.Getpid()))
+		if err != nil {
+			t.Fatal(err)
+		}
+	default:
+		p = makeTree(t, map[string]string{}).Join("pipe")
+	}
+
+	np, err := p.CreatePipe(0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if np.Path() != p {
+		t.Errorf("Unexpected path: %s", np.Path())
+	}
+	if (np.File() != nil) != isWindows {
+		t.Errorf("Server end should be returned only on Windows: %v", np.File())
+	}
+
+	if isWindows {
+		c, err := os.OpenFile(p.String(), os.O_RDWR, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Close()
+	} else {
+		s, err := os.Lstat(p.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Mode()&os.ModeNamedPipe == 0 {
+			t.Errorf("FIFO should be created but got mode %s", s.Mode())
+		}
+	}
+
+	if err := np.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !isWindows {
+		if _, err := os.Lstat(p.String()); !os.IsNotExist(err) {
+			t.Errorf("FIFO should be removed on close: %v", err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build unix
+// +build unix
+
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func mkfifo(p string, perm os.FileMode) error {
+	if err := unix.Mkfifo(p, uint32(perm.Perm())); err != nil {
+		return &os.PathError{Op: "mkfifo", Path: p, Err: err}
+	}
+	return nil
+}
+
+func createPipe(p string, perm os.FileMode) (*os.File, error) {
+	return nil, mkfifo(p, perm)
+}
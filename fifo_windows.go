@@ -0,0 +1,40 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+// pipeBufferSize is the size of input and output buffers of a named pipe.
+const pipeBufferSize = 64 * 1024
+
+func mkfifo(p string, perm os.FileMode) error {
+	return &UnsupportedError{"Mkfifo", errors.New("no FIFO file on Windows. use CreatePipe() instead")}
+}
+
+func createPipe(p string, perm os.FileMode) (*os.File, error) {
+	if !strings.HasPrefix(strings.ToLower(p), `\\.\pipe\`) {
+		return nil, &os.PathError{Op: "createpipe", Path: p, Err: errors.New(`named pipe must be under \\.\pipe\`)}
+	}
+	name, err := windows.UTF16PtrFromString(p)
+	if err != nil {
+		return nil, &os.PathError{Op: "createpipe", Path: p, Err: err}
+	}
+	h, err := windows.CreateNamedPipe(
+		name,
+		windows.PIPE_ACCESS_DUPLEX|windows.FILE_FLAG_FIRST_PIPE_INSTANCE,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT,
+		1,
+		pipeBufferSize,
+		pipeBufferSize,
+		0,
+		nil,
+	)
+	if err != nil {
+		return nil, &os.PathError{Op: "createpipe", Path: p, Err: err}
+	}
+	return os.NewFile(uintptr(h), p), nil
+}
@@ -0,0 +1,15 @@
+package abspath
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the pair of device and qid path which identifies the file.
+func fileID(p string, info os.FileInfo) (fileKey, bool) {
+	d, ok := info.Sys().(*syscall.Dir)
+	if !ok {
+		return fileKey{}, false
+	}
+	return fileKey{dev: uint64(d.Type)<<32 | uint64(d.Dev), ino: d.Qid.Path}, true
+}
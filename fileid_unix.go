@@ -0,0 +1,18 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package abspath
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID returns the pair of device and inode numbers which identifies the file.
+func fileID(p string, info os.FileInfo) (fileKey, bool) {
+	s, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}, false
+	}
+	return fileKey{dev: uint64(s.Dev), ino: uint64(s.Ino)}, true
+}
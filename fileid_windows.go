@@ -0,0 +1,34 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// fileID returns the pair of volume serial number and file index which identifies the file.
+func fileID(p string, info os.FileInfo) (fileKey, bool) {
+	u, err := windows.UTF16PtrFromString(p)
+	if err != nil {
+		return fileKey{}, false
+	}
+	h, err := windows.CreateFile(
+		u,
+		0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE|windows.FILE_SHARE_DELETE,
+		nil,
+		windows.OPEN_EXISTING,
+		windows.FILE_FLAG_BACKUP_SEMANTICS,
+		0,
+	)
+	if err != nil {
+		return fileKey{}, false
+	}
+	defer windows.CloseHandle(h)
+
+	var d windows.ByHandleFileInformation
+	if err := windows.GetFileInformationByHandle(h, &d); err != nil {
+		return fileKey{}, false
+	}
+	return fileKey{dev: uint64(d.VolumeSerialNumber), ino: uint64(d.FileIndexHigh)<<32 | uint64(d.FileIndexLow)}, true
+}
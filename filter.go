@@ -0,0 +1,29 @@
+package abspath
+
+import (
+	"path"
+)
+
+// matchPatterns returns true when one of the glob patterns matches the slash-separated relative path or its base name.
+// Patterns are interpreted by path.Match() after expanding braces like '*.{go,mod}'.  An error is returned when some
+// pattern is malformed.
+func matchPatterns(patterns []string, rel string) (bool, error) {
+	base := path.Base(rel)
+	expanded := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		expanded = append(expanded, expandBraces(p, true)...)
+	}
+	for _, p := range expanded {
+		m, err := path.Match(p, rel)
+		if err != nil {
+			return false, err
+		}
+		if m {
+			return true, nil
+		}
+		if m, _ = path.Match(p, base); m {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,123 @@
+package abspath
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Set parses the string as a path and sets it to the receiver.  The string is expanded by ExpandFrom() so a relative
+// path and a path starting with '~' are accepted.  With String() and Type() methods, *AbsPath implements flag.Value
+// of the standard library and pflag.Value used by cobra.
+//
+// Example:
+//	var dir abspath.AbsPath
+//	flag.Var(&dir, "dir", "Path to output directory")
+//	// With pflag
+//	pflag.Var(&dir, "dir", "Path to output directory")
+func (a *AbsPath) Set(s string) error {
+	p, err := ExpandFrom(s)
+	if err != nil {
+		return err
+	}
+	*a = p
+	return nil
+}
+
+// Type returns the name of the type shown in help messages of pflag.
+func (a AbsPath) Type() string {
+	return "path"
+}
+
+// CompleteOptions is a set of options for CompletePath() function.
+type CompleteOptions struct {
+	// DirsOnly makes CompletePath() suggest only directories.
+	DirsOnly bool
+	// Extensions is a list of file extensions like ".go" to suggest.  Directories are always suggested.  When it is
+	// empty, all files are suggested.
+	Extensions []string
+}
+
+func (o *CompleteOptions) accept(name string, dir bool) bool {
+	if dir {
+		return true
+	}
+	if o.DirsOnly {
+		return false
+	}
+	if len(o.Extensions) == 0 {
+		return true
+	}
+	ext := filepath.Ext(name)
+	for _, e := range o.Extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// CompletePath returns candidates of paths to complete the partial input for shell completion.  Candidates keep the
+// input as typed by the user (for example, a relative path or a path starting with '~') and directories end with a
+// path separator.  Hidden files are suggested only when the input of the last component starts with '.'.  opts can be
+// nil.
+//
+// Example:
+//	cmd.RegisterFlagCompletionFunc("dir", func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+//		cs, err := abspath.CompletePath(toComplete, &abspath.CompleteOptions{DirsOnly: true})
+//		if err != nil {
+//			return nil, cobra.ShellCompDirectiveError
+//		}
+//		return cs, cobra.ShellCompDirectiveNoSpace
+//	})
+func CompletePath(partial string, opts *CompleteOptions) ([]string, error) {
+	if opts == nil {
+		opts = &CompleteOptions{}
+	}
+
+	i := strings.LastIndexFunc(partial, func(r rune) bool { return r == '/' || r == filepath.Separator })
+	dir, prefix := partial[:i+1], partial[i+1:]
+	if dir == "" && partial == "~" {
+		dir, prefix = "~"+string(filepath.Separator), ""
+	}
+	d := dir
+	if d == "" {
+		d = "."
+	}
+	p, err := ExpandFrom(d)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := fsys()
+	entries, err := fs.ReadDir(p.underlying)
+	if err != nil {
+		if os.IsNotExist(err) || isNotDir(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	cs := []string{}
+	for _, e := range entries {
+		n := e.Name()
+		if !strings.HasPrefix(n, prefix) || (n[0] == '.' && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+		isDir := e.IsDir()
+		if e.Mode()&os.ModeSymlink != 0 {
+			if s, err := fs.Stat(p.Join(n).underlying); err == nil {
+				isDir = s.IsDir()
+			}
+		}
+		if !opts.accept(n, isDir) {
+			continue
+		}
+		c := dir + n
+		if isDir {
+			c += string(filepath.Separator)
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
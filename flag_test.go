@@ -0,0 +1,92 @@
+package abspath
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFlagValue(t *testing.T) {
+	var p AbsPath
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&p, "dir", "directory")
+
+	abs := fixAbsPath(filepath.FromSlash("/path/to/dir"))
+	if err := fs.Parse([]string{"-dir", abs}); err != nil {
+		t.Fatal(err)
+	}
+	if p.String() != abs {
+		t.Errorf("Wanted %q but got %q", abs, p)
+	}
+
+	if err := p.Set("relative"); err != nil {
+		t.Fatal(err)
+	}
+	cwd, _ := Getwd()
+	if p != cwd.Join("relative") {
+		t.Errorf("Relative path should be expanded: %q", p)
+	}
+
+	if err := p.Set(""); err == nil {
+		t.Error("Empty path should cause an error")
+	}
+	if p.Type() != "path" {
+		t.Errorf("Unexpected type name: %q", p.Type())
+	}
+}
+
+func TestCompletePath(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"src/main.go":   "",
+		"src/util.go":   "",
+		"src/README.md": "",
+		"script.sh":     "",
+		".hidden":       "",
+		"docs/":         "",
+	})
+	sep := string(filepath.Separator)
+	r := root.String() + sep
+
+	for _, c := range []struct {
+		partial string
+		opts    *CompleteOptions
+		want    []string
+	}{
+		{r + "s", nil, []string{r + "script.sh", r + "src" + sep}},
+		{r, nil, []string{r + "docs" + sep, r + "script.sh", r + "src" + sep}},
+		{r + ".", nil, []string{r + ".hidden"}},
+		{r + "s", &CompleteOptions{DirsOnly: true}, []string{r + "src" + sep}},
+		{r + "src" + sep, &CompleteOptions{Extensions: []string{".go"}}, []string{r + "src" + sep + "main.go", r + "src" + sep + "util.go"}},
+		{r + "x", nil, []string{}},
+		{r + "missing" + sep + "x", nil, []string{}},
+		{r + "script.sh" + sep, nil, []string{}},
+	} {
+		have, err := CompletePath(c.partial, c.opts)
+		if err != nil {
+			t.Errorf("Completion for %q failed: %v", c.partial, err)
+			continue
+		}
+		if !reflect.DeepEqual(c.want, have) {
+			t.Errorf("Completion for %q should be %v but got %v", c.partial, c.want, have)
+		}
+	}
+
+	// Relative input is kept as typed
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root.String()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+	have, err := CompletePath("sr", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"src" + sep}; !reflect.DeepEqual(want, have) {
+		t.Errorf("Completion for relative path should be %v but got %v", want, have)
+	}
+}
@@ -0,0 +1,141 @@
+package abspath
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AppendTo appends the path to the byte slice and returns the extended slice.  It is useful to build log lines or
+// serialized data in a reused buffer without allocating an intermediate string.
+//
+// Example:
+//	buf := make([]byte, 0, 4096)
+//	for _, p := range paths {
+//		buf = p.AppendTo(buf[:0])
+//		buf = append(buf, '\n')
+//		w.Write(buf)
+//	}
+func (a AbsPath) AppendTo(dst []byte) []byte {
+	return append(dst, a.underlying...)
+}
+
+// AppendQuotedTo appends the path quoted as a Go string literal to the byte slice and returns the extended slice.  It
+// is equivalent to strconv.AppendQuote().
+//
+// Example:
+//	a, _ := abspath.New("/path/to/my file")
+//	buf := a.AppendQuotedTo([]byte("path="))
+//	fmt.Println(string(buf)) // => path="/path/to/my file"
+func (a AbsPath) AppendQuotedTo(dst []byte) []byte {
+	return strconv.AppendQuote(dst, a.underlying)
+}
+
+// Len returns the length of the path in bytes.  It is useful to check limits like PATH_MAX or to pre-size buffers.
+//
+// Example:
+//	if a.Len() >= 4096 {
+//		return errors.New("path is too long")
+//	}
+func (a AbsPath) Len() int {
+	return len(a.underlying)
+}
+
+// Bytes returns a copy of the path as a byte slice.  Modifying the returned slice does not affect the path.
+func (a AbsPath) Bytes() []byte {
+	return []byte(a.underlying)
+}
+
+// WriteTo writes the path to the writer.  It implements io.WriterTo interface.  When the writer implements
+// io.StringWriter, the path is written without converting it to a byte slice.
+//
+// Example:
+//	for _, p := range paths {
+//		p.WriteTo(w)
+//		io.WriteString(w, "\n")
+//	}
+func (a AbsPath) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, a.underlying)
+	return int64(n), err
+}
+
+// scanQuoted reads a double-quoted Go string literal from the scan state and returns the unquoted string.
+func scanQuoted(state fmt.ScanState) (string, error) {
+	state.SkipSpace()
+	r, _, err := state.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	if r != '"' {
+		return "", errors.New("quoted path must start with '\"'")
+	}
+	var b strings.Builder
+	b.WriteRune(r)
+	escaped := false
+	for {
+		r, _, err := state.ReadRune()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+		b.WriteRune(r)
+		if escaped {
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		if r == '"' {
+			break
+		}
+	}
+	return strconv.Unquote(b.String())
+}
+
+// Scan reads an absolute path from the input.  It implements fmt.Scanner interface so that fmt.Sscan(), fmt.Fscan()
+// and so on can read AbsPath values directly.  With verbs %v and %s, a token separated by white spaces is read.  With
+// verb %q, a double-quoted Go string literal is read so that the path can contain white spaces.  The token must
+// represent an absolute path.  Otherwise it returns an error.
+//
+// Example:
+//	var (
+//		size int
+//		p    abspath.AbsPath
+//	)
+//	_, err := fmt.Sscan("1024 /path/to/file", &size, &p)
+func (a *AbsPath) Scan(state fmt.ScanState, verb rune) error {
+	var s string
+	switch verb {
+	case 'v', 's':
+		tok, err := state.Token(true, func(r rune) bool { return !unicode.IsSpace(r) })
+		if err != nil {
+			return err
+		}
+		if len(tok) == 0 {
+			return io.ErrUnexpectedEOF
+		}
+		s = string(tok)
+	case 'q':
+		q, err := scanQuoted(state)
+		if err != nil {
+			return err
+		}
+		s = q
+	default:
+		return fmt.Errorf("unsupported verb %%%c for scanning absolute path", verb)
+	}
+
+	p, err := New(s)
+	if err != nil {
+		return err
+	}
+	*a = p
+	return nil
+}
@@ -0,0 +1,113 @@
+package abspath
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestAppendTo(t *testing.T) {
+	s := filepath.FromSlash(fixAbsPath("/path/to/my file\t.txt"))
+	a, _ := New(s)
+
+	b := a.AppendTo([]byte("path="))
+	if string(b) != "path="+s {
+		t.Errorf("Unexpected result: %q", b)
+	}
+	b = a.AppendQuotedTo([]byte("path="))
+	if string(b) != "path="+strconv.Quote(s) {
+		t.Errorf("Unexpected quoted result: %q", b)
+	}
+
+	buf := make([]byte, 0, 256)
+	if n := testing.AllocsPerRun(100, func() {
+		buf = a.AppendTo(buf[:0])
+		buf = a.AppendQuotedTo(buf)
+	}); n != 0 {
+		t.Errorf("Appending to buffer with enough capacity should not allocate but allocated %v times", n)
+	}
+}
+
+func TestLenBytes(t *testing.T) {
+	s := filepath.FromSlash(fixAbsPath("/path/to/ファイル"))
+	a, _ := New(s)
+	if a.Len() != len(s) {
+		t.Errorf("Length should be %d but got %d", len(s), a.Len())
+	}
+	b := a.Bytes()
+	if string(b) != s {
+		t.Errorf("Bytes should be %q but got %q", s, b)
+	}
+	b[0] = 'x'
+	if a.String() != s {
+		t.Errorf("Modifying returned bytes should not affect the path: %q", a)
+	}
+}
+
+type failingWriter struct{}
+
+func (w failingWriter) Write(b []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriteTo(t *testing.T) {
+	s := filepath.FromSlash(fixAbsPath("/path/to/file"))
+	a, _ := New(s)
+
+	var _ io.WriterTo = a
+	var b bytes.Buffer
+	n, err := a.WriteTo(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(s)) || b.String() != s {
+		t.Errorf("Unexpected written content: %q (%d bytes)", b.String(), n)
+	}
+
+	if _, err := a.WriteTo(failingWriter{}); err == nil {
+		t.Error("Error from writer should be returned")
+	}
+}
+
+func TestScan(t *testing.T) {
+	s := filepath.FromSlash(fixAbsPath("/path/to/file"))
+	var (
+		n int
+		p AbsPath
+	)
+	if _, err := fmt.Sscan("42 "+s+" rest", &n, &p); err != nil {
+		t.Fatal(err)
+	}
+	if n != 42 || p.String() != s {
+		t.Errorf("Unexpected scanned values: %d %q", n, p)
+	}
+
+	var q AbsPath
+	spaced := filepath.FromSlash(fixAbsPath("/path/to/my file"))
+	if _, err := fmt.Sscanf("path: "+strconv.Quote(spaced), "path: %q", &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.String() != spaced {
+		t.Errorf("Unexpected scanned quoted path: %q", q)
+	}
+
+	for _, c := range []struct {
+		input  string
+		format string
+	}{
+		{"relative/path", "%v"},
+		{"", "%s"},
+		{s, "%d"},
+		{s, "%q"},
+		{`"` + s, "%q"},
+	} {
+		var r AbsPath
+		if _, err := fmt.Sscanf(c.input, c.format, &r); err == nil {
+			t.Errorf("Scanning %q with %s should fail but got %q", c.input, c.format, r)
+		}
+	}
+}
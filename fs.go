@@ -0,0 +1,191 @@
+package abspath
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// File is an interface of an opened file returned from FS.  *os.File satisfies this interface.
+type File interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.Seeker
+	io.Closer
+	// Name returns the name of the file as passed to Open() or OpenFile().
+	Name() string
+	// Stat returns the file info of the file.
+	Stat() (os.FileInfo, error)
+	// Sync commits the content of the file to the storage.
+	Sync() error
+}
+
+// FS is an interface of filesystem operations used by methods of AbsPath which access the filesystem.  All paths
+// passed to the methods are absolute and cleaned.  By default, the real OS filesystem is used.  Another implementation
+// such as a fake filesystem for tests can be set by SetFS().  Note that some operations which require OS-specific
+// features (file locks, native atomic swap and watching with OS notifications) always access the OS filesystem.
+type FS interface {
+	// Stat is equivalent to os.Stat().
+	Stat(name string) (os.FileInfo, error)
+	// Lstat is equivalent to os.Lstat().
+	Lstat(name string) (os.FileInfo, error)
+	// Open is equivalent to os.Open().
+	Open(name string) (File, error)
+	// OpenFile is equivalent to os.OpenFile().
+	OpenFile(name string, flag int, perm os.FileMode) (File, error)
+	// ReadDir is equivalent to ioutil.ReadDir().  Returned entries must be sorted by their names.
+	ReadDir(name string) ([]os.FileInfo, error)
+	// Readlink is equivalent to os.Readlink().
+	Readlink(name string) (string, error)
+	// Symlink is equivalent to os.Symlink().
+	Symlink(oldname, newname string) error
+	// Mkdir is equivalent to os.Mkdir().
+	Mkdir(name string, perm os.FileMode) error
+	// MkdirAll is equivalent to os.MkdirAll().
+	MkdirAll(name string, perm os.FileMode) error
+	// Remove is equivalent to os.Remove().
+	Remove(name string) error
+	// RemoveAll is equivalent to os.RemoveAll().
+	RemoveAll(name string) error
+	// Rename is equivalent to os.Rename().
+	Rename(oldpath, newpath string) error
+	// Chmod is equivalent to os.Chmod().
+	Chmod(name string, mode os.FileMode) error
+	// Chtimes is equivalent to os.Chtimes().
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+type osFS struct{}
+
+func (osFS) Stat(name string) (os.FileInfo, error)  { return os.Stat(name) }
+func (osFS) Lstat(name string) (os.FileInfo, error) { return os.Lstat(name) }
+func (osFS) Open(name string) (File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+func (osFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := os.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+func (osFS) ReadDir(name string) ([]os.FileInfo, error)   { return ioutil.ReadDir(name) }
+func (osFS) Readlink(name string) (string, error)         { return os.Readlink(name) }
+func (osFS) Symlink(oldname, newname string) error        { return os.Symlink(oldname, newname) }
+func (osFS) Mkdir(name string, perm os.FileMode) error    { return os.Mkdir(name, perm) }
+func (osFS) MkdirAll(name string, perm os.FileMode) error { return os.MkdirAll(name, perm) }
+func (osFS) Remove(name string) error                     { return os.Remove(name) }
+func (osFS) RemoveAll(name string) error                  { return os.RemoveAll(name) }
+func (osFS) Rename(oldpath, newpath string) error         { return os.Rename(oldpath, newpath) }
+func (osFS) Chmod(name string, mode os.FileMode) error    { return os.Chmod(name, mode) }
+func (osFS) Chtimes(name string, a, m time.Time) error    { return os.Chtimes(name, a, m) }
+
+// OSFS is an FS which accesses the real OS filesystem.  It is used by default.
+var OSFS FS = osFS{}
+
+// fsHolder wraps FS since atomic.Value requires values of the same concrete type.
+type fsHolder struct {
+	fs FS
+}
+
+var currentFS atomic.Value
+
+func init() {
+	currentFS.Store(fsHolder{OSFS})
+}
+
+// rawFS returns the FS set by SetFS() without interception by the middlewares, the audit hook and the metrics receiver.
+func rawFS() FS {
+	return currentFS.Load().(fsHolder).fs
+}
+
+// fsys returns the FS currently used by this package.  When middlewares, an audit hook or a metrics receiver are set,
+// operations on the returned FS are passed to them.
+func fsys() FS {
+	f := rawFS()
+	if intercepted() {
+		return interceptFS{f}
+	}
+	return f
+}
+
+// usesOSFS returns whether the OS filesystem is currently used.
+func usesOSFS() bool {
+	_, ok := rawFS().(osFS)
+	return ok
+}
+
+// SetFS sets the FS used by all methods which access the filesystem and returns the previous one.  When nil is
+// given, OSFS is set.  It is intended to inject a fake filesystem in tests.  Since the FS is shared in the whole
+// process, it should be set before starting filesystem operations.
+//
+// Example:
+//	prev := abspath.SetFS(fake)
+//	defer abspath.SetFS(prev)
+func SetFS(f FS) FS {
+	if f == nil {
+		f = OSFS
+	}
+	return currentFS.Swap(fsHolder{f}).(fsHolder).fs
+}
+
+// CurrentFS returns the FS currently used by this package.
+func CurrentFS() FS {
+	return rawFS()
+}
+
+// walk is the same as filepath.Walk() but accesses the filesystem via the current FS.
+func walk(root string, fn filepath.WalkFunc) (err error) {
+	defer observe("walk", time.Now(), &err)
+	if usesOSFS() && !intercepted() {
+		return filepath.Walk(root, fn)
+	}
+	f := fsys()
+	info, err := f.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkFS(f, root, info, fn)
+	}
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func walkFS(f FS, p string, info os.FileInfo, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(p, info, nil)
+	}
+
+	entries, err := f.ReadDir(p)
+	err1 := fn(p, info, err)
+	if err != nil || err1 != nil {
+		return err1
+	}
+
+	for _, e := range entries {
+		child := filepath.Join(p, e.Name())
+		s, err := f.Lstat(child)
+		if err != nil {
+			if err := fn(child, s, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		if err := walkFS(f, child, s, fn); err != nil {
+			if !s.IsDir() || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
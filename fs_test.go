@@ -0,0 +1,92 @@
+package abspath
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// recordingFS delegates all operations to the OS filesystem and records paths passed to some of them
+type recordingFS struct {
+	FS
+	opened []string
+}
+
+func (f *recordingFS) Open(name string) (File, error) {
+	f.opened = append(f.opened, name)
+	return f.FS.Open(name)
+}
+
+func TestSetFS(t *testing.T) {
+	r := &recordingFS{FS: OSFS}
+	prev := SetFS(r)
+	if prev != OSFS {
+		t.Fatalf("OS filesystem should be used by default but got %v", prev)
+	}
+	defer SetFS(prev)
+
+	if CurrentFS() != r {
+		t.Fatalf("Set filesystem is not returned from CurrentFS(): %v", CurrentFS())
+	}
+
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	p := root.Join("a.txt")
+	if err := p.CopyFile(root.Join("b.txt"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.opened) == 0 || r.opened[0] != p.String() {
+		t.Fatalf("File was not opened via the set filesystem: %v", r.opened)
+	}
+
+	if SetFS(nil) != r {
+		t.Fatal("Previous filesystem should be returned")
+	}
+	if CurrentFS() != OSFS {
+		t.Fatal("OS filesystem should be set when nil is given")
+	}
+}
+
+func TestWalkViaFS(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"a.txt":        "",
+		"b/c.txt":      "",
+		"b/d/e.txt":    "",
+		"b/skip/f.txt": "",
+		"g/":           "",
+	})
+
+	collect := func() []string {
+		ps := []string{}
+		err := root.Walk(func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() && info.Name() == "skip" {
+				return filepath.SkipDir
+			}
+			ps = append(ps, p)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ps
+	}
+
+	want := collect()
+	prev := SetFS(&recordingFS{FS: OSFS})
+	defer SetFS(prev)
+	have := collect()
+
+	if !reflect.DeepEqual(want, have) {
+		t.Fatalf("Walk via FS should be the same as filepath.Walk: wanted %v but got %v", want, have)
+	}
+
+	err := root.Join("not-exist").Walk(func(p string, info os.FileInfo, err error) error {
+		return err
+	})
+	if !os.IsNotExist(err) {
+		t.Fatalf("Not existing root should cause an error: %v", err)
+	}
+}
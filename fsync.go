@@ -0,0 +1,71 @@
+package abspath
+
+import (
+	"os"
+	"runtime"
+)
+
+// syncFile opens the file at the path and commits its content to the storage.
+func syncFile(p string, flag int) error {
+	f, err := fsys().OpenFile(p, flag, 0)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Sync commits the content of the file at the path to the storage as fsync(2).  Data written by other functions such
+// as ioutil.WriteFile() may stay in the OS cache until it is called.  Note that the directory entry of a newly created
+// or renamed file is not persisted by this method.  Call SyncDir() as well for durable writes.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/data.json")
+//	if err := ioutil.WriteFile(a.String(), data, 0644); err != nil {
+//		panic(err)
+//	}
+//	if err := a.Sync(); err != nil {
+//		panic(err)
+//	}
+//	if err := a.SyncDir(); err != nil {
+//		panic(err)
+//	}
+func (a AbsPath) Sync() (err error) {
+	defer annotate(&err, "sync", a)
+	flag := os.O_RDONLY
+	if runtime.GOOS == "windows" {
+		// FlushFileBuffers() requires write access
+		flag = os.O_WRONLY
+	}
+	return syncFile(a.underlying, flag)
+}
+
+// SyncDir commits the directory containing the path to the storage so that creating, renaming or removing the entry
+// at the path survives a crash.  Durable writes need to sync both the file and its parent directory.  Since Windows
+// does not support syncing directories, it does nothing on Windows.  When the path is the root directory, the root
+// directory itself is synced.
+//
+// Example:
+//	tmp, _ := a.WithExt(".tmp")
+//	if err := ioutil.WriteFile(tmp.String(), data, 0644); err != nil {
+//		panic(err)
+//	}
+//	if err := tmp.Sync(); err != nil {
+//		panic(err)
+//	}
+//	if err := os.Rename(tmp.String(), a.String()); err != nil {
+//		panic(err)
+//	}
+//	if err := a.SyncDir(); err != nil {
+//		panic(err)
+//	}
+func (a AbsPath) SyncDir() (err error) {
+	defer annotate(&err, "sync directory", a)
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	return syncFile(a.Dir().underlying, os.O_RDONLY)
+}
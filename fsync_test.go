@@ -0,0 +1,51 @@
+package abspath
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestSyncFile(t *testing.T) {
+	root := makeTree(t, map[string]string{"dir/a.txt": "hello"})
+	p := root.Join("dir", "a.txt")
+
+	if err := p.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SyncDir(); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.Join("dir").Sync(); err != nil && !isWindows {
+		t.Fatal(err)
+	}
+
+	if b, err := ioutil.ReadFile(p.String()); err != nil || string(b) != "hello" {
+		t.Errorf("Content should not be changed: %q %v", b, err)
+	}
+
+	if err := root.Join("missing").Sync(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+	if !isWindows {
+		if err := root.Join("missing", "a.txt").SyncDir(); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Not-exist error should be returned but got %v", err)
+		}
+	}
+}
+
+func TestSyncFileOtherFS(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	p := root.Join("a.txt")
+
+	prev := SetFS(&recordingFS{FS: OSFS})
+	defer SetFS(prev)
+
+	if err := p.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SyncDir(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,186 @@
+package abspath
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExpandBraces expands bash-style brace expressions like '{a,b,c}' in the pattern.  Braces can be nested and multiple
+// braces are expanded in order like bash.  Braces without any comma such as '{}' or '{a}' and unbalanced braces are
+// kept as they are.  Braces and commas can be escaped with '\' except on Windows, as filepath.Match() does.
+//
+// Example:
+//	abspath.ExpandBraces("*.{go,mod,sum}")  // => []string{"*.go", "*.mod", "*.sum"}
+//	abspath.ExpandBraces("{a,b}/{c,d{e,f}}") // => []string{"a/c", "a/de", "a/df", "b/c", "b/de", "b/df"}
+func ExpandBraces(pattern string) []string {
+	return expandBraces(pattern, filepath.Separator != '\\')
+}
+
+// matchBrace returns the index of the close brace matching the open brace at i and whether the braces contain a comma
+// at the top level.  When there is no matching close brace, it returns -1.
+func matchBrace(s string, i int, escape bool) (int, bool) {
+	depth, comma := 0, false
+	for j := i; j < len(s); j++ {
+		switch c := s[j]; {
+		case escape && c == '\\':
+			j++
+		case c == '{':
+			depth++
+		case c == ',' && depth == 1:
+			comma = true
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return j, comma
+			}
+		}
+	}
+	return -1, false
+}
+
+// findBraces returns the indices of the first expandable open brace and its matching close brace.  When there is no
+// expandable brace, it returns -1 as the first index.
+func findBraces(s string, escape bool) (int, int) {
+	for i := 0; i < len(s); i++ {
+		if escape && s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] != '{' {
+			continue
+		}
+		if j, comma := matchBrace(s, i, escape); j >= 0 && comma {
+			return i, j
+		}
+	}
+	return -1, -1
+}
+
+// splitAlternatives splits the content of braces by commas at the top level.
+func splitAlternatives(s string, escape bool) []string {
+	alts := []string{}
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case escape && c == '\\':
+			i++
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			alts = append(alts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(alts, s[start:])
+}
+
+func expandBraces(pattern string, escape bool) []string {
+	start, end := findBraces(pattern, escape)
+	if start < 0 {
+		return []string{pattern}
+	}
+
+	prefix := pattern[:start]
+	suffixes := expandBraces(pattern[end+1:], escape)
+	ret := []string{}
+	for _, alt := range splitAlternatives(pattern[start+1:end], escape) {
+		for _, a := range expandBraces(alt, escape) {
+			for _, s := range suffixes {
+				ret = append(ret, prefix+a+s)
+			}
+		}
+	}
+	return ret
+}
+
+func isSeparatorRune(r rune) bool {
+	return r < 0x80 && os.IsPathSeparator(uint8(r))
+}
+
+func hasGlobMeta(s string) bool {
+	magic := `*?[`
+	if filepath.Separator != '\\' {
+		magic = `*?[\`
+	}
+	return strings.ContainsAny(s, magic)
+}
+
+// globIn returns paths matching the pattern components under the directory.  Filesystem errors are ignored as
+// filepath.Glob() does.
+func globIn(dir string, comps []string) ([]string, error) {
+	if len(comps) == 0 {
+		return []string{dir}, nil
+	}
+
+	c := comps[0]
+	if !hasGlobMeta(c) {
+		p := filepath.Join(dir, c)
+		if _, err := fsys().Lstat(p); err != nil {
+			return nil, nil
+		}
+		return globIn(p, comps[1:])
+	}
+
+	// Check the pattern is not malformed
+	if _, err := filepath.Match(c, ""); err != nil {
+		return nil, err
+	}
+	entries, err := fsys().ReadDir(dir)
+	if err != nil {
+		return nil, nil
+	}
+	ret := []string{}
+	for _, e := range entries {
+		if m, _ := filepath.Match(c, e.Name()); !m {
+			continue
+		}
+		ps, err := globIn(filepath.Join(dir, e.Name()), comps[1:])
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ps...)
+	}
+	return ret, nil
+}
+
+// Glob returns paths under the directory which match the pattern.  The pattern is relative to the path and is
+// interpreted by filepath.Match() for each path component after expanding braces by ExpandBraces().  Matched paths
+// for each expanded pattern are sorted by name and appended in the order of the expansion.  The same path is included
+// only once.  As filepath.Glob(), filesystem errors are ignored and the only possible error is
+// filepath.ErrBadPattern.
+//
+// Example:
+//	dir, _ := abspath.ExpandFrom("~/repo")
+//	files, err := dir.Glob("*.{go,mod,sum}")
+//	// files => [~/repo/main.go, ~/repo/go.mod, ~/repo/go.sum]
+func (a AbsPath) Glob(pattern string) (_ []AbsPath, err error) {
+	defer annotate(&err, "glob in", a)
+	ret := []AbsPath{}
+	seen := map[string]struct{}{}
+	for _, p := range ExpandBraces(pattern) {
+		comps := []string{}
+		for _, c := range strings.FieldsFunc(p, isSeparatorRune) {
+			if c != "." {
+				comps = append(comps, c)
+			}
+		}
+
+		ps, err := globIn(a.underlying, comps)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(ps)
+		for _, s := range ps {
+			if _, ok := seen[s]; ok {
+				continue
+			}
+			seen[s] = struct{}{}
+			ret = append(ret, AbsPath{s})
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,109 @@
+package abspath
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandBraces(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  []string
+	}{
+		{"*.go", []string{"*.go"}},
+		{"*.{go,mod,sum}", []string{"*.go", "*.mod", "*.sum"}},
+		{"{a,b}/{c,d{e,f}}", []string{"a/c", "a/de", "a/df", "b/c", "b/de", "b/df"}},
+		{"x{,y}", []string{"x", "xy"}},
+		{"{a,b}{1,2}", []string{"a1", "a2", "b1", "b2"}},
+		{"{}", []string{"{}"}},
+		{"{a}", []string{"{a}"}},
+		{"{a}{b,c}", []string{"{a}b", "{a}c"}},
+		{"{a,b", []string{"{a,b"}},
+		{"a,b}", []string{"a,b}"}},
+		{"{{a,b}", []string{"{a", "{b"}},
+		{"{a,{b,c}}", []string{"a", "b", "c"}},
+		{"", []string{""}},
+	} {
+		if have := ExpandBraces(tc.input); !reflect.DeepEqual(have, tc.want) {
+			t.Errorf("Expanding %q should be %#v but actually %#v", tc.input, tc.want, have)
+		}
+	}
+}
+
+func TestExpandBracesEscape(t *testing.T) {
+	if have := expandBraces(`\{a,b}`, true); !reflect.DeepEqual(have, []string{`\{a,b}`}) {
+		t.Errorf("Escaped brace should not be expanded: %#v", have)
+	}
+	if have := expandBraces(`{a\,b,c}`, true); !reflect.DeepEqual(have, []string{`a\,b`, "c"}) {
+		t.Errorf("Escaped comma should not split alternatives: %#v", have)
+	}
+	if have := expandBraces(`{a\,b}`, false); !reflect.DeepEqual(have, []string{`a\`, "b"}) {
+		t.Errorf("Backslash should not escape when escaping is disabled: %#v", have)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"main.go":        "",
+		"util.go":        "",
+		"go.mod":         "",
+		"go.sum":         "",
+		"README.md":      "",
+		"cmd/foo/foo.go": "",
+		"cmd/bar/bar.go": "",
+		"cmd/bar/bar.md": "",
+		"empty/":         "",
+	})
+
+	for _, tc := range []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.go", []string{"main.go", "util.go"}},
+		{"*.{go,mod,sum}", []string{"main.go", "util.go", "go.mod", "go.sum"}},
+		{"{go.mod,*.mod}", []string{"go.mod"}},
+		{"cmd/*/*.go", []string{"cmd/bar/bar.go", "cmd/foo/foo.go"}},
+		{"cmd/bar/*.{md,go}", []string{"cmd/bar/bar.md", "cmd/bar/bar.go"}},
+		{"./cmd/{foo,bar}", []string{"cmd/foo", "cmd/bar"}},
+		{"README.md", []string{"README.md"}},
+		{"missing.go", []string{}},
+		{"main.go/*", []string{}},
+		{"empty/*", []string{}},
+		{"", []string{""}},
+	} {
+		want := make([]AbsPath, 0, len(tc.want))
+		for _, w := range tc.want {
+			want = append(want, root.Join(filepath.FromSlash(w)))
+		}
+		have, err := root.Glob(tc.pattern)
+		if err != nil {
+			t.Fatal(tc.pattern, err)
+		}
+		assertPathsEqual(t, want, have)
+	}
+
+	if _, err := root.Glob("*.{go,[}"); !errors.Is(err, filepath.ErrBadPattern) {
+		t.Errorf("ErrBadPattern should be returned but actually %v", err)
+	}
+}
+
+func TestMatchPatternsBraces(t *testing.T) {
+	for _, tc := range []struct {
+		rel  string
+		want bool
+	}{
+		{"foo/bar.go", true},
+		{"foo/go.mod", true},
+		{"foo/bar.md", false},
+	} {
+		m, err := matchPatterns([]string{"*.{go,mod}"}, tc.rel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m != tc.want {
+			t.Errorf("Matching %q should be %v but actually %v", tc.rel, tc.want, m)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+module github.com/rhysd/abspath
+
+go 1.20
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/cespare/xxhash/v2 v2.2.0
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/go-git/go-billy/v5 v5.5.0
+	github.com/spf13/afero v1.11.0
+	go.opentelemetry.io/otel v1.21.0
+	go.opentelemetry.io/otel/trace v1.21.0
+	golang.org/x/sys v0.24.0
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/cyphar/filepath-securejoin v0.2.4 // indirect
@@ -0,0 +1,166 @@
+package abspath
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// GuardError is an error returned when Guard refuses an operation on a path.
+type GuardError struct {
+	// Op is the name of the refused operation.
+	Op string
+	// Path is the path which the operation tried to touch.
+	Path AbsPath
+	// Reason describes why the operation was refused.
+	Reason string
+}
+
+func (err *GuardError) Error() string {
+	return fmt.Sprintf("Refused to %s '%s': %s", err.Op, err.Path.underlying, err.Reason)
+}
+
+// Guard is a safety belt for destructive operations on computed paths.  Operations through it are allowed only for
+// paths under the allowed root directories.  In addition, filesystem roots, the home directory and their ancestors are
+// never touched even if they are under the allowed roots.  Symbolic links in parent directories are resolved before
+// checking so that a path cannot escape from the roots via symbolic links.  Guard is safe for concurrent use.
+//
+// Example:
+//	cache, _ := abspath.ExpandFrom("~/.cache/myapp")
+//	g, err := abspath.NewGuard(cache)
+//	if err != nil {
+//		panic(err)
+//	}
+//	// Even if a bug makes the path empty or '/', nothing outside the cache is removed
+//	err = g.RemoveAll(cache.Join(computedName))
+type Guard struct {
+	roots     []AbsPath
+	resolved  []AbsPath
+	protected []AbsPath
+}
+
+// NewGuard creates a new Guard which allows operations under the roots.  At least one root is necessary.  It returns
+// an error when some root is a filesystem root, the home directory or an ancestor of the home directory since such
+// roots would not protect anything.
+func NewGuard(roots ...AbsPath) (*Guard, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("at least one root is necessary for guard")
+	}
+
+	g := &Guard{}
+	if h, err := HomeDir(); err == nil {
+		g.protected = append(g.protected, h)
+		if r, err := h.Resolve(nil); err == nil && r != h {
+			g.protected = append(g.protected, r)
+		}
+	}
+
+	for _, r := range roots {
+		if reason := g.dangerous(r); reason != "" {
+			return nil, &GuardError{"guard", r, reason}
+		}
+		res, err := r.Resolve(nil)
+		if err != nil {
+			return nil, err
+		}
+		g.roots = append(g.roots, r)
+		g.resolved = append(g.resolved, res)
+	}
+	return g, nil
+}
+
+// Roots returns the allowed root directories.
+func (g *Guard) Roots() []AbsPath {
+	return append([]AbsPath{}, g.roots...)
+}
+
+func guardContains(a, b AbsPath) bool {
+	if caseInsensitiveOS {
+		return a.ContainsPathFold(b)
+	}
+	return a.ContainsPath(b)
+}
+
+// dangerous returns the reason when the path must never be touched.
+func (g *Guard) dangerous(p AbsPath) string {
+	if p.IsRoot() {
+		return "it is a filesystem root"
+	}
+	for _, h := range g.protected {
+		if guardContains(p, h) {
+			return fmt.Sprintf("it is the home directory or its ancestor '%s'", h.underlying)
+		}
+	}
+	return ""
+}
+
+// underRoots returns whether the path is under one of the roots.  When allowRoot is true, a root itself is also allowed.
+func underRoots(roots []AbsPath, p AbsPath, allowRoot bool) bool {
+	for _, r := range roots {
+		if !guardContains(r, p) {
+			continue
+		}
+		if allowRoot || !guardContains(p, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Guard) check(op string, p AbsPath, allowRoot bool) error {
+	if p.underlying == "" {
+		return &GuardError{op, p, "path is empty"}
+	}
+	if reason := g.dangerous(p); reason != "" {
+		return &GuardError{op, p, reason}
+	}
+	if !underRoots(g.roots, p, allowRoot) {
+		return &GuardError{op, p, "it is not under the allowed roots"}
+	}
+
+	// The path itself is not resolved since removing a symbolic link does not touch its target
+	d, err := p.Dir().Resolve(nil)
+	if err != nil {
+		return err
+	}
+	p = d.JoinOne(filepath.Base(p.underlying))
+	if reason := g.dangerous(p); reason != "" {
+		return &GuardError{op, p, reason}
+	}
+	if !underRoots(g.resolved, p, allowRoot) {
+		return &GuardError{op, p, "it is not under the allowed roots after resolving symbolic links"}
+	}
+	return nil
+}
+
+// Check returns *GuardError when a destructive operation on the path is not allowed.  The path must be under one of
+// the allowed roots and must not be a root itself.
+func (g *Guard) Check(p AbsPath) error {
+	return g.check("touch", p, false)
+}
+
+// Remove removes the file or the empty directory at the path after checking it is allowed.
+func (g *Guard) Remove(p AbsPath) (err error) {
+	defer annotate(&err, "remove", p)
+	if err := g.check("remove", p, false); err != nil {
+		return err
+	}
+	return fsys().Remove(p.underlying)
+}
+
+// RemoveAll removes the path and all its children after checking it is allowed.
+func (g *Guard) RemoveAll(p AbsPath) (err error) {
+	defer annotate(&err, "remove", p)
+	if err := g.check("remove", p, false); err != nil {
+		return err
+	}
+	return fsys().RemoveAll(p.underlying)
+}
+
+// Sync is the same as Sync() function but checks the destination is allowed before syncing.  Unlike Remove() and
+// RemoveAll(), the destination can be an allowed root itself since the root is not removed.
+func (g *Guard) Sync(src, dst AbsPath, opts *SyncOptions) (*SyncResult, error) {
+	if err := g.check("sync", dst, true); err != nil {
+		return nil, err
+	}
+	return Sync(src, dst, opts)
+}
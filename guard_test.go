@@ -0,0 +1,130 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestGuardRemove(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "", "dir/b.txt": "", "dir/c/d.txt": ""})
+	outside := makeTree(t, map[string]string{"keep.txt": ""})
+
+	g, err := NewGuard(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs := g.Roots(); len(rs) != 1 || rs[0] != root {
+		t.Errorf("Unexpected roots: %v", rs)
+	}
+
+	if err := g.Remove(root.Join("a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.RemoveAll(root.Join("dir")); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"a.txt", "dir"} {
+		if _, err := os.Lstat(root.Join(p).String()); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed: %v", p, err)
+		}
+	}
+
+	for _, p := range []AbsPath{
+		root,
+		root.Dir(),
+		outside.Join("keep.txt"),
+		root.Join("..", "other"),
+		{""},
+	} {
+		err := g.RemoveAll(p)
+		var gerr *GuardError
+		if !errors.As(err, &gerr) {
+			t.Errorf("GuardError should be returned for %q but got %v", p, err)
+		}
+	}
+	if _, err := os.Stat(outside.Join("keep.txt").String()); err != nil {
+		t.Errorf("File outside roots should not be removed: %v", err)
+	}
+}
+
+func TestGuardDangerousPaths(t *testing.T) {
+	root := makeTree(t, nil)
+	volRoot := Volume{root.VolumeName()}.Root()
+
+	if _, err := NewGuard(); err == nil {
+		t.Error("Error should occur without roots")
+	}
+	if _, err := NewGuard(volRoot); err == nil {
+		t.Error("Filesystem root should not be allowed as root")
+	}
+
+	h, err := HomeDir()
+	if err != nil {
+		t.Skip(err)
+	}
+	var gerr *GuardError
+	if _, err := NewGuard(h); !errors.As(err, &gerr) {
+		t.Errorf("Home directory should not be allowed as root: %v", err)
+	}
+	if !h.IsRoot() {
+		if _, err := NewGuard(h.Dir()); !errors.As(err, &gerr) {
+			t.Errorf("Ancestor of home directory should not be allowed as root: %v", err)
+		}
+	}
+
+	// Home directory is protected even if it is under an allowed root
+	g := &Guard{roots: []AbsPath{h.Dir()}, resolved: []AbsPath{h.Dir()}, protected: []AbsPath{h}}
+	if err := g.Check(h); !errors.As(err, &gerr) {
+		t.Errorf("Home directory should be protected: %v", err)
+	}
+}
+
+func TestGuardSymlink(t *testing.T) {
+	if isWindows {
+		t.Skip("Symlink requires privilege on Windows")
+	}
+	root := makeTree(t, map[string]string{})
+	outside := makeTree(t, map[string]string{"secret/file": ""})
+	link := root.Join("link")
+	if err := os.Symlink(outside.Join("secret").String(), link.String()); err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGuard(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gerr *GuardError
+	if err := g.RemoveAll(link.Join("file")); !errors.As(err, &gerr) {
+		t.Errorf("Path escaping via symlink should be refused: %v", err)
+	}
+	if err := g.Remove(link); err != nil {
+		t.Errorf("Symlink itself can be removed: %v", err)
+	}
+	if _, err := os.Stat(outside.Join("secret", "file").String()); err != nil {
+		t.Errorf("Target of symlink should not be removed: %v", err)
+	}
+}
+
+func TestGuardSync(t *testing.T) {
+	src := makeTree(t, map[string]string{"a.txt": "a"})
+	root := makeTree(t, map[string]string{"stale.txt": ""})
+	g, err := NewGuard(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.Sync(src, root, &SyncOptions{Delete: true}); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("a.txt"), "a")
+	if _, err := os.Stat(root.Join("stale.txt").String()); !os.IsNotExist(err) {
+		t.Errorf("Stale file should be deleted: %v", err)
+	}
+
+	var gerr *GuardError
+	if _, err := g.Sync(root, src, &SyncOptions{Delete: true}); !errors.As(err, &gerr) {
+		t.Errorf("Sync to outside of roots should be refused: %v", err)
+	}
+}
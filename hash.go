@@ -0,0 +1,79 @@
+package abspath
+
+import (
+	"context"
+	"crypto"
+	"encoding/hex"
+	"fmt"
+)
+
+// hashBufferSize is a size of buffer used for streaming file contents through hash functions.
+const hashBufferSize = 64 * 1024
+
+// Hash calculates the digest of the file content at the path with the given hash function.  The file is streamed
+// through the hash function so that large files are not loaded into memory at once.  The package implementing the
+// hash function must be linked into the binary (e.g. by importing crypto/sha256).  Otherwise an error is returned.
+//
+// Example:
+//	import _ "crypto/sha256"
+//
+//	a, _ := abspath.New("/path/to/file")
+//	sum, err := a.Hash(crypto.SHA256)
+func (a AbsPath) Hash(h crypto.Hash) ([]byte, error) {
+	return a.HashContext(context.Background(), h)
+}
+
+// HashContext is the same as Hash() but accepts a context.  Hashing is stopped between chunks when the context is done.
+func (a AbsPath) HashContext(ctx context.Context, h crypto.Hash) ([]byte, error) {
+	return a.hash(ctx, h, nil)
+}
+
+// HashOptions is a set of options for HashWithOptions() method.
+type HashOptions struct {
+	// RateLimiter limits the throughput of reading the file.  When it is nil, the throughput is not limited.
+	RateLimiter *RateLimiter
+}
+
+// HashWithOptions is the same as Hash() but hashing is customized with the options.  opts can be nil.
+//
+// Example:
+//	l := abspath.NewRateLimiter(0, 5*1024*1024)
+//	sum, err := a.HashWithOptions(crypto.SHA256, &abspath.HashOptions{RateLimiter: l})
+func (a AbsPath) HashWithOptions(h crypto.Hash, opts *HashOptions) ([]byte, error) {
+	return a.hash(context.Background(), h, opts)
+}
+
+func (a AbsPath) hash(ctx context.Context, h crypto.Hash, opts *HashOptions) (_ []byte, err error) {
+	defer annotate(&err, "hash", a)
+	if opts == nil {
+		opts = &HashOptions{}
+	}
+	_, span := startSpan(ctx, "abspath.Hash", pathAttr("abspath.path", a), SpanAttr{"abspath.hash", fmt.Sprint(h)})
+	var n int64
+	defer func() {
+		span.SetAttributes(int64Attr("abspath.bytes", n))
+		span.End(err)
+	}()
+
+	if !h.Available() {
+		return nil, fmt.Errorf("hash function %v is not available. import the package implementing it", h)
+	}
+
+	var sum []byte
+	sum, n, err = hashFile(ctx, a.underlying, h.New(), opts.RateLimiter)
+	return sum, err
+}
+
+// HashString is the same as Hash() but returns the digest as a hex encoded string.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/file")
+//	sum, err := a.HashString(crypto.SHA256)
+//	fmt.Println(sum) // e.g. "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+func (a AbsPath) HashString(h crypto.Hash) (string, error) {
+	b, err := a.Hash(h)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,42 @@
+package abspath
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	content := strings.Repeat("abcdefg", hashBufferSize/3)
+	root := makeTree(t, map[string]string{"file": content})
+	a := root.Join("file")
+
+	b := sha256.Sum256([]byte(content))
+	expected := hex.EncodeToString(b[:])
+
+	actual, err := a.Hash(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(actual) != expected {
+		t.Errorf("Expected %x but actually %x", b, actual)
+	}
+
+	s, err := a.HashString(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != expected {
+		t.Errorf("Expected %s but actually %s", expected, s)
+	}
+
+	if _, err := root.Join("not-exist").Hash(crypto.SHA256); err == nil {
+		t.Errorf("Not existing file must cause an error")
+	}
+
+	if _, err := a.HashString(crypto.Hash(0)); err == nil {
+		t.Errorf("Unavailable hash function must cause an error")
+	}
+}
@@ -0,0 +1,19 @@
+package abspath
+
+import (
+	"os"
+)
+
+// homeEnv is the environment variable which holds the home directory.
+const homeEnv = "HOME"
+
+// homeDir returns the home directory.  On GOOS=js, the user database is not available so it is obtained from $HOME
+// (or %USERPROFILE% when running on Windows host) environment variable.
+func homeDir() (string, error) {
+	for _, env := range []string{homeEnv, "USERPROFILE"} {
+		if h := os.Getenv(env); h != "" {
+			return h, nil
+		}
+	}
+	return "", &UnsupportedError{"HomeDir", nil}
+}
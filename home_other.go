@@ -0,0 +1,26 @@
+//go:build !plan9 && !js
+// +build !plan9,!js
+
+package abspath
+
+import (
+	"os/user"
+	"runtime"
+)
+
+// homeEnv is the environment variable which holds the home directory.
+var homeEnv = func() string {
+	if runtime.GOOS == "windows" {
+		return "USERPROFILE"
+	}
+	return "HOME"
+}()
+
+// homeDir returns the home directory of the current user.
+func homeDir() (string, error) {
+	u, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+	return u.HomeDir, nil
+}
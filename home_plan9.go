@@ -0,0 +1,19 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+)
+
+// homeEnv is the environment variable which holds the home directory.  Plan 9 uses lower-case $home.
+const homeEnv = "home"
+
+// homeDir returns the home directory.  On Plan 9, it is obtained from $home environment variable since the user
+// database is not available.
+func homeDir() (string, error) {
+	h := os.Getenv(homeEnv)
+	if h == "" {
+		return "", errors.New("$home environment variable is not set")
+	}
+	return h, nil
+}
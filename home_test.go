@@ -0,0 +1,65 @@
+package abspath
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestHomeDirPlan9(t *testing.T) {
+	if runtime.GOOS != "plan9" {
+		t.Skip("$home is only used on Plan 9")
+	}
+
+	prev, ok := os.LookupEnv("home")
+	defer func() {
+		if ok {
+			os.Setenv("home", prev)
+		} else {
+			os.Unsetenv("home")
+		}
+	}()
+
+	os.Setenv("home", "/usr/glenda")
+	h, err := HomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.String() != "/usr/glenda" {
+		t.Errorf("Home directory should be taken from $home but actually %q", h)
+	}
+	a, err := ExpandFrom("~/lib/profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != "/usr/glenda/lib/profile" {
+		t.Errorf("Unexpected expanded path %q", a)
+	}
+
+	os.Unsetenv("home")
+	if _, err := HomeDir(); err == nil {
+		t.Errorf("Error should occur when $home is not set")
+	}
+}
+
+func TestHomeEnv(t *testing.T) {
+	want := "HOME"
+	switch runtime.GOOS {
+	case "windows":
+		want = "USERPROFILE"
+	case "plan9":
+		want = "home"
+	}
+	if homeEnv != want {
+		t.Errorf("Environment variable for home directory should be %q but actually %q", want, homeEnv)
+	}
+
+	h, err := homeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filepath.IsAbs(h) {
+		t.Errorf("Home directory should be absolute: %q", h)
+	}
+}
@@ -0,0 +1,43 @@
+package abspath
+
+// InputPath is an absolute path which remembers the original spelling given by the user.  AbsPath always holds a
+// clean path, so a trailing separator, redundant separators and '.' or '..' components in the user's input are lost.
+// InputPath keeps the input so that error messages and UIs can echo exactly what the user typed while internal logic
+// uses the embedded AbsPath.  All methods of AbsPath are available on InputPath and they work on the clean form.
+type InputPath struct {
+	AbsPath
+	original string
+}
+
+// NewInputPath is the same as New() but the returned value remembers the given string as its original spelling.
+//
+// Example:
+//	p, err := abspath.NewInputPath("/path/to/dir/")
+//	p.String()   // => "/path/to/dir"
+//	p.Original() // => "/path/to/dir/"
+func NewInputPath(s string) (InputPath, error) {
+	a, err := New(s)
+	if err != nil {
+		return InputPath{}, err
+	}
+	return InputPath{a, s}, nil
+}
+
+// ExpandInputPath is the same as ExpandFrom() but the returned value remembers the given string as its original
+// spelling.
+//
+// Example:
+//	p, err := abspath.ExpandInputPath("~/Documents/")
+//	p.Original() // => "~/Documents/"
+func ExpandInputPath(s string) (InputPath, error) {
+	a, err := ExpandFrom(s)
+	if err != nil {
+		return InputPath{}, err
+	}
+	return InputPath{a, s}, nil
+}
+
+// Original returns the path exactly as the user spelled it.
+func (p InputPath) Original() string {
+	return p.original
+}
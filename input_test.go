@@ -0,0 +1,44 @@
+package abspath
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestInputPath(t *testing.T) {
+	in := filepath.FromSlash(fixAbsPath("/path/./to//dir/"))
+	p, err := NewInputPath(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Original() != in {
+		t.Errorf("Original should be %q but got %q", in, p.Original())
+	}
+	want := filepath.FromSlash(fixAbsPath("/path/to/dir"))
+	if p.String() != want {
+		t.Errorf("Clean form should be %q but got %q", want, p.String())
+	}
+	if p.Join("file").String() != filepath.Join(want, "file") {
+		t.Errorf("Methods of AbsPath should work on the clean form: %q", p.Join("file"))
+	}
+
+	if _, err := NewInputPath("relative/"); err == nil {
+		t.Error("Relative path should cause an error")
+	}
+
+	q, err := ExpandInputPath("relative/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Original() != "relative/" {
+		t.Errorf("Original should be kept: %q", q.Original())
+	}
+	cwd, _ := Getwd()
+	if q.AbsPath != cwd.Join("relative") {
+		t.Errorf("Relative path should be expanded: %q", q.AbsPath)
+	}
+
+	if _, err := ExpandInputPath(""); err == nil {
+		t.Error("Empty path should cause an error")
+	}
+}
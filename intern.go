@@ -0,0 +1,59 @@
+package abspath
+
+import (
+	"sync"
+)
+
+// Interner deduplicates paths so that equal paths share the same backing string.  Programs holding many paths
+// collected from large directory trees can reduce memory usage by interning them, since paths created separately
+// (for example, by walking the same tree twice) keep their own copies of strings.  Interned strings are kept alive
+// until Reset() is called.  The zero value is an empty interner ready to use.  It is safe for concurrent use.
+type Interner struct {
+	mu sync.Mutex
+	m  map[string]string
+}
+
+// Intern returns the path whose underlying string is shared with the equal path interned before.  When the path is
+// interned first time, it is stored and returned as-is.
+//
+// Example:
+//	var in abspath.Interner
+//	root.Walk(func(p string, info os.FileInfo, err error) error {
+//		a, _ := abspath.New(p)
+//		index = append(index, in.Intern(a))
+//		return nil
+//	})
+func (in *Interner) Intern(a AbsPath) AbsPath {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if s, ok := in.m[a.underlying]; ok {
+		return AbsPath{s}
+	}
+	if in.m == nil {
+		in.m = map[string]string{}
+	}
+	in.m[a.underlying] = a.underlying
+	return a
+}
+
+// Len returns the number of interned paths.
+func (in *Interner) Len() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return len(in.m)
+}
+
+// Reset forgets all interned paths so that they can be garbage collected.
+func (in *Interner) Reset() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.m = nil
+}
+
+var defaultInterner Interner
+
+// Intern interns the path with the global interner shared in the process.  See Interner for more details.  Note that
+// paths interned by this function are never released.  Use Interner to control lifetime of interned paths.
+func Intern(a AbsPath) AbsPath {
+	return defaultInterner.Intern(a)
+}
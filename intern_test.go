@@ -0,0 +1,50 @@
+package abspath
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func dataPtr(s string) uintptr {
+	return (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+}
+
+func TestInterner(t *testing.T) {
+	s := filepath.FromSlash(fixAbsPath("/path/to/file"))
+	// Build strings dynamically so that they do not share the same backing array
+	a, _ := New(strings.Repeat(s, 1))
+	b, _ := New(string(append([]byte{}, s...)))
+	if dataPtr(a.underlying) == dataPtr(b.underlying) {
+		t.Fatal("Test paths should not share the backing string")
+	}
+
+	var in Interner
+	ia := in.Intern(a)
+	ib := in.Intern(b)
+	if ia != a || ib != b {
+		t.Fatalf("Interned paths should be equal to the original paths: %q %q", ia, ib)
+	}
+	if dataPtr(ia.underlying) != dataPtr(ib.underlying) {
+		t.Error("Interned paths should share the backing string")
+	}
+	c, _ := FromSlash(fixAbsPath("/path/to/other"))
+	in.Intern(c)
+	if in.Len() != 2 {
+		t.Errorf("Interner should have 2 paths but has %d", in.Len())
+	}
+
+	in.Reset()
+	if in.Len() != 0 {
+		t.Errorf("Interner should be empty after reset but has %d paths", in.Len())
+	}
+	if dataPtr(in.Intern(b).underlying) != dataPtr(b.underlying) {
+		t.Error("Path interned first time after reset should be returned as-is")
+	}
+
+	if dataPtr(Intern(a).underlying) != dataPtr(Intern(b).underlying) {
+		t.Error("Global interner should share the backing string")
+	}
+}
@@ -0,0 +1,343 @@
+// Package lexical provides purely lexical operations on absolute paths.  Unlike abspath package, it imports neither os
+// nor os/user and never touches the filesystem or environment, so it can be used in environments where accessing the
+// OS is impossible or forbidden such as WebAssembly, TinyGo or policy evaluation.  Paths can be interpreted by the
+// rules of the host platform or an explicit platform.
+//
+// Example:
+//	p, err := lexical.New("/path/to/dir")
+//	p.Join("foo", "..", "bar").String() // => "/path/to/dir/bar"
+//	p.Dir().String()                    // => "/path/to"
+//	p.Segments()                        // => []string{"path", "to", "dir"}
+package lexical
+
+import (
+	"errors"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// Platform is a set of rules of paths on some platform.
+type Platform int
+
+const (
+	// Unix is the platform using '/' as a path separator without volume names.  Linux, macOS, BSDs, Plan 9 and so on
+	// follow the rules.
+	Unix Platform = iota
+	// Windows is the platform using '\' (and '/') as path separators with volume names like 'C:' or UNC paths like
+	// '\\server\share'.  Paths are compared in case-insensitive.
+	Windows
+)
+
+// HostPlatform is the platform where the program is running.
+var HostPlatform = func() Platform {
+	if runtime.GOOS == "windows" {
+		return Windows
+	}
+	return Unix
+}()
+
+// String returns the name of the platform.
+func (p Platform) String() string {
+	if p == Windows {
+		return "windows"
+	}
+	return "unix"
+}
+
+// Separator returns the path separator of the platform.
+func (p Platform) Separator() byte {
+	if p == Windows {
+		return '\\'
+	}
+	return '/'
+}
+
+func (p Platform) isSeparator(c byte) bool {
+	return c == '/' || (p == Windows && c == '\\')
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// volumeNameLen returns the length of the leading volume name.  Drive letters like 'C:', UNC paths like
+// '\\server\share' and device paths like '\\?\C:' or '\\?\UNC\server\share' are recognized on Windows.
+func (p Platform) volumeNameLen(s string) int {
+	if p != Windows || len(s) < 2 {
+		return 0
+	}
+	if s[1] == ':' && isLetter(s[0]) {
+		return 2
+	}
+	if !p.isSeparator(s[0]) || !p.isSeparator(s[1]) {
+		return 0
+	}
+
+	n, comps := 2, 2 // \\server\share
+	if len(s) >= 4 && (s[2] == '.' || s[2] == '?') && p.isSeparator(s[3]) {
+		n, comps = 4, 1 // \\?\C:
+		if len(s) >= 7 && strings.EqualFold(s[4:7], "UNC") && (len(s) == 7 || p.isSeparator(s[7])) {
+			n, comps = 8, 2 // \\?\UNC\server\share
+		}
+	}
+	for i := 0; i < comps; i++ {
+		start := n
+		for n < len(s) && !p.isSeparator(s[n]) {
+			n++
+		}
+		if n == start {
+			return 0
+		}
+		if i < comps-1 {
+			if n == len(s) {
+				return 0
+			}
+			n++
+		}
+	}
+	return n
+}
+
+// VolumeName returns the leading volume name of the path.  It is always empty on Unix.
+func (p Platform) VolumeName(s string) string {
+	return s[:p.volumeNameLen(s)]
+}
+
+// IsAbs returns whether the path is absolute on the platform.
+func (p Platform) IsAbs(s string) bool {
+	if p != Windows {
+		return strings.HasPrefix(s, "/")
+	}
+	l := p.volumeNameLen(s)
+	if l == 0 {
+		return false
+	}
+	if l == 2 && s[1] == ':' {
+		return len(s) > 2 && p.isSeparator(s[2])
+	}
+	return true
+}
+
+// Clean returns the shortest path equivalent to the given path by lexical processing on the platform.  It follows the
+// same rules as filepath.Clean() on the platform.
+func (p Platform) Clean(s string) string {
+	if p != Windows {
+		return path.Clean(s)
+	}
+	s = strings.ReplaceAll(s, "/", `\`)
+	l := p.volumeNameLen(s)
+	vol, rest := s[:l], s[l:]
+	if rest == "" && l > 2 {
+		// UNC volume like \\server\share
+		return vol
+	}
+	c := strings.ReplaceAll(path.Clean(strings.ReplaceAll(rest, `\`, "/")), "/", `\`)
+	return vol + c
+}
+
+// Join joins the elements with the separator of the platform and cleans the result.  Empty elements are ignored.  It
+// follows the same rules as filepath.Join() on the platform.
+func (p Platform) Join(elem ...string) string {
+	ss := make([]string, 0, len(elem))
+	for _, e := range elem {
+		if e != "" {
+			ss = append(ss, e)
+		}
+	}
+	if len(ss) == 0 {
+		return ""
+	}
+	return p.Clean(strings.Join(ss, string(p.Separator())))
+}
+
+// NotAbsolutePathError is an error returned when the given path is not absolute on the platform.
+type NotAbsolutePathError struct {
+	// Path is the given path.
+	Path string
+	// Platform is the platform whose rules were used.
+	Platform Platform
+}
+
+// Error returns an error message.
+func (err *NotAbsolutePathError) Error() string {
+	return "Not an absolute path on " + err.Platform.String() + ": '" + err.Path + "'"
+}
+
+// Path is an absolute path on a specific platform.  All methods are lexical and never access the filesystem.
+type Path struct {
+	platform   Platform
+	underlying string
+}
+
+// New creates a Path instance from a string following the rules of the host platform.  The string must represent an
+// absolute path.  Otherwise it returns an error.  The path is cleaned.
+func New(s string) (Path, error) {
+	return NewForPlatform(HostPlatform, s)
+}
+
+// NewForPlatform creates a Path instance from a string following the rules of the platform.  The string must
+// represent an absolute path on the platform.  Otherwise it returns an error.  The path is cleaned.
+//
+// Example:
+//	// Even on Linux
+//	p, err := lexical.NewForPlatform(lexical.Windows, `C:/Users/foo/../bar`)
+//	p.String()                 // => `C:\Users\bar`
+//	p.Join("AppData").String() // => `C:\Users\bar\AppData`
+func NewForPlatform(p Platform, s string) (Path, error) {
+	if !p.IsAbs(s) {
+		return Path{}, &NotAbsolutePathError{s, p}
+	}
+	return Path{p, p.Clean(s)}, nil
+}
+
+// Platform returns the platform of the path.
+func (p Path) Platform() Platform {
+	return p.platform
+}
+
+// String returns the path as string.
+func (p Path) String() string {
+	return p.underlying
+}
+
+// ToSlash returns the path whose separators are replaced with '/'.
+func (p Path) ToSlash() string {
+	if p.platform != Windows {
+		return p.underlying
+	}
+	return strings.ReplaceAll(p.underlying, `\`, "/")
+}
+
+// VolumeName returns the volume name of the path.  It is always empty on Unix.
+func (p Path) VolumeName() string {
+	return p.platform.VolumeName(p.underlying)
+}
+
+func (p Path) lastSeparator() (int, int) {
+	vol := p.platform.volumeNameLen(p.underlying)
+	i := len(p.underlying) - 1
+	for i >= vol && !p.platform.isSeparator(p.underlying[i]) {
+		i--
+	}
+	return i, vol
+}
+
+// IsRoot returns whether the path is a root directory of its volume.
+func (p Path) IsRoot() bool {
+	i, vol := p.lastSeparator()
+	return i < vol || i == len(p.underlying)-1
+}
+
+// Base returns the last component of the path.  For a root directory, it returns the separator.
+func (p Path) Base() string {
+	if p.IsRoot() {
+		return string(p.platform.Separator())
+	}
+	i, _ := p.lastSeparator()
+	return p.underlying[i+1:]
+}
+
+// Dir returns the parent directory of the path.  For a root directory, it returns the path itself.
+func (p Path) Dir() Path {
+	if p.IsRoot() {
+		return p
+	}
+	i, vol := p.lastSeparator()
+	if i == vol {
+		return Path{p.platform, p.underlying[:vol+1]}
+	}
+	return Path{p.platform, p.underlying[:i]}
+}
+
+// Ext returns the file name extension of the path.
+func (p Path) Ext() string {
+	s := p.underlying
+	for i := len(s) - 1; i >= 0 && !p.platform.isSeparator(s[i]); i-- {
+		if s[i] == '.' {
+			return s[i:]
+		}
+	}
+	return ""
+}
+
+// Join joins the elements to the path following the rules of the platform.
+func (p Path) Join(elem ...string) Path {
+	return Path{p.platform, p.platform.Join(append([]string{p.underlying}, elem...)...)}
+}
+
+// Segments returns the components of the path except for its volume name.
+func (p Path) Segments() []string {
+	s := p.underlying[p.platform.volumeNameLen(p.underlying):]
+	sep := string(p.platform.Separator())
+	s = strings.Trim(s, sep)
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, sep)
+}
+
+func (p Path) equalString(a, b string) bool {
+	if p.platform == Windows {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// Equal returns whether two paths are the same.  Paths on Windows are compared in case-insensitive.  Paths on
+// different platforms are never equal.
+func (p Path) Equal(q Path) bool {
+	return p.platform == q.platform && p.equalString(p.underlying, q.underlying)
+}
+
+// ContainsPath returns whether q is the same as p or under p.  Paths on Windows are compared in case-insensitive.
+//
+// Example:
+//	p, _ := lexical.NewForPlatform(lexical.Windows, `C:\Users`)
+//	q, _ := lexical.NewForPlatform(lexical.Windows, `c:\users\foo`)
+//	p.ContainsPath(q) // => true
+func (p Path) ContainsPath(q Path) bool {
+	if p.platform != q.platform || !p.equalString(p.VolumeName(), q.VolumeName()) {
+		return false
+	}
+	ps, qs := p.Segments(), q.Segments()
+	if len(ps) > len(qs) {
+		return false
+	}
+	for i, s := range ps {
+		if !p.equalString(s, qs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Rel returns the relative path from p to q following the rules of the platform.  It returns an error when the paths
+// are on different platforms or volumes.
+//
+// Example:
+//	p, _ := lexical.NewForPlatform(lexical.Windows, `C:\foo\bar`)
+//	q, _ := lexical.NewForPlatform(lexical.Windows, `C:\foo\baz\qux`)
+//	p.Rel(q) // => `..\baz\qux`
+func (p Path) Rel(q Path) (string, error) {
+	if p.platform != q.platform {
+		return "", errors.New("cannot make " + q.platform.String() + " path '" + q.underlying + "' relative to " + p.platform.String() + " path '" + p.underlying + "'")
+	}
+	if !p.equalString(p.VolumeName(), q.VolumeName()) {
+		return "", errors.New("cannot make '" + q.underlying + "' relative to '" + p.underlying + "' since their volumes are different")
+	}
+	ps, qs := p.Segments(), q.Segments()
+	i := 0
+	for i < len(ps) && i < len(qs) && p.equalString(ps[i], qs[i]) {
+		i++
+	}
+	rel := make([]string, 0, len(ps)-i+len(qs)-i)
+	for j := i; j < len(ps); j++ {
+		rel = append(rel, "..")
+	}
+	rel = append(rel, qs[i:]...)
+	if len(rel) == 0 {
+		return ".", nil
+	}
+	return strings.Join(rel, string(p.platform.Separator())), nil
+}
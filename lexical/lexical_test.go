@@ -0,0 +1,237 @@
+package lexical
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPlatformIsAbs(t *testing.T) {
+	for _, tc := range []struct {
+		platform Platform
+		input    string
+		want     bool
+	}{
+		{Unix, "/foo", true},
+		{Unix, "foo", false},
+		{Unix, `C:\foo`, false},
+		{Windows, `C:\foo`, true},
+		{Windows, `c:/foo`, true},
+		{Windows, `C:foo`, false},
+		{Windows, `C:`, false},
+		{Windows, `\foo`, false},
+		{Windows, `/foo`, false},
+		{Windows, `\\server\share\foo`, true},
+		{Windows, `\\server\share`, true},
+		{Windows, `\\server`, false},
+		{Windows, `\\?\C:\foo`, true},
+		{Windows, `\\?\UNC\server\share\foo`, true},
+	} {
+		if have := tc.platform.IsAbs(tc.input); have != tc.want {
+			t.Errorf("IsAbs(%q) on %s should be %v but actually %v", tc.input, tc.platform, tc.want, have)
+		}
+	}
+}
+
+func TestPlatformVolumeName(t *testing.T) {
+	for _, tc := range []struct {
+		platform Platform
+		input    string
+		want     string
+	}{
+		{Unix, "/foo", ""},
+		{Unix, `C:\foo`, ""},
+		{Windows, `C:\foo`, "C:"},
+		{Windows, `\\server\share\foo`, `\\server\share`},
+		{Windows, `//server/share/foo`, `//server/share`},
+		{Windows, `\\?\C:\foo`, `\\?\C:`},
+		{Windows, `\\?\UNC\server\share\foo`, `\\?\UNC\server\share`},
+		{Windows, `\foo`, ""},
+	} {
+		if have := tc.platform.VolumeName(tc.input); have != tc.want {
+			t.Errorf("VolumeName(%q) on %s should be %q but actually %q", tc.input, tc.platform, tc.want, have)
+		}
+	}
+}
+
+func TestPlatformCleanAndJoin(t *testing.T) {
+	for _, tc := range []struct {
+		platform Platform
+		input    string
+		want     string
+	}{
+		{Unix, "/foo//bar/../baz/", "/foo/baz"},
+		{Unix, `/foo\bar`, `/foo\bar`},
+		{Windows, `C:/foo//bar/../baz/`, `C:\foo\baz`},
+		{Windows, `C:\..\foo`, `C:\foo`},
+		{Windows, `C:\`, `C:\`},
+		{Windows, `\\server\share\foo\..`, `\\server\share\`},
+		{Windows, `\\server\share`, `\\server\share`},
+	} {
+		if have := tc.platform.Clean(tc.input); have != tc.want {
+			t.Errorf("Clean(%q) on %s should be %q but actually %q", tc.input, tc.platform, tc.want, have)
+		}
+	}
+
+	if j := Windows.Join(`C:\foo`, "", "bar/baz", `..\qux`); j != `C:\foo\bar\qux` {
+		t.Errorf("Unexpected joined Windows path %q", j)
+	}
+	if j := Unix.Join("/foo", "", "bar", "../baz"); j != "/foo/baz" {
+		t.Errorf("Unexpected joined Unix path %q", j)
+	}
+	if j := Unix.Join("", ""); j != "" {
+		t.Errorf("Joining empty elements should be empty but %q", j)
+	}
+}
+
+func TestPathWindows(t *testing.T) {
+	if _, err := NewForPlatform(Windows, "/foo"); err == nil {
+		t.Fatal("Error should occur for non-absolute Windows path")
+	}
+
+	p, err := NewForPlatform(Windows, `C:/Users/foo/../bar`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.String() != `C:\Users\bar` {
+		t.Errorf("Unexpected path %q", p)
+	}
+	if p.ToSlash() != "C:/Users/bar" {
+		t.Errorf("Unexpected slash path %q", p.ToSlash())
+	}
+	if p.VolumeName() != "C:" {
+		t.Errorf("Unexpected volume %q", p.VolumeName())
+	}
+	if j := p.Join("AppData", "Local"); j.String() != `C:\Users\bar\AppData\Local` {
+		t.Errorf("Unexpected joined path %q", j)
+	}
+	if b := p.Base(); b != "bar" {
+		t.Errorf("Unexpected base %q", b)
+	}
+	if d := p.Dir(); d.String() != `C:\Users` {
+		t.Errorf("Unexpected dir %q", d)
+	}
+	if d := p.Dir().Dir(); d.String() != `C:\` || !d.IsRoot() || d.Base() != `\` {
+		t.Errorf("Unexpected root %q", d)
+	}
+	if d := p.Dir().Dir().Dir(); d.String() != `C:\` {
+		t.Errorf("Dir of root should be root itself but %q", d)
+	}
+	if e := p.Join("a.tar.gz").Ext(); e != ".gz" {
+		t.Errorf("Unexpected ext %q", e)
+	}
+	if s := p.Segments(); !reflect.DeepEqual(s, []string{"Users", "bar"}) {
+		t.Errorf("Unexpected segments %#v", s)
+	}
+
+	q, _ := NewForPlatform(Windows, `c:\users\BAR\baz`)
+	if !p.ContainsPath(q) || q.ContainsPath(p) {
+		t.Errorf("%s should contain %s in case-insensitive", p, q)
+	}
+	if !p.Equal(q.Dir()) {
+		t.Errorf("%s should equal to %s", p, q.Dir())
+	}
+	r, err := q.Rel(p.Dir().Join("qux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != `..\..\qux` {
+		t.Errorf("Unexpected relative path %q", r)
+	}
+
+	d, _ := NewForPlatform(Windows, `D:\Users\bar`)
+	if p.ContainsPath(d) || p.Equal(d) {
+		t.Errorf("Paths on different volumes are not related: %s and %s", p, d)
+	}
+	if _, err := p.Rel(d); err == nil {
+		t.Errorf("Rel should fail for different volumes")
+	}
+
+	u, _ := NewForPlatform(Windows, `\\server\share\dir\file`)
+	if u.VolumeName() != `\\server\share` {
+		t.Errorf("Unexpected UNC volume %q", u.VolumeName())
+	}
+	if d := u.Dir().Dir(); d.String() != `\\server\share\` || !d.IsRoot() {
+		t.Errorf("Unexpected UNC root %q", d)
+	}
+}
+
+func TestPathUnix(t *testing.T) {
+	if _, err := NewForPlatform(Unix, `C:\foo`); err == nil {
+		t.Fatal("Error should occur for Windows path on Unix")
+	}
+
+	p, err := NewForPlatform(Unix, "/home/foo/../bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.String() != "/home/bar" || p.VolumeName() != "" {
+		t.Errorf("Unexpected path %q", p)
+	}
+	q, _ := NewForPlatform(Unix, "/HOME/bar")
+	if p.Equal(q) || p.ContainsPath(q) {
+		t.Errorf("Unix paths should be compared in case-sensitive: %s and %s", p, q)
+	}
+	if r, err := p.Rel(p); err != nil || r != "." {
+		t.Errorf("Unexpected relative path %q (%v)", r, err)
+	}
+	if d := p.Dir().Dir(); d.String() != "/" || !d.IsRoot() || d.Base() != "/" {
+		t.Errorf("Unexpected root %q", d)
+	}
+
+	w, _ := NewForPlatform(Windows, `C:\home\bar`)
+	if p.Equal(w) || p.ContainsPath(w) {
+		t.Errorf("Paths on different platforms are not related: %s and %s", p, w)
+	}
+	if _, err := p.Rel(w); err == nil {
+		t.Errorf("Rel should fail for different platforms")
+	}
+}
+
+func TestHostPlatformCompatibility(t *testing.T) {
+	for _, s := range []string{"/foo//bar/../baz/", "/", "/foo/./bar", "/../foo"} {
+		if HostPlatform == Windows {
+			s = "C:" + s
+		}
+		s = filepath.FromSlash(s)
+		if want, have := filepath.Clean(s), HostPlatform.Clean(s); want != have {
+			t.Errorf("Clean(%q) should be compatible with filepath.Clean: want %q but have %q", s, want, have)
+		}
+		if want, have := filepath.IsAbs(s), HostPlatform.IsAbs(s); want != have {
+			t.Errorf("IsAbs(%q) should be compatible with filepath.IsAbs: want %v but have %v", s, want, have)
+		}
+		p, err := New(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := filepath.Base(filepath.Clean(s)), p.Base(); want != have {
+			t.Errorf("Base of %q should be compatible with filepath.Base: want %q but have %q", s, want, have)
+		}
+		if want, have := filepath.Dir(filepath.Clean(s)), p.Dir().String(); want != have {
+			t.Errorf("Dir of %q should be compatible with filepath.Dir: want %q but have %q", s, want, have)
+		}
+	}
+
+	if _, err := New("foo"); err == nil {
+		t.Errorf("Error should occur for relative path")
+	} else if _, ok := err.(*NotAbsolutePathError); !ok {
+		t.Errorf("Unexpected error %T: %v", err, err)
+	}
+}
+
+func TestNoOSImports(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "lexical.go", nil, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range f.Imports {
+		p, _ := strconv.Unquote(i.Path.Value)
+		switch p {
+		case "os", "os/user", "path/filepath", "io/ioutil", "syscall", "fmt":
+			t.Errorf("lexical package must not import %q", p)
+		}
+	}
+}
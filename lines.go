@@ -0,0 +1,123 @@
+package abspath
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// LineIter is an iterator to read lines of a file one by one.  It is returned from LinesIter() method.  It must be
+// closed by Close() method after use.
+type LineIter struct {
+	file File
+	r    *bufio.Reader
+}
+
+// LinesIter opens the file and returns an iterator to read its lines one by one.  Lines are read in streaming so that
+// a large file is not loaded into memory at once.  There is no limit on length of lines.
+//
+// Example:
+//	it, err := a.LinesIter()
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer it.Close()
+//	for {
+//		l, err := it.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			panic(err)
+//		}
+//		fmt.Println(l)
+//	}
+func (a AbsPath) LinesIter() (_ *LineIter, err error) {
+	defer annotate(&err, "read lines of", a)
+	f, err := fsys().Open(a.underlying)
+	if err != nil {
+		return nil, err
+	}
+	return &LineIter{f, bufio.NewReader(f)}, nil
+}
+
+// Next returns the next line without the trailing newline ("\n" or "\r\n").  When all lines were read, it returns
+// io.EOF.  The last line is returned even if it does not end with a newline.
+func (it *LineIter) Next() (string, error) {
+	l, err := it.r.ReadString('\n')
+	if err == io.EOF {
+		if l == "" {
+			return "", io.EOF
+		}
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	l = strings.TrimSuffix(l, "\n")
+	return strings.TrimSuffix(l, "\r"), nil
+}
+
+// Close closes the file.
+func (it *LineIter) Close() error {
+	return it.file.Close()
+}
+
+// ReadLines reads the file and returns its lines without trailing newlines ("\n" or "\r\n").  A newline at the end of
+// the file does not make an empty last line.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/.gitignore")
+//	lines, err := a.ReadLines()
+func (a AbsPath) ReadLines() (_ []string, err error) {
+	defer annotate(&err, "read lines of", a)
+	it, err := a.LinesIter()
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	ls := []string{}
+	for {
+		l, err := it.Next()
+		if err == io.EOF {
+			return ls, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		ls = append(ls, l)
+	}
+}
+
+// WriteLines writes the lines to the file.  Each line is terminated with "\n".  When the file does not exist, it is
+// created with the permission.  Otherwise the file is truncated.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/list.txt")
+//	err := a.WriteLines([]string{"foo", "bar"}, 0644)
+func (a AbsPath) WriteLines(lines []string, perm os.FileMode) (err error) {
+	defer annotate(&err, "write lines to", a)
+	f, err := fsys().OpenFile(a.underlying, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	for _, l := range lines {
+		if _, err := w.WriteString(l); err != nil {
+			f.Close()
+			return err
+		}
+		if err := w.WriteByte('\n'); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
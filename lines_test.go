@@ -0,0 +1,107 @@
+package abspath
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadLines(t *testing.T) {
+	long := strings.Repeat("x", 100*1024)
+	root := makeTree(t, map[string]string{
+		"lf":       "foo\nbar\n",
+		"crlf":     "foo\r\nbar\r\n",
+		"no-eol":   "foo\nbar",
+		"empty":    "",
+		"blank":    "\n\nfoo\n\n",
+		"long":     long + "\n",
+		"only-eol": "\n",
+	})
+
+	for _, tc := range []struct {
+		file string
+		want []string
+	}{
+		{"lf", []string{"foo", "bar"}},
+		{"crlf", []string{"foo", "bar"}},
+		{"no-eol", []string{"foo", "bar"}},
+		{"empty", []string{}},
+		{"blank", []string{"", "", "foo", ""}},
+		{"long", []string{long}},
+		{"only-eol", []string{""}},
+	} {
+		have, err := root.Join(tc.file).ReadLines()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(have, tc.want) {
+			t.Errorf("Lines of %q should be %q but actually %q", tc.file, tc.want, have)
+		}
+	}
+
+	if _, err := root.Join("missing").ReadLines(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but %v", err)
+	}
+}
+
+func TestLinesIter(t *testing.T) {
+	root := makeTree(t, map[string]string{"f": "a\nb\nc"})
+	it, err := root.Join("f").LinesIter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	for _, want := range []string{"a", "b", "c"} {
+		l, err := it.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l != want {
+			t.Errorf("Expected %q but actually %q", want, l)
+		}
+	}
+	if _, err := it.Next(); err != io.EOF {
+		t.Errorf("EOF should be returned but %v", err)
+	}
+}
+
+func TestWriteLines(t *testing.T) {
+	root := makeTree(t, map[string]string{"exist": "previous content which is longer"})
+	for _, name := range []string{"new", "exist"} {
+		p := root.Join(name)
+		if err := p.WriteLines([]string{"foo", "", "bar"}, 0644); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(p.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != "foo\n\nbar\n" {
+			t.Errorf("Unexpected content of %s: %q", name, b)
+		}
+		ls, err := p.ReadLines()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ls, []string{"foo", "", "bar"}) {
+			t.Errorf("Written lines should be read as they are: %q", ls)
+		}
+	}
+
+	p := root.Join("empty")
+	if err := p.WriteLines(nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := os.Stat(p.String()); err != nil || s.Size() != 0 {
+		t.Errorf("Empty file should be created: %v %v", s, err)
+	}
+
+	if err := root.Join("missing", "file").WriteLines([]string{"a"}, 0644); err == nil {
+		t.Errorf("Error should occur when parent directory does not exist")
+	}
+}
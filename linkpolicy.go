@@ -0,0 +1,86 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SymlinkPolicy is a policy of how CopyDir() and Sync() handle symbolic links in the source tree.
+type SymlinkPolicy int
+
+const (
+	// SymlinkPreserve copies symbolic links as symbolic links with the same targets.  It is the default policy.
+	SymlinkPreserve SymlinkPolicy = iota
+	// SymlinkFollow copies the files and the directories which symbolic links point to instead of the links.  Broken
+	// links and links to their ancestor directories (which would make copying endless) are copied as links.
+	SymlinkFollow
+	// SymlinkRewrite copies symbolic links as symbolic links.  Absolute targets pointing inside the source tree are
+	// rewritten to point to the same relative paths inside the destination tree so that the copied tree does not refer
+	// to the source tree.  Relative targets and absolute targets outside the source tree are kept as they are.
+	SymlinkRewrite
+	// SymlinkReject makes copying fail with *SymlinkRejectedError when a symbolic link is found in the source tree.
+	SymlinkReject
+)
+
+var symlinkPolicyNames = []string{"preserve", "follow", "rewrite", "reject"}
+
+// String returns the name of the policy.
+func (p SymlinkPolicy) String() string {
+	if p < 0 || int(p) >= len(symlinkPolicyNames) {
+		return "unknown"
+	}
+	return symlinkPolicyNames[p]
+}
+
+// SymlinkRejectedError is an error returned when a symbolic link is found while copying with SymlinkReject policy.
+type SymlinkRejectedError struct {
+	// Path is the path of the symbolic link in the source tree.
+	Path AbsPath
+	// Target is the target of the symbolic link.
+	Target string
+}
+
+func (err *SymlinkRejectedError) Error() string {
+	return fmt.Sprintf("Refused to copy symbolic link '%s' to '%s'", err.Path.underlying, err.Target)
+}
+
+// follows returns whether symbolic links should be followed while walking the source tree.
+func (p SymlinkPolicy) follows() bool {
+	return p == SymlinkFollow
+}
+
+// target reads the symbolic link at the path in the source tree srcRoot and returns the target of the link to be
+// created in the destination tree dstRoot.
+func (p SymlinkPolicy) target(path, srcRoot, dstRoot string) (string, error) {
+	t, err := fsys().Readlink(path)
+	if err != nil {
+		return "", err
+	}
+	switch p {
+	case SymlinkReject:
+		return "", &SymlinkRejectedError{AbsPath{path}, t}
+	case SymlinkRewrite:
+		if !filepath.IsAbs(t) {
+			return t, nil
+		}
+		abs := AbsPath{filepath.Clean(t)}
+		if !containsPath(AbsPath{srcRoot}, abs, false) {
+			return t, nil
+		}
+		rel, err := filepath.Rel(srcRoot, abs.underlying)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dstRoot, rel), nil
+	}
+	return t, nil
+}
+
+// stat returns the function to get file info of entries in the source tree.
+func (p SymlinkPolicy) stat() func(string) (os.FileInfo, error) {
+	if p.follows() {
+		return fsys().Stat
+	}
+	return fsys().Lstat
+}
@@ -0,0 +1,128 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func makeLinkTree(t *testing.T) (AbsPath, AbsPath) {
+	root := makeTree(t, map[string]string{"src/a.txt": "a", "src/d/b.txt": "b", "outside.txt": "out"})
+	src := root.Join("src")
+	for name, target := range map[string]string{
+		"abs":  src.Join("d").String(),
+		"rel":  "a.txt",
+		"out":  root.Join("outside.txt").String(),
+		"loop": src.String(),
+	} {
+		if err := os.Symlink(target, src.Join(name).String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root, src
+}
+
+func assertLink(t *testing.T, p AbsPath, want string) {
+	t.Helper()
+	have, err := os.Readlink(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have != want {
+		t.Errorf("Target of '%s' should be %q but got %q", p, want, have)
+	}
+}
+
+func TestCopyDirSymlinkPolicies(t *testing.T) {
+	if isWindows {
+		t.Skip("Creating symbolic links requires privilege on Windows")
+	}
+	root, src := makeLinkTree(t)
+
+	dst := root.Join("preserve")
+	if err := src.CopyDir(dst, nil); err != nil {
+		t.Fatal(err)
+	}
+	assertLink(t, dst.Join("abs"), src.Join("d").String())
+	assertLink(t, dst.Join("rel"), "a.txt")
+
+	dst = root.Join("rewrite")
+	if err := src.CopyDir(dst, &CopyOptions{Symlinks: SymlinkRewrite}); err != nil {
+		t.Fatal(err)
+	}
+	assertLink(t, dst.Join("abs"), dst.Join("d").String())
+	assertLink(t, dst.Join("loop"), dst.String())
+	assertLink(t, dst.Join("rel"), "a.txt")
+	assertLink(t, dst.Join("out"), root.Join("outside.txt").String())
+
+	dst = root.Join("follow")
+	if err := src.CopyDir(dst, &CopyOptions{Symlinks: SymlinkFollow}); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, dst.Join("abs", "b.txt"), "b")
+	assertContent(t, dst.Join("rel"), "a")
+	assertContent(t, dst.Join("out"), "out")
+	for _, name := range []string{"abs", "rel", "out"} {
+		if s, err := os.Lstat(dst.Join(name).String()); err != nil || s.Mode()&os.ModeSymlink != 0 {
+			t.Errorf("%q should not be copied as symbolic link: %v", name, err)
+		}
+	}
+	// Link to the ancestor is not followed
+	assertLink(t, dst.Join("loop"), src.String())
+
+	err := src.CopyDir(root.Join("reject"), &CopyOptions{Symlinks: SymlinkReject})
+	var se *SymlinkRejectedError
+	if !errors.As(err, &se) {
+		t.Fatalf("SymlinkRejectedError should be returned but got %v", err)
+	}
+	if se.Path.Dir() != src {
+		t.Errorf("Unexpected link path: %s", se.Path)
+	}
+}
+
+func TestSyncSymlinkPolicies(t *testing.T) {
+	if isWindows {
+		t.Skip("Creating symbolic links requires privilege on Windows")
+	}
+	root, src := makeLinkTree(t)
+
+	dst := root.Join("rewrite")
+	for i := 0; i < 2; i++ {
+		res, err := Sync(src, dst, &SyncOptions{Symlinks: SymlinkRewrite})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && len(res.Copied) != 0 {
+			t.Errorf("Rewritten links should not be copied again: %v", res.Copied)
+		}
+	}
+	assertLink(t, dst.Join("abs"), dst.Join("d").String())
+
+	dst = root.Join("follow")
+	for i := 0; i < 2; i++ {
+		res, err := Sync(src, dst, &SyncOptions{Symlinks: SymlinkFollow, Delete: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 1 && (len(res.Copied) != 0 || len(res.Deleted) != 0) {
+			t.Errorf("Nothing should be done on second sync: %+v", res)
+		}
+	}
+	assertContent(t, dst.Join("abs", "b.txt"), "b")
+	assertContent(t, dst.Join("rel"), "a")
+
+	if _, err := Sync(src, root.Join("reject"), &SyncOptions{Symlinks: SymlinkReject}); err == nil {
+		t.Error("Symbolic link should be rejected")
+	}
+}
+
+func TestSymlinkPolicyString(t *testing.T) {
+	for p := SymlinkPreserve; p <= SymlinkReject; p++ {
+		if s := p.String(); s == "unknown" {
+			t.Errorf("Name of policy %d should be defined", int(p))
+		}
+	}
+	if s := SymlinkPolicy(-1).String(); s != "unknown" {
+		t.Errorf("Unknown policy should be %q but got %q", "unknown", s)
+	}
+}
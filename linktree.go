@@ -0,0 +1,96 @@
+package abspath
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// linker is implemented by FS which can create hard links.
+type linker interface {
+	Link(oldname, newname string) error
+}
+
+// hardLinkFunc returns the function to create hard links on the current FS.  It returns false when the FS cannot create
+// hard links.
+func hardLinkFunc() (func(string, string) error, bool) {
+	if usesOSFS() {
+		return os.Link, true
+	}
+	if l, ok := rawFS().(linker); ok {
+		return l.Link, true
+	}
+	return nil, false
+}
+
+// LinkTree creates a hard link farm of the directory tree at src in dst as 'cp -al'.  Directories are created in dst
+// with the same permission bits and modification times, files are hard-linked to the files in src, and symbolic links
+// are copied as symbolic links.  Since files in dst share their contents with src, it is useful to make space-efficient
+// snapshots.  dst is created when it does not exist, but existing files in dst cause an error.  src and dst must be on
+// the same filesystem.  When the current FS is not the OS filesystem, it must implement
+// 'Link(oldname, newname string) error' method.  Otherwise an error which satisfies errors.Is(err, ErrUnsupported) is
+// returned.
+//
+// Example:
+//	// Rotate snapshots like rsnapshot. Unchanged files share disk space between snapshots
+//	daily0, _ := abspath.New("/backup/daily.0")
+//	daily1, _ := abspath.New("/backup/daily.1")
+//	if err := abspath.LinkTree(daily0, daily1); err != nil {
+//		panic(err)
+//	}
+//	_, err := abspath.Sync(src, daily0, &abspath.SyncOptions{Delete: true})
+func LinkTree(src, dst AbsPath) error {
+	return LinkTreeContext(context.Background(), src, dst)
+}
+
+// LinkTreeContext is the same as LinkTree() but accepts a context.  Linking is stopped between entries when the context
+// is done.  Entries linked before the cancellation are left in dst.
+func LinkTreeContext(ctx context.Context, src, dst AbsPath) (err error) {
+	defer annotate(&err, "link", src, dst)
+	link, ok := hardLinkFunc()
+	if !ok {
+		return &os.LinkError{Op: "link", Old: src.underlying, New: dst.underlying, Err: ErrUnsupported}
+	}
+
+	s, err := fsys().Stat(src.underlying)
+	if err != nil {
+		return err
+	}
+	if !s.IsDir() {
+		return fmt.Errorf("cannot link '%s' since it is not a directory", src.underlying)
+	}
+
+	var dirs []createdDir
+	err = walk(src.underlying, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src.underlying, p)
+		if err != nil {
+			return err
+		}
+		to := filepath.Join(dst.underlying, rel)
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			dirs = append(dirs, createdDir{to, info})
+			return fsys().MkdirAll(to, 0700)
+		case mode&os.ModeSymlink != 0:
+			target, err := fsys().Readlink(p)
+			if err != nil {
+				return err
+			}
+			return fsys().Symlink(target, to)
+		default:
+			return audit("link", "target="+p, func() error { return link(p, to) }, to)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return restoreDirs(dirs)
+}
@@ -0,0 +1,115 @@
+package abspath
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLinkTree(t *testing.T) {
+	root := makeTree(t, map[string]string{"src/a.txt": "a", "src/d/b.txt": "b", "src/empty/": ""})
+	src := root.Join("src")
+	if !isWindows {
+		if err := os.Symlink("a.txt", src.Join("link").String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(src.Join("d").String(), mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := root.Join("snapshots", "dst")
+	if err := LinkTree(src, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"a.txt", "d/b.txt"} {
+		s, err := os.Stat(src.Join(rel).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		d, err := os.Stat(dst.Join(rel).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !os.SameFile(s, d) {
+			t.Errorf("%q should be hard-linked", rel)
+		}
+	}
+	if s, err := os.Stat(dst.Join("empty").String()); err != nil || !s.IsDir() {
+		t.Errorf("Empty directory should be created: %v", err)
+	}
+	if s, err := os.Stat(dst.Join("d").String()); err != nil || !s.ModTime().Equal(mtime) {
+		t.Errorf("Modification time of directory should be preserved: %v", err)
+	}
+	if !isWindows {
+		assertLink(t, dst.Join("link"), "a.txt")
+	}
+
+	if err := LinkTree(src, dst); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Linking to existing files should fail but got %v", err)
+	}
+	if err := LinkTree(src.Join("a.txt"), root.Join("foo")); err == nil {
+		t.Error("Linking file should fail")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := LinkTreeContext(ctx, src, root.Join("canceled")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Canceled error should be returned but got %v", err)
+	}
+}
+
+func TestLinkTreeUnsupportedFS(t *testing.T) {
+	root := makeTree(t, map[string]string{"src/a.txt": "a"})
+
+	prev := SetFS(&recordingFS{FS: OSFS})
+	defer SetFS(prev)
+
+	if err := LinkTree(root.Join("src"), root.Join("dst")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Unsupported error should be returned but got %v", err)
+	}
+}
+
+// permLinkFS is permFS which can create hard links
+type permLinkFS struct {
+	permFS
+}
+
+func (f permLinkFS) Link(oldname, newname string) error {
+	if err := f.checkParent(newname); err != nil {
+		return err
+	}
+	return os.Link(oldname, newname)
+}
+
+func TestLinkTreeReadOnlyDirectory(t *testing.T) {
+	if isWindows {
+		t.Skip("Permission bits of directories are not available on Windows")
+	}
+	root := makeTree(t, map[string]string{"src/ro/sub/a.txt": "a", "src/ro/b.txt": "b"})
+	src := root.Join("src")
+	for _, p := range []AbsPath{src.Join("ro", "sub"), src.Join("ro")} {
+		if err := os.Chmod(p.String(), 0555); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chmod(p.String(), 0755)
+	}
+	prev := SetFS(permLinkFS{permFS{OSFS}})
+	defer SetFS(prev)
+
+	dst := root.Join("dst")
+	err := LinkTree(src, dst)
+	for _, p := range []AbsPath{dst.Join("ro", "sub"), dst.Join("ro")} {
+		defer os.Chmod(p.String(), 0755)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, dst.Join("ro", "sub", "a.txt"), "a")
+	assertContent(t, dst.Join("ro", "b.txt"), "b")
+	assertPerm(t, dst.Join("ro", "sub"), 0555)
+	assertPerm(t, dst.Join("ro"), 0555)
+}
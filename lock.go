@@ -0,0 +1,88 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrLocked is an error returned from TryLock() and TryRLock() methods when the lock is held by someone else.
+var ErrLocked = errors.New("file is locked by another process")
+
+// FileLock is an advisory lock acquired on a file.  It is returned from Lock(), RLock(), TryLock() and TryRLock()
+// methods.  The lock must be released with Unlock() method.
+type FileLock struct {
+	path AbsPath
+	file *os.File
+}
+
+// Path returns the locked path.
+func (l *FileLock) Path() AbsPath {
+	return l.path
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() error {
+	err := unlockFile(l.file)
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (a AbsPath) lock(exclusive, block bool) (_ *FileLock, err error) {
+	defer annotate(&err, "lock", a)
+	var f *os.File
+	err = audit("open", "flag=O_RDWR|O_CREATE perm=0644", func() error {
+		var err error
+		f, err = os.OpenFile(a.underlying, os.O_RDWR|os.O_CREATE, 0644)
+		return err
+	}, a.underlying)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f, exclusive, block); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &FileLock{a, f}, nil
+}
+
+// Lock acquires an exclusive advisory lock on the file at the path.  It blocks until the lock is acquired.  The file is
+// created when it does not exist.  The lock is implemented with flock(2) on Unix and LockFileEx on Windows so it can be
+// used for mutual exclusion among multiple processes.
+//
+// Example:
+//	a, _ := abspath.ExpandFrom("~/.cache/app/lock")
+//	l, err := a.Lock()
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer l.Unlock()
+func (a AbsPath) Lock() (*FileLock, error) {
+	return a.lock(true, true)
+}
+
+// RLock acquires a shared advisory lock on the file at the path.  It blocks until the lock is acquired.  Multiple
+// shared locks can be held at the same time, but an exclusive lock cannot be held with any shared lock.
+func (a AbsPath) RLock() (*FileLock, error) {
+	return a.lock(false, true)
+}
+
+// TryLock is the same as Lock() but does not block.  When the lock is held by someone else, it returns an error which
+// satisfies errors.Is(err, ErrLocked).
+//
+// Example:
+//	l, err := a.TryLock()
+//	if errors.Is(err, abspath.ErrLocked) {
+//		fmt.Println("Another process is running")
+//		return
+//	}
+func (a AbsPath) TryLock() (*FileLock, error) {
+	return a.lock(true, false)
+}
+
+// TryRLock is the same as RLock() but does not block.  When an exclusive lock is held by someone else, it returns an
+// error which satisfies errors.Is(err, ErrLocked).
+func (a AbsPath) TryRLock() (*FileLock, error) {
+	return a.lock(false, false)
+}
@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package abspath
+
+import (
+	"os"
+)
+
+func lockFile(f *os.File, exclusive, block bool) error {
+	return &UnsupportedError{"File lock", nil}
+}
+
+func unlockFile(f *os.File) error {
+	return &UnsupportedError{"File lock", nil}
+}
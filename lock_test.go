@@ -0,0 +1,67 @@
+package abspath
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLock(t *testing.T) {
+	a := makeTree(t, nil).Join("lock")
+
+	l, err := a.Lock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Path() != a {
+		t.Errorf("Expected %s but actually %s", a, l.Path())
+	}
+
+	if _, err := a.TryLock(); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked while exclusive lock is held but actually %v", err)
+	}
+	if _, err := a.TryRLock(); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked for shared lock while exclusive lock is held but actually %v", err)
+	}
+
+	if err := l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = a.TryLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRLock(t *testing.T) {
+	a := makeTree(t, nil).Join("lock")
+
+	l1, err := a.RLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l2, err := a.TryRLock()
+	if err != nil {
+		t.Fatalf("Multiple shared locks should be acquired: %v", err)
+	}
+
+	if _, err := a.TryLock(); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked while shared lock is held but actually %v", err)
+	}
+
+	for _, l := range []*FileLock{l1, l2} {
+		if err := l.Unlock(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLockError(t *testing.T) {
+	a := makeTree(t, nil).Join("not-exist", "lock")
+	if _, err := a.Lock(); err == nil {
+		t.Errorf("Lock file in not existing directory must cause an error")
+	}
+}
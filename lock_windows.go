@@ -0,0 +1,28 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+const lockAllBytes = ^uint32(0)
+
+func lockFile(f *os.File, exclusive, block bool) error {
+	var flags uint32
+	if exclusive {
+		flags |= windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	if !block {
+		flags |= windows.LOCKFILE_FAIL_IMMEDIATELY
+	}
+	err := windows.LockFileEx(windows.Handle(f.Fd()), flags, 0, lockAllBytes, lockAllBytes, &windows.Overlapped{})
+	if err == windows.ERROR_LOCK_VIOLATION {
+		return ErrLocked
+	}
+	return err
+}
+
+func unlockFile(f *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(f.Fd()), 0, lockAllBytes, lockAllBytes, &windows.Overlapped{})
+}
@@ -0,0 +1,613 @@
+// Package memfs provides an in-memory filesystem which implements abspath.FS.  Setting it by abspath.SetFS() makes
+// all methods of abspath.AbsPath access the in-memory filesystem instead of the disk so that tests can be run
+// deterministically without any fixture on the disk.  The filesystem is safe for concurrent use.  Parallel tests can
+// share one filesystem by using distinct directories.
+//
+// Example:
+//	fs := memfs.New()
+//	prev := abspath.SetFS(fs)
+//	defer abspath.SetFS(prev)
+//
+//	p, _ := abspath.New("/path/to/file.txt")
+//	fs.WriteFile(p.String(), []byte("hello"), 0644)
+//	err := p.CopyFile(p.Dir().Join("copied.txt"), nil)
+package memfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rhysd/abspath"
+)
+
+// maxSymlinks is the maximum number of symbolic links followed while resolving one path.
+const maxSymlinks = 255
+
+var (
+	errNotDir   = errors.New("not a directory")
+	errIsDir    = errors.New("is a directory")
+	errNotEmpty = errors.New("directory not empty")
+	errLoop     = errors.New("too many levels of symbolic links")
+	errInvalid  = errors.New("invalid argument")
+	errBadFile  = errors.New("bad file descriptor")
+)
+
+type node struct {
+	name     string
+	mode     os.FileMode
+	modTime  time.Time
+	data     []byte
+	target   string
+	children map[string]*node
+}
+
+func (n *node) isDir() bool {
+	return n.mode.IsDir()
+}
+
+func (n *node) isSymlink() bool {
+	return n.mode&os.ModeSymlink != 0
+}
+
+func (n *node) info() os.FileInfo {
+	return &fileInfo{n.name, int64(len(n.data)), n.mode, n.modTime}
+}
+
+type fileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) Mode() os.FileMode  { return i.mode }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *fileInfo) Sys() interface{}   { return nil }
+
+// FS is an in-memory filesystem.  Root directories of volumes exist from the beginning.  Relative targets of
+// symbolic links are resolved from the directory containing the link and '..' in targets is resolved lexically.
+type FS struct {
+	mu    sync.Mutex
+	roots map[string]*node
+	now   func() time.Time
+}
+
+// New creates a new empty in-memory filesystem.
+func New() *FS {
+	return &FS{roots: map[string]*node{}, now: time.Now}
+}
+
+func (fs *FS) root(p abspath.AbsPath) *node {
+	r, ok := fs.roots[p.Key()]
+	if !ok {
+		r = &node{
+			name:     p.String(),
+			mode:     os.ModeDir | 0755,
+			modTime:  fs.now(),
+			children: map[string]*node{},
+		}
+		fs.roots[p.Key()] = r
+	}
+	return r
+}
+
+// lookup finds the node at the path.  It returns the parent directory, the base name and the node.  The node is nil
+// when the parent directory exists but the entry does not exist.  Symbolic links in the middle of the path are always
+// followed.  The symbolic link at the end of the path is followed only when follow is true.
+func (fs *FS) lookup(op, name string, follow bool) (*node, string, *node, error) {
+	p := name
+	for links := 0; ; links++ {
+		if links > maxSymlinks {
+			return nil, "", nil, &os.PathError{Op: op, Path: name, Err: errLoop}
+		}
+
+		a, err := abspath.New(p)
+		if err != nil {
+			return nil, "", nil, &os.PathError{Op: op, Path: name, Err: errInvalid}
+		}
+		curPath := a.Volume().Root()
+		cur := fs.root(curPath)
+		_, comps := a.SplitAll()
+		if len(comps) == 0 {
+			return nil, "", cur, nil
+		}
+
+		next := ""
+		for i, c := range comps {
+			if !cur.isDir() {
+				return nil, "", nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+			}
+			child := cur.children[c]
+			last := i == len(comps)-1
+			if child == nil {
+				if last {
+					return cur, c, nil, nil
+				}
+				return nil, "", nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+			}
+			if child.isSymlink() && (!last || follow) {
+				t := child.target
+				if !filepath.IsAbs(t) {
+					t = filepath.Join(curPath.String(), t)
+				}
+				next = filepath.Join(append([]string{t}, comps[i+1:]...)...)
+				break
+			}
+			if last {
+				return cur, c, child, nil
+			}
+			cur = child
+			curPath = curPath.Join(c)
+		}
+		p = next
+	}
+}
+
+func (fs *FS) find(op, name string, follow bool) (*node, error) {
+	_, _, n, err := fs.lookup(op, name, follow)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	return n, nil
+}
+
+// parent finds the directory which will contain the new entry at the path.
+func (fs *FS) parent(op, name string) (*node, string, *node, error) {
+	dir, base, n, err := fs.lookup(op, name, false)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if dir == nil {
+		// Root directory of volume
+		return nil, "", nil, &os.PathError{Op: op, Path: name, Err: os.ErrExist}
+	}
+	return dir, base, n, nil
+}
+
+// Stat implements abspath.FS.
+func (fs *FS) Stat(name string) (os.FileInfo, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	n, err := fs.find("stat", name, true)
+	if err != nil {
+		return nil, err
+	}
+	return n.info(), nil
+}
+
+// Lstat implements abspath.FS.
+func (fs *FS) Lstat(name string) (os.FileInfo, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	n, err := fs.find("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+	return n.info(), nil
+}
+
+// Open implements abspath.FS.
+func (fs *FS) Open(name string) (abspath.File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile implements abspath.FS.
+func (fs *FS) OpenFile(name string, flag int, perm os.FileMode) (abspath.File, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	dir, base, n, err := fs.lookup("open", name, true)
+	if err != nil {
+		return nil, err
+	}
+	acc := flag & (os.O_RDONLY | os.O_WRONLY | os.O_RDWR)
+	if n == nil {
+		if flag&os.O_CREATE == 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		n = &node{name: base, mode: perm.Perm(), modTime: fs.now()}
+		dir.children[base] = n
+		dir.modTime = n.modTime
+	} else {
+		if flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrExist}
+		}
+		if n.isDir() && acc != os.O_RDONLY {
+			return nil, &os.PathError{Op: "open", Path: name, Err: errIsDir}
+		}
+		if flag&os.O_TRUNC != 0 && acc != os.O_RDONLY {
+			n.data = nil
+			n.modTime = fs.now()
+		}
+	}
+
+	return &file{
+		fs:       fs,
+		node:     n,
+		name:     name,
+		readable: acc != os.O_WRONLY,
+		writable: acc != os.O_RDONLY,
+		append:   flag&os.O_APPEND != 0,
+	}, nil
+}
+
+// ReadDir implements abspath.FS.
+func (fs *FS) ReadDir(name string) ([]os.FileInfo, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	n, err := fs.find("open", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if !n.isDir() {
+		return nil, &os.PathError{Op: "readdirent", Path: name, Err: errNotDir}
+	}
+	infos := make([]os.FileInfo, 0, len(n.children))
+	for _, c := range n.children {
+		infos = append(infos, c.info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+// Readlink implements abspath.FS.
+func (fs *FS) Readlink(name string) (string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	n, err := fs.find("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+	if !n.isSymlink() {
+		return "", &os.PathError{Op: "readlink", Path: name, Err: errInvalid}
+	}
+	return n.target, nil
+}
+
+// Symlink implements abspath.FS.
+func (fs *FS) Symlink(oldname, newname string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	dir, base, n, err := fs.parent("symlink", newname)
+	if err != nil {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: underlying(err)}
+	}
+	if n != nil {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: os.ErrExist}
+	}
+	now := fs.now()
+	dir.children[base] = &node{name: base, mode: os.ModeSymlink | 0777, modTime: now, target: oldname}
+	dir.modTime = now
+	return nil
+}
+
+// Mkdir implements abspath.FS.
+func (fs *FS) Mkdir(name string, perm os.FileMode) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.mkdir(name, perm)
+}
+
+func (fs *FS) mkdir(name string, perm os.FileMode) error {
+	dir, base, n, err := fs.parent("mkdir", name)
+	if err != nil {
+		return err
+	}
+	if n != nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrExist}
+	}
+	now := fs.now()
+	dir.children[base] = &node{name: base, mode: os.ModeDir | perm.Perm(), modTime: now, children: map[string]*node{}}
+	dir.modTime = now
+	return nil
+}
+
+// MkdirAll implements abspath.FS.
+func (fs *FS) MkdirAll(name string, perm os.FileMode) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.mkdirAll(name, perm)
+}
+
+func (fs *FS) mkdirAll(name string, perm os.FileMode) error {
+	if n, err := fs.find("mkdir", name, true); err == nil {
+		if n.isDir() {
+			return nil
+		}
+		return &os.PathError{Op: "mkdir", Path: name, Err: errNotDir}
+	}
+	if p := filepath.Dir(name); p != name {
+		if err := fs.mkdirAll(p, perm); err != nil {
+			return err
+		}
+	}
+	return fs.mkdir(name, perm)
+}
+
+// Remove implements abspath.FS.
+func (fs *FS) Remove(name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	dir, base, n, err := fs.parent("remove", name)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	if n.isDir() && len(n.children) > 0 {
+		return &os.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(dir.children, base)
+	dir.modTime = fs.now()
+	return nil
+}
+
+// RemoveAll implements abspath.FS.
+func (fs *FS) RemoveAll(name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	dir, base, n, err := fs.parent("unlinkat", name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if n != nil {
+		delete(dir.children, base)
+		dir.modTime = fs.now()
+	}
+	return nil
+}
+
+// Rename implements abspath.FS.
+func (fs *FS) Rename(oldpath, newpath string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	linkErr := func(err error) error {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+
+	odir, obase, o, err := fs.parent("rename", oldpath)
+	if err != nil {
+		return linkErr(underlying(err))
+	}
+	if o == nil {
+		return linkErr(os.ErrNotExist)
+	}
+	ndir, nbase, n, err := fs.parent("rename", newpath)
+	if err != nil {
+		return linkErr(underlying(err))
+	}
+	if n == o {
+		return nil
+	}
+	if o.isDir() && strings.HasPrefix(newpath+string(filepath.Separator), oldpath+string(filepath.Separator)) {
+		// Moving a directory into itself is not allowed
+		return linkErr(errInvalid)
+	}
+	if n != nil {
+		switch {
+		case n.isDir() && !o.isDir():
+			return linkErr(errIsDir)
+		case !n.isDir() && o.isDir():
+			return linkErr(errNotDir)
+		case n.isDir() && len(n.children) > 0:
+			return linkErr(errNotEmpty)
+		}
+	}
+
+	now := fs.now()
+	delete(odir.children, obase)
+	o.name = nbase
+	ndir.children[nbase] = o
+	odir.modTime = now
+	ndir.modTime = now
+	return nil
+}
+
+// Chmod implements abspath.FS.
+func (fs *FS) Chmod(name string, mode os.FileMode) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	n, err := fs.find("chmod", name, true)
+	if err != nil {
+		return err
+	}
+	n.mode = n.mode&os.ModeType | mode.Perm()
+	return nil
+}
+
+// Chtimes implements abspath.FS.  Access time is ignored.
+func (fs *FS) Chtimes(name string, atime, mtime time.Time) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	n, err := fs.find("chtimes", name, true)
+	if err != nil {
+		return err
+	}
+	n.modTime = mtime
+	return nil
+}
+
+// WriteFile writes the data to the file at the path.  Parent directories are created when they do not exist.  It is
+// useful to prepare fixtures of tests.
+func (fs *FS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	fs.mu.Lock()
+	err := fs.mkdirAll(filepath.Dir(name), 0755)
+	fs.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	f, err := fs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func underlying(err error) error {
+	if pe, ok := err.(*os.PathError); ok {
+		return pe.Err
+	}
+	return err
+}
+
+type file struct {
+	fs       *FS
+	node     *node
+	name     string
+	offset   int64
+	readable bool
+	writable bool
+	append   bool
+	closed   bool
+}
+
+func (f *file) check(op string, write bool) error {
+	if f.closed {
+		return &os.PathError{Op: op, Path: f.name, Err: os.ErrClosed}
+	}
+	if f.node.isDir() {
+		return &os.PathError{Op: op, Path: f.name, Err: errIsDir}
+	}
+	if (write && !f.writable) || (!write && !f.readable) {
+		return &os.PathError{Op: op, Path: f.name, Err: errBadFile}
+	}
+	return nil
+}
+
+func (f *file) Read(b []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("read", false); err != nil {
+		return 0, err
+	}
+	if f.offset >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.node.data[f.offset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *file) ReadAt(b []byte, off int64) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("read", false); err != nil {
+		return 0, err
+	}
+	if off < 0 {
+		return 0, &os.PathError{Op: "readat", Path: f.name, Err: errInvalid}
+	}
+	if off >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.node.data[off:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *file) Write(b []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("write", true); err != nil {
+		return 0, err
+	}
+	if f.append {
+		f.offset = int64(len(f.node.data))
+	}
+	end := f.offset + int64(len(b))
+	if end > int64(len(f.node.data)) {
+		d := make([]byte, end)
+		copy(d, f.node.data)
+		f.node.data = d
+	}
+	copy(f.node.data[f.offset:], b)
+	f.offset = end
+	f.node.modTime = f.fs.now()
+	return len(b), nil
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.closed {
+		return 0, &os.PathError{Op: "seek", Path: f.name, Err: os.ErrClosed}
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.node.data))
+	}
+	if offset < 0 {
+		return 0, &os.PathError{Op: "seek", Path: f.name, Err: errInvalid}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *file) Close() error {
+	if f.closed {
+		return &os.PathError{Op: "close", Path: f.name, Err: os.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+func (f *file) Name() string {
+	return f.name
+}
+
+func (f *file) Stat() (os.FileInfo, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.closed {
+		return nil, &os.PathError{Op: "stat", Path: f.name, Err: os.ErrClosed}
+	}
+	return f.node.info(), nil
+}
+
+func (f *file) Sync() error {
+	if f.closed {
+		return &os.PathError{Op: "sync", Path: f.name, Err: os.ErrClosed}
+	}
+	return nil
+}
+
+func (f *file) Truncate(size int64) error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("truncate", true); err != nil {
+		return err
+	}
+	if size < 0 {
+		return &os.PathError{Op: "truncate", Path: f.name, Err: errInvalid}
+	}
+	d := make([]byte, size)
+	copy(d, f.node.data)
+	f.node.data = d
+	f.node.modTime = f.fs.now()
+	return nil
+}
@@ -0,0 +1,320 @@
+package memfs
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/rhysd/abspath"
+)
+
+func root(t *testing.T) abspath.AbsPath {
+	s := "/"
+	if runtime.GOOS == "windows" {
+		s = `C:\`
+	}
+	p, err := abspath.New(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func readFile(t *testing.T, fs *FS, p abspath.AbsPath) string {
+	f, err := fs.Open(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestFileOperations(t *testing.T) {
+	fs := New()
+	dir := root(t).Join("path", "to")
+	p := dir.Join("file.txt")
+
+	if err := fs.WriteFile(p.String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := fs.Stat(dir.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.IsDir() || s.Name() != "to" {
+		t.Errorf("Unexpected file info of directory: %s %s", s.Name(), s.Mode())
+	}
+	s, err = fs.Stat(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Mode().IsRegular() || s.Size() != 5 || s.Mode().Perm() != 0644 {
+		t.Errorf("Unexpected file info of file: %s %d", s.Mode(), s.Size())
+	}
+	if c := readFile(t, fs, p); c != "hello" {
+		t.Errorf("Unexpected content: %q", c)
+	}
+
+	f, err := fs.OpenFile(p.String(), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(" world")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Read(make([]byte, 1)); err == nil {
+		t.Error("Reading file opened for writing should cause an error")
+	}
+	f.Close()
+	if c := readFile(t, fs, p); c != "hello world" {
+		t.Errorf("Unexpected content after appending: %q", c)
+	}
+
+	f, err = fs.Open(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, 5)
+	if _, err := io.ReadFull(f, b); err != nil || string(b) != "world" {
+		t.Errorf("Unexpected read after seek: %q %v", b, err)
+	}
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("Writing file opened for reading should cause an error")
+	}
+	f.Close()
+
+	if _, err := fs.OpenFile(p.String(), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644); !os.IsExist(err) {
+		t.Errorf("Exclusive creation of existing file should fail: %v", err)
+	}
+	if _, err := fs.Open(dir.Join("missing").String()); !os.IsNotExist(err) {
+		t.Errorf("Opening missing file should fail: %v", err)
+	}
+	if _, err := fs.Stat(p.Join("child").String()); !os.IsNotExist(err) {
+		t.Errorf("Path under file should not exist: %v", err)
+	}
+	if err := fs.Mkdir(dir.String(), 0755); !os.IsExist(err) {
+		t.Errorf("Creating existing directory should fail: %v", err)
+	}
+	if err := fs.MkdirAll(p.Join("d").String(), 0755); err == nil {
+		t.Error("Creating directory under file should fail")
+	}
+	if err := fs.Remove(dir.String()); err == nil {
+		t.Error("Removing non-empty directory should fail")
+	}
+
+	moved := root(t).Join("moved")
+	if err := fs.Rename(dir.String(), moved.String()); err != nil {
+		t.Fatal(err)
+	}
+	if c := readFile(t, fs, moved.Join("file.txt")); c != "hello world" {
+		t.Errorf("Unexpected content after rename: %q", c)
+	}
+	if err := fs.Rename(moved.String(), moved.Join("sub").String()); err == nil {
+		t.Error("Moving directory into itself should fail")
+	}
+	if err := fs.RemoveAll(moved.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat(moved.String()); !os.IsNotExist(err) {
+		t.Errorf("Removed directory should not exist: %v", err)
+	}
+	if err := fs.RemoveAll(moved.String()); err != nil {
+		t.Errorf("Removing missing path should not fail: %v", err)
+	}
+}
+
+func TestSymlink(t *testing.T) {
+	fs := New()
+	r := root(t)
+	p := r.Join("a", "b", "file.txt")
+	if err := fs.WriteFile(p.String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	link := r.Join("link")
+	if err := fs.Symlink(filepath.Join("a", "b"), link.String()); err != nil {
+		t.Fatal(err)
+	}
+	if c := readFile(t, fs, link.Join("file.txt")); c != "hello" {
+		t.Errorf("Unexpected content via symlink: %q", c)
+	}
+	s, err := fs.Lstat(link.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Lstat should not follow symlink: %s", s.Mode())
+	}
+	if s, err := fs.Stat(link.String()); err != nil || !s.IsDir() {
+		t.Errorf("Stat should follow symlink: %v", err)
+	}
+	target, err := fs.Readlink(link.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != filepath.Join("a", "b") {
+		t.Errorf("Unexpected link target: %q", target)
+	}
+	if _, err := fs.Readlink(p.String()); err == nil {
+		t.Error("Reading non-symlink should fail")
+	}
+
+	loop := r.Join("loop")
+	if err := fs.Symlink(loop.String(), loop.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat(loop.String()); err == nil {
+		t.Error("Symlink loop should cause an error")
+	}
+}
+
+func TestAbsPathMethods(t *testing.T) {
+	fs := New()
+	prev := abspath.SetFS(fs)
+	defer abspath.SetFS(prev)
+
+	src := root(t).Join("src")
+	for _, p := range []string{"a.txt", "b/c.txt", "b/d/e.txt"} {
+		if err := fs.WriteFile(src.Join(filepath.FromSlash(p)).String(), []byte(p), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fs.Symlink("a.txt", src.Join("link").String()); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := root(t).Join("dst")
+	if err := src.CopyDir(dst, nil); err != nil {
+		t.Fatal(err)
+	}
+	if c := readFile(t, fs, dst.Join("b", "d", "e.txt")); c != "b/d/e.txt" {
+		t.Errorf("Unexpected content of copied file: %q", c)
+	}
+
+	stats, err := dst.Count(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Files != 3 || stats.Dirs != 2 || stats.Symlinks != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	resolved, err := dst.Join("link").EvalSymlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved != dst.Join("a.txt") {
+		t.Errorf("Unexpected resolved path: %s", resolved)
+	}
+
+	eq, err := src.Join("a.txt").ContentEqual(dst.Join("a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !eq {
+		t.Error("Copied file should have the same content")
+	}
+}
+
+func TestWalkFollowSymlinks(t *testing.T) {
+	fs := New()
+	prev := abspath.SetFS(fs)
+	defer abspath.SetFS(prev)
+
+	r := root(t).Join("tree")
+	if err := fs.WriteFile(r.Join("a", "file").String(), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Symlink("..", r.Join("a", "loop").String()); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	err := r.WalkWithOptions(func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		n++
+		return nil
+	}, &abspath.WalkOptions{FollowSymlinks: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// tree, tree/a, tree/a/file, tree/a/loop
+	if n != 4 {
+		t.Errorf("Cycle should be detected without file IDs but %d entries were visited", n)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	fs := New()
+	prev := abspath.SetFS(fs)
+	defer abspath.SetFS(prev)
+
+	p := root(t).Join("file.txt")
+	if err := fs.WriteFile(p.String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Truncate(2); err != nil {
+		t.Fatal(err)
+	}
+	if c := readFile(t, fs, p); c != "he" {
+		t.Errorf("Unexpected content after shrinking: %q", c)
+	}
+	if err := p.Truncate(4); err != nil {
+		t.Fatal(err)
+	}
+	if c := readFile(t, fs, p); c != "he\x00\x00" {
+		t.Errorf("Unexpected content after extending: %q", c)
+	}
+	if err := p.Truncate(-1); err == nil {
+		t.Error("Error should occur for negative size")
+	}
+	if err := root(t).Join("missing").Truncate(0); err == nil {
+		t.Error("Error should occur for missing file")
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	fs := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p := root(t).Join("dir", string(rune('a'+i)), "file.txt")
+			if err := fs.WriteFile(p.String(), []byte("hello"), 0644); err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := fs.ReadDir(p.Dir().Dir().String()); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	infos, err := fs.ReadDir(root(t).Join("dir").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 8 {
+		t.Fatalf("Unexpected number of entries: %d", len(infos))
+	}
+	for i, info := range infos {
+		if info.Name() != string(rune('a'+i)) {
+			t.Errorf("Entries should be sorted by name: %s at %d", info.Name(), i)
+		}
+	}
+}
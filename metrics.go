@@ -0,0 +1,84 @@
+package abspath
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Metrics is an interface to receive metrics of filesystem activity of this package.  It can be implemented with
+// counters and histograms of a metrics library such as Prometheus client.  Since operations may be performed
+// concurrently, the methods must be safe for concurrent use.
+type Metrics interface {
+	// ObserveOp is called after every filesystem operation with the name of the operation, the time it took and its
+	// error.  Names are the same as OpInfo.Name such as "stat", "open" and "rename".  Walking a directory tree is
+	// reported as "walk" after the whole walk finishes.
+	ObserveOp(name string, elapsed time.Duration, err error)
+	// AddBytes is called when file contents are transferred.  The name is one of "read" (ReadFile()), "write"
+	// (WriteFile()), "copy" (copying files), "hash" (hashing files) and "compare" (comparing file contents).  It may be
+	// called multiple times for one file.
+	AddBytes(name string, n int64)
+}
+
+type metricsHolder struct {
+	m Metrics
+}
+
+var currentMetrics atomic.Value
+
+func init() {
+	currentMetrics.Store(metricsHolder{})
+}
+
+func metrics() Metrics {
+	return currentMetrics.Load().(metricsHolder).m
+}
+
+// SetMetrics sets the metrics receiver and returns the previous one.  When nil is given, metrics are not reported.  It is
+// useful to export statistics such as the number of stat calls, bytes copied and walk durations without wrapping the
+// package.
+//
+// Example:
+//	type promMetrics struct {
+//		ops   *prometheus.HistogramVec
+//		bytes *prometheus.CounterVec
+//	}
+//
+//	func (m *promMetrics) ObserveOp(name string, elapsed time.Duration, err error) {
+//		m.ops.WithLabelValues(name, strconv.FormatBool(err == nil)).Observe(elapsed.Seconds())
+//	}
+//
+//	func (m *promMetrics) AddBytes(name string, n int64) {
+//		m.bytes.WithLabelValues(name).Add(float64(n))
+//	}
+//
+//	abspath.SetMetrics(&promMetrics{ops, bytes})
+func SetMetrics(m Metrics) Metrics {
+	return currentMetrics.Swap(metricsHolder{m}).(metricsHolder).m
+}
+
+// metricsMiddleware returns a middleware which reports every operation to the metrics receiver.
+func metricsMiddleware(m Metrics) Middleware {
+	return func(next Op) Op {
+		return func(info *OpInfo) error {
+			start := time.Now()
+			err := next(info)
+			m.ObserveOp(info.Name, time.Since(start), err)
+			return err
+		}
+	}
+}
+
+// observe reports the operation which started at the time to the metrics receiver when it is set.  It is deferred at
+// the beginning of functions with a named error result.
+func observe(name string, start time.Time, errp *error) {
+	if m := metrics(); m != nil {
+		m.ObserveOp(name, time.Since(start), *errp)
+	}
+}
+
+// addBytes reports the number of transferred bytes to the metrics receiver when it is set.
+func addBytes(name string, n int64) {
+	if m := metrics(); m != nil && n > 0 {
+		m.AddBytes(name, n)
+	}
+}
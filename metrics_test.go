@@ -0,0 +1,107 @@
+package abspath
+
+import (
+	"crypto"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	mu    sync.Mutex
+	ops   map[string]int
+	errs  map[string]int
+	bytes map[string]int64
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{ops: map[string]int{}, errs: map[string]int{}, bytes: map[string]int64{}}
+}
+
+func (m *recordingMetrics) ObserveOp(name string, elapsed time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ops[name]++
+	if err != nil {
+		m.errs[name]++
+	}
+}
+
+func (m *recordingMetrics) AddBytes(name string, n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes[name] += n
+}
+
+func TestMetricsOps(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello", "d/b.txt": "world"})
+
+	m := newRecordingMetrics()
+	prev := SetMetrics(m)
+	defer SetMetrics(prev)
+
+	if _, err := fsys().Stat(root.Join("a.txt").String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys().Stat(root.Join("missing").String()); err == nil {
+		t.Fatal("Error did not occur")
+	}
+	if m.ops["stat"] != 2 || m.errs["stat"] != 1 {
+		t.Errorf("2 stat calls with 1 error should be reported but got %d calls with %d errors", m.ops["stat"], m.errs["stat"])
+	}
+
+	if _, err := root.Count(nil); err != nil {
+		t.Fatal(err)
+	}
+	if m.ops["walk"] != 1 {
+		t.Errorf("Walk should be reported once but got %d", m.ops["walk"])
+	}
+	if m.ops["readdir"] != 2 {
+		t.Errorf("Reading 2 directories should be reported but got %d", m.ops["readdir"])
+	}
+}
+
+func TestMetricsBytes(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello", "b.txt": "hello"})
+	a := root.Join("a.txt")
+
+	m := newRecordingMetrics()
+	prev := SetMetrics(m)
+	defer SetMetrics(prev)
+
+	if err := a.CopyFile(root.Join("c.txt"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ReadFile(); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.Join("d.txt").WriteFile([]byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Hash(crypto.SHA256); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.ContentEqual(root.Join("b.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int64{"copy": 5, "read": 5, "write": 3, "hash": 5, "compare": 10}
+	for name, n := range want {
+		if m.bytes[name] != n {
+			t.Errorf("%d bytes should be reported for %q but got %d", n, name, m.bytes[name])
+		}
+	}
+}
+
+func TestSetMetricsNil(t *testing.T) {
+	prev := SetMetrics(newRecordingMetrics())
+	defer SetMetrics(prev)
+
+	if SetMetrics(nil) == nil {
+		t.Error("Previous metrics receiver should be returned")
+	}
+	if intercepted() {
+		t.Error("Operations should not be intercepted after unsetting metrics")
+	}
+	addBytes("copy", 1) // Should not panic
+}
@@ -0,0 +1,212 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// OpInfo describes a filesystem operation passed to middlewares.
+type OpInfo struct {
+	// Name is the name of the operation such as "stat", "open", "remove" or "rename".  Names of operations via FS are
+	// the lowercased names of FS methods.
+	Name string
+	// Paths is a list of paths involved in the operation.  For "rename", they are the old path and the new path.
+	Paths []AbsPath
+	// Detail is additional information of the operation in human readable form such as the flags for "open" or the
+	// permission for "chmod".  It may be empty.
+	Detail string
+	// Mutating is true when the operation may modify the filesystem.
+	Mutating bool
+}
+
+// Op is a function which performs a filesystem operation once and returns its error.  The operation itself is fixed
+// when the Op is created.  Modifying the OpInfo does not change what is performed.
+type Op func(info *OpInfo) error
+
+// Middleware wraps an Op to add behavior around every filesystem operation.  It can call next zero times (to refuse the
+// operation), once or multiple times (to retry the operation).
+type Middleware func(next Op) Op
+
+type middlewaresHolder struct {
+	chain []Middleware
+}
+
+var currentMiddlewares atomic.Value
+
+func init() {
+	currentMiddlewares.Store(middlewaresHolder{})
+}
+
+func middlewares() []Middleware {
+	return currentMiddlewares.Load().(middlewaresHolder).chain
+}
+
+// SetMiddlewares sets the chain of middlewares applied to every filesystem operation performed by this package and
+// returns the previous chain.  The first middleware is the outermost one.  Calling it without arguments removes all
+// middlewares.  Operations via FS (including walking directories) and mutating operations which directly access the OS
+// filesystem are passed to the middlewares.  Reads and writes on opened files are not.  The audit hook set by
+// SetAuditHook() is applied inside all middlewares so that it reports every attempt.  Since operations may be
+// performed concurrently, middlewares must be safe for concurrent use.
+//
+// Example:
+//	prev := abspath.SetMiddlewares(
+//		func(next abspath.Op) abspath.Op {
+//			return func(info *abspath.OpInfo) error {
+//				start := time.Now()
+//				err := next(info)
+//				log.Printf("%s %v took %s: %v", info.Name, info.Paths, time.Since(start), err)
+//				return err
+//			}
+//		},
+//		abspath.RetryMiddleware(nil),
+//	)
+//	defer abspath.SetMiddlewares(prev...)
+func SetMiddlewares(mws ...Middleware) []Middleware {
+	chain := append([]Middleware{}, mws...)
+	return currentMiddlewares.Swap(middlewaresHolder{chain}).(middlewaresHolder).chain
+}
+
+// RetryMiddleware returns a middleware which retries mutating operations on temporary errors with exponential backoff
+// in the same way as RenameRetry().  It is useful to make all operations robust on Windows.  opts can be nil.
+func RetryMiddleware(opts *RetryOptions) Middleware {
+	return func(next Op) Op {
+		return func(info *OpInfo) error {
+			if !info.Mutating {
+				return next(info)
+			}
+			return retry(opts, isTemporaryFileError, func() error { return next(info) })
+		}
+	}
+}
+
+func intercepted() bool {
+	return auditHook() != nil || len(middlewares()) > 0 || metrics() != nil
+}
+
+// intercept performs the operation via the middlewares, the audit hook and the metrics receiver when they are set.
+func intercept(name, detail string, mutating bool, f func() error, paths ...string) error {
+	h := auditHook()
+	mws := middlewares()
+	m := metrics()
+	if h == nil && len(mws) == 0 && m == nil {
+		return f()
+	}
+
+	ps := make([]AbsPath, 0, len(paths))
+	for _, p := range paths {
+		ps = append(ps, AbsPath{p})
+	}
+	op := func(*OpInfo) error { return f() }
+	if m != nil {
+		op = metricsMiddleware(m)(op)
+	}
+	if h != nil {
+		op = auditMiddleware(h)(op)
+	}
+	for i := len(mws) - 1; i >= 0; i-- {
+		op = mws[i](op)
+	}
+	return op(&OpInfo{Name: name, Paths: ps, Detail: detail, Mutating: mutating})
+}
+
+// interceptFS is an FS which passes all operations of the underlying FS to the middlewares, the audit hook and the
+// metrics receiver.
+type interceptFS struct {
+	FS
+}
+
+func (f interceptFS) Stat(name string) (os.FileInfo, error) {
+	var s os.FileInfo
+	err := intercept("stat", "", false, func() error {
+		var err error
+		s, err = f.FS.Stat(name)
+		return err
+	}, name)
+	return s, err
+}
+
+func (f interceptFS) Lstat(name string) (os.FileInfo, error) {
+	var s os.FileInfo
+	err := intercept("lstat", "", false, func() error {
+		var err error
+		s, err = f.FS.Lstat(name)
+		return err
+	}, name)
+	return s, err
+}
+
+func (f interceptFS) Open(name string) (File, error) {
+	var file File
+	err := intercept("open", "flag=O_RDONLY", false, func() error {
+		var err error
+		file, err = f.FS.Open(name)
+		return err
+	}, name)
+	return file, err
+}
+
+func (f interceptFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	var file File
+	detail := fmt.Sprintf("flag=%s perm=%#o", openFlagString(flag), perm)
+	err := intercept("open", detail, flag&mutatingFlags != 0, func() error {
+		var err error
+		file, err = f.FS.OpenFile(name, flag, perm)
+		return err
+	}, name)
+	return file, err
+}
+
+func (f interceptFS) ReadDir(name string) ([]os.FileInfo, error) {
+	var es []os.FileInfo
+	err := intercept("readdir", "", false, func() error {
+		var err error
+		es, err = f.FS.ReadDir(name)
+		return err
+	}, name)
+	return es, err
+}
+
+func (f interceptFS) Readlink(name string) (string, error) {
+	var s string
+	err := intercept("readlink", "", false, func() error {
+		var err error
+		s, err = f.FS.Readlink(name)
+		return err
+	}, name)
+	return s, err
+}
+
+func (f interceptFS) Symlink(oldname, newname string) error {
+	return audit("symlink", "target="+oldname, func() error { return f.FS.Symlink(oldname, newname) }, newname)
+}
+
+func (f interceptFS) Mkdir(name string, perm os.FileMode) error {
+	return audit("mkdir", fmt.Sprintf("perm=%#o", perm), func() error { return f.FS.Mkdir(name, perm) }, name)
+}
+
+func (f interceptFS) MkdirAll(name string, perm os.FileMode) error {
+	return audit("mkdirall", fmt.Sprintf("perm=%#o", perm), func() error { return f.FS.MkdirAll(name, perm) }, name)
+}
+
+func (f interceptFS) Remove(name string) error {
+	return audit("remove", "", func() error { return f.FS.Remove(name) }, name)
+}
+
+func (f interceptFS) RemoveAll(name string) error {
+	return audit("removeall", "", func() error { return f.FS.RemoveAll(name) }, name)
+}
+
+func (f interceptFS) Rename(oldpath, newpath string) error {
+	return audit("rename", "", func() error { return f.FS.Rename(oldpath, newpath) }, oldpath, newpath)
+}
+
+func (f interceptFS) Chmod(name string, mode os.FileMode) error {
+	return audit("chmod", fmt.Sprintf("mode=%#o", mode), func() error { return f.FS.Chmod(name, mode) }, name)
+}
+
+func (f interceptFS) Chtimes(name string, atime, mtime time.Time) error {
+	detail := fmt.Sprintf("atime=%s mtime=%s", atime.Format(time.RFC3339Nano), mtime.Format(time.RFC3339Nano))
+	return audit("chtimes", detail, func() error { return f.FS.Chtimes(name, atime, mtime) }, name)
+}
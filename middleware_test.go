@@ -0,0 +1,173 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMiddlewaresOrder(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	a := root.Join("a.txt")
+
+	var mu sync.Mutex
+	calls := []string{}
+	record := func(name string) Middleware {
+		return func(next Op) Op {
+			return func(info *OpInfo) error {
+				mu.Lock()
+				calls = append(calls, name+":"+info.Name)
+				mu.Unlock()
+				return next(info)
+			}
+		}
+	}
+
+	prev := SetMiddlewares(record("outer"), record("inner"))
+	defer SetMiddlewares(prev...)
+
+	if err := a.Truncate(1); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"outer:truncate", "inner:truncate"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("Wanted calls %v but got %v", want, calls)
+	}
+}
+
+func TestMiddlewareRefusesOperation(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	a := root.Join("a.txt")
+
+	refused := errors.New("refused")
+	prev := SetMiddlewares(func(next Op) Op {
+		return func(info *OpInfo) error {
+			if info.Mutating {
+				return refused
+			}
+			return next(info)
+		}
+	})
+	defer SetMiddlewares(prev...)
+
+	if err := fsys().Remove(a.String()); err != refused {
+		t.Fatalf("Error should be returned from middleware but got %v", err)
+	}
+	if _, err := fsys().Stat(a.String()); err != nil {
+		t.Fatalf("File should not be removed: %v", err)
+	}
+}
+
+func TestMiddlewareReceivesReads(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello", "d/b.txt": "world"})
+
+	var mu sync.Mutex
+	infos := []OpInfo{}
+	prev := SetMiddlewares(func(next Op) Op {
+		return func(info *OpInfo) error {
+			mu.Lock()
+			infos = append(infos, *info)
+			mu.Unlock()
+			return next(info)
+		}
+	})
+	defer SetMiddlewares(prev...)
+
+	if _, err := fsys().Stat(root.Join("a.txt").String()); err != nil {
+		t.Fatal(err)
+	}
+	want := OpInfo{Name: "stat", Paths: []AbsPath{root.Join("a.txt")}}
+	if len(infos) != 1 || !reflect.DeepEqual(infos[0], want) {
+		t.Fatalf("Wanted %+v but got %+v", want, infos)
+	}
+
+	infos = infos[:0]
+	n := 0
+	if err := walk(root.String(), func(p string, info os.FileInfo, err error) error {
+		n++
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("All 4 entries should be walked but got %d", n)
+	}
+	readdirs := 0
+	for _, i := range infos {
+		if i.Mutating {
+			t.Errorf("Walking should not be mutating: %+v", i)
+		}
+		if i.Name == "readdir" {
+			readdirs++
+		}
+	}
+	if readdirs != 2 {
+		t.Errorf("Reading 2 directories should be passed to middleware but got %+v", infos)
+	}
+}
+
+func TestMiddlewareWithAuditHook(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	a := root.Join("a.txt")
+
+	r := &auditRecorder{}
+	prevHook := SetAuditHook(r.hook)
+	defer SetAuditHook(prevHook)
+
+	attempts := 0
+	prev := SetMiddlewares(func(next Op) Op {
+		return func(info *OpInfo) error {
+			if info.Name != "chmod" {
+				return next(info)
+			}
+			attempts++
+			if err := next(info); err != nil {
+				return err
+			}
+			attempts++
+			return next(info)
+		}
+	})
+	defer SetMiddlewares(prev...)
+
+	if err := fsys().Chmod(a.String(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Fatalf("Operation should be attempted twice but got %d", attempts)
+	}
+	want := []string{"chmod", "chmod"}
+	if ops := r.ops(); !reflect.DeepEqual(ops, want) {
+		t.Errorf("Audit hook should report every attempt %v but got %v", want, ops)
+	}
+}
+
+func TestRetryMiddleware(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+
+	prev := SetMiddlewares(RetryMiddleware(&RetryOptions{MaxAttempts: 3}))
+	defer SetMiddlewares(prev...)
+
+	err := fsys().Remove(root.Join("missing").String())
+	if !os.IsNotExist(err) {
+		t.Fatalf("Not-exist error should be returned without retrying but got %v", err)
+	}
+}
+
+func TestSetMiddlewaresReturnsPrevious(t *testing.T) {
+	mw := func(next Op) Op { return next }
+	prev := SetMiddlewares(mw, mw)
+	defer SetMiddlewares(prev...)
+
+	if got := SetMiddlewares(); len(got) != 2 {
+		t.Errorf("Previous 2 middlewares should be returned but got %d", len(got))
+	}
+	if intercepted() {
+		t.Error("Operations should not be intercepted after removing middlewares")
+	}
+	if _, ok := fsys().(interceptFS); ok {
+		t.Error("FS should not be wrapped after removing middlewares")
+	}
+}
@@ -0,0 +1,104 @@
+package abspath
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// MmapOptions is a set of options for Mmap() method.
+type MmapOptions struct {
+	// CopyOnWrite maps the file privately.  The returned slice is writable but modifications are visible only in the
+	// process and never written to the file.  By default the slice is read-only and writing to it causes a crash.
+	CopyOnWrite bool
+}
+
+// MappedFile is a file mapped into memory by Mmap() method.  It must be unmapped by Close() method after use.
+type MappedFile struct {
+	data  []byte
+	unmap func([]byte) error
+}
+
+// Bytes returns the content of the mapped file.  The slice must not be used after Close() is called.
+func (m *MappedFile) Bytes() []byte {
+	return m.data
+}
+
+// Len returns the size of the mapped content in bytes.
+func (m *MappedFile) Len() int {
+	return len(m.data)
+}
+
+// Close unmaps the file.  It is safe to call it multiple times.
+func (m *MappedFile) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	d := m.data
+	m.data = nil
+	if m.unmap == nil {
+		return nil
+	}
+	return m.unmap(d)
+}
+
+// Mmap maps the whole file at the path into memory and returns it as a byte slice.  Pages are loaded lazily by the OS
+// so that a large file can be read without loading it into memory at once.  It is implemented with mmap(2) on Unix and
+// MapViewOfFile() on Windows.  On other platforms or when the current FS is not the OS filesystem, the file content is
+// read into memory instead.  The file must not be truncated while it is mapped.  opts can be nil.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/index.bin")
+//	m, err := a.Mmap(nil)
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer m.Close()
+//	n := bytes.Count(m.Bytes(), []byte("\n"))
+func (a AbsPath) Mmap(opts *MmapOptions) (_ *MappedFile, err error) {
+	defer annotate(&err, "mmap", a)
+	if opts == nil {
+		opts = &MmapOptions{}
+	}
+
+	if !usesOSFS() || !mmapSupported {
+		f, err := fsys().Open(a.underlying)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		b, err := ioutil.ReadAll(f)
+		if err != nil {
+			return nil, err
+		}
+		return &MappedFile{b, nil}, nil
+	}
+
+	f, err := os.Open(a.underlying)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if s.IsDir() {
+		return nil, fmt.Errorf("cannot map '%s' since it is a directory", a.underlying)
+	}
+	size := s.Size()
+	if size == 0 {
+		// Empty file cannot be mapped
+		return &MappedFile{[]byte{}, nil}, nil
+	}
+	if int64(int(size)) != size {
+		return nil, fmt.Errorf("cannot map '%s' since its size %d is too large", a.underlying, size)
+	}
+
+	b, err := mmap(f, int(size), opts.CopyOnWrite)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: a.underlying, Err: err}
+	}
+	return &MappedFile{b, munmap}, nil
+}
@@ -0,0 +1,19 @@
+//go:build !unix && !windows
+// +build !unix,!windows
+
+package abspath
+
+import (
+	"os"
+)
+
+// mmapSupported is false since memory-mapped files are not available.  The file content is read instead.
+const mmapSupported = false
+
+func mmap(f *os.File, size int, cow bool) ([]byte, error) {
+	return nil, &UnsupportedError{"Mmap", nil}
+}
+
+func munmap(b []byte) error {
+	return nil
+}
@@ -0,0 +1,92 @@
+package abspath
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestMmap(t *testing.T) {
+	content := bytes.Repeat([]byte("hello, world\n"), 10000)
+	root := makeTree(t, map[string]string{"file": string(content), "empty": ""})
+	p := root.Join("file")
+
+	m, err := p.Mmap(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Len() != len(content) || !bytes.Equal(m.Bytes(), content) {
+		t.Errorf("Mapped content is not correct (%d bytes)", m.Len())
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("Closing twice should not fail: %v", err)
+	}
+	if m.Bytes() != nil {
+		t.Error("Bytes should be nil after close")
+	}
+
+	e, err := root.Join("empty").Mmap(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Len() != 0 {
+		t.Errorf("Empty file should be mapped to empty slice: %d", e.Len())
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := root.Mmap(nil); err == nil {
+		t.Error("Directory should not be mapped")
+	}
+	if _, err := root.Join("missing").Mmap(nil); err == nil {
+		t.Error("Missing file should not be mapped")
+	}
+}
+
+func TestMmapCopyOnWrite(t *testing.T) {
+	root := makeTree(t, map[string]string{"file": "hello"})
+	p := root.Join("file")
+
+	m, err := p.Mmap(&MmapOptions{CopyOnWrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	copy(m.Bytes(), "HELLO")
+	if string(m.Bytes()) != "HELLO" {
+		t.Errorf("Mapped slice should be modified: %q", m.Bytes())
+	}
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "hello" {
+		t.Errorf("Modification should not be written to file: %q", b)
+	}
+}
+
+func TestMmapOtherFS(t *testing.T) {
+	root := makeTree(t, map[string]string{"file": "hello"})
+	r := &recordingFS{FS: OSFS}
+	prev := SetFS(r)
+	defer SetFS(prev)
+
+	m, err := root.Join("file").Mmap(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if string(m.Bytes()) != "hello" {
+		t.Errorf("Unexpected content: %q", m.Bytes())
+	}
+	if len(r.opened) != 1 {
+		t.Errorf("File should be read via current FS: %v", r.opened)
+	}
+}
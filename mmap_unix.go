@@ -0,0 +1,24 @@
+//go:build unix
+// +build unix
+
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+const mmapSupported = true
+
+func mmap(f *os.File, size int, cow bool) ([]byte, error) {
+	prot, flags := unix.PROT_READ, unix.MAP_SHARED
+	if cow {
+		prot, flags = unix.PROT_READ|unix.PROT_WRITE, unix.MAP_PRIVATE
+	}
+	return unix.Mmap(int(f.Fd()), 0, size, prot, flags)
+}
+
+func munmap(b []byte) error {
+	return unix.Munmap(b)
+}
@@ -0,0 +1,41 @@
+package abspath
+
+import (
+	"os"
+	"reflect"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const mmapSupported = true
+
+func mmap(f *os.File, size int, cow bool) ([]byte, error) {
+	prot, access := uint32(windows.PAGE_READONLY), uint32(windows.FILE_MAP_READ)
+	if cow {
+		prot, access = windows.PAGE_WRITECOPY, windows.FILE_MAP_COPY
+	}
+	s := uint64(size)
+	m, err := windows.CreateFileMapping(windows.Handle(f.Fd()), nil, prot, uint32(s>>32), uint32(s), nil)
+	if err != nil {
+		return nil, err
+	}
+	// The view keeps the mapping object alive
+	defer windows.CloseHandle(m)
+
+	addr, err := windows.MapViewOfFile(m, access, 0, 0, uintptr(size))
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	h := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	h.Data = addr
+	h.Len = size
+	h.Cap = size
+	return b, nil
+}
+
+func munmap(b []byte) error {
+	return windows.UnmapViewOfFile(uintptr(unsafe.Pointer(&b[0])))
+}
@@ -0,0 +1,118 @@
+package abspath
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// WithExt returns the path whose extension is replaced with the given extension.  The extension should start with '.'.
+// When the path has no extension, the extension is appended.  When the extension is empty, the extension of the path
+// is removed.  The extension is determined in the same way as Ext().  The root directory is returned as it is.  It
+// returns an error when the extension contains a path separator or the new base name is invalid as WithName().
+//
+// Example:
+//	a, _ := abspath.New("/path/to/foo.proto")
+//	b, err := a.WithExt(".json") // => "/path/to/foo.json"
+func (a AbsPath) WithExt(ext string) (AbsPath, error) {
+	if strings.ContainsAny(ext, `/`+string(filepath.Separator)) {
+		return AbsPath{""}, fmt.Errorf("invalid extension %q", ext)
+	}
+	if a.IsRoot() {
+		return a, nil
+	}
+	b := a.Base().underlying
+	return a.WithName(strings.TrimSuffix(b, filepath.Ext(b)) + ext)
+}
+
+// TrimExt returns the path whose extension is removed.  It is the same as WithExt("").  When the base name consists
+// only of an extension such as '.bashrc', the path is returned as it is.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/foo.txt")
+//	a.TrimExt() // => "/path/to/foo"
+func (a AbsPath) TrimExt() AbsPath {
+	if t, err := a.WithExt(""); err == nil {
+		return t
+	}
+	return a
+}
+
+// Stem returns the base name of the path without its extension.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/foo.txt")
+//	a.Stem() // => "foo"
+func (a AbsPath) Stem() string {
+	b := a.Base().underlying
+	return strings.TrimSuffix(b, filepath.Ext(b))
+}
+
+// compoundExts is a list of extensions which consist of multiple extensions but should be treated as one unit.
+var compoundExts = []string{".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst", ".tar.lz4", ".tar.br", ".tar.z"}
+
+// multiExt returns the extension of the base name.  Well-known compound extensions such as '.tar.gz' are treated as one
+// extension.
+func multiExt(base string) string {
+	l := strings.ToLower(base)
+	for _, e := range compoundExts {
+		if strings.HasSuffix(l, e) && len(l) > len(e) {
+			return base[len(base)-len(e):]
+		}
+	}
+	return filepath.Ext(base)
+}
+
+func (a AbsPath) addSuffix(suffix string, ext func(string) string) (AbsPath, error) {
+	if strings.ContainsAny(suffix, `/`+string(filepath.Separator)) {
+		return AbsPath{""}, fmt.Errorf("invalid suffix %q", suffix)
+	}
+	if a.IsRoot() {
+		return a, nil
+	}
+	b := a.Base().underlying
+	e := ext(b)
+	return a.WithName(strings.TrimSuffix(b, e) + suffix + e)
+}
+
+// AddSuffix returns the path whose base name has the suffix inserted before its extension.  The extension is determined
+// in the same way as Ext().  The root directory is returned as it is.  It returns an error when the suffix contains a
+// path separator.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/report.pdf")
+//	b, err := a.AddSuffix("-v2") // => "/path/to/report-v2.pdf"
+func (a AbsPath) AddSuffix(suffix string) (AbsPath, error) {
+	return a.addSuffix(suffix, filepath.Ext)
+}
+
+// AddSuffixMultiExt is the same as AddSuffix() but well-known compound extensions such as '.tar.gz' are treated as one
+// extension.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/backup.tar.gz")
+//	b, err := a.AddSuffix("-old")         // => "/path/to/backup.tar-old.gz"
+//	c, err := a.AddSuffixMultiExt("-old") // => "/path/to/backup-old.tar.gz"
+func (a AbsPath) AddSuffixMultiExt(suffix string) (AbsPath, error) {
+	return a.addSuffix(suffix, multiExt)
+}
+
+// WithName returns the path whose base name is replaced with the given name.  In other words, it returns the sibling
+// path with the name.  The name must not be empty, '.' nor '..' and must not contain any path separator.  It returns an
+// error when the name is invalid or the path is the root directory.  The parent is determined in the same way as Dir()
+// so '..' components of a path created with NoClean() option are kept.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/foo.txt")
+//	b, err := a.WithName("bar.txt") // => "/path/to/bar.txt"
+func (a AbsPath) WithName(name string) (AbsPath, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/`+string(filepath.Separator)) {
+		return AbsPath{""}, fmt.Errorf("invalid file name %q", name)
+	}
+	if a.IsRoot() {
+		return AbsPath{""}, fmt.Errorf("cannot replace name of root directory '%s'", a.underlying)
+	}
+	// Not joined with filepath.Join() since it would resolve '..' components of a path created with NoClean() lexically
+	p, i, _ := a.lastSeparator()
+	return AbsPath{p[:i+1] + name}, nil
+}
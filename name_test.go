@@ -0,0 +1,177 @@
+package abspath
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWithExt(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		ext      string
+		expected string
+	}{
+		{"/path/to/foo.proto", ".json", "/path/to/foo.json"},
+		{"/path/to/foo", ".txt", "/path/to/foo.txt"},
+		{"/path/to/foo.tar.gz", ".zip", "/path/to/foo.tar.zip"},
+		{"/path/to/foo.txt", "", "/path/to/foo"},
+		{"/path.d/foo", ".txt", "/path.d/foo.txt"},
+		{"/", ".txt", "/"},
+	} {
+		a, _ := FromSlash(fixAbsPath(c.input))
+		e, _ := FromSlash(fixAbsPath(c.expected))
+		r, err := a.WithExt(c.ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r != e {
+			t.Errorf("Expected %s but actually %s", e, r)
+		}
+	}
+}
+
+func TestWithExtInvalid(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/path/to/foo.txt"))
+	for _, ext := range []string{"/../x", "/x", ".d/x", "." + string(filepath.Separator) + "x"} {
+		if r, err := a.WithExt(ext); err == nil {
+			t.Errorf("Error should occur for extension %q but got %s", ext, r)
+		}
+	}
+	d, _ := FromSlash(fixAbsPath("/path/to/.bashrc"))
+	if r, err := d.WithExt(""); err == nil {
+		t.Errorf("Error should occur for empty base name but got %s", r)
+	}
+}
+
+func TestTrimExt(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/path/to/foo.txt"))
+	e, _ := FromSlash(fixAbsPath("/path/to/foo"))
+	if r := a.TrimExt(); r != e {
+		t.Errorf("Expected %s but actually %s", e, r)
+	}
+	if r := e.TrimExt(); r != e {
+		t.Errorf("Expected %s but actually %s", e, r)
+	}
+	d, _ := FromSlash(fixAbsPath("/path/to/.bashrc"))
+	if r := d.TrimExt(); r != d {
+		t.Errorf("Expected %s but actually %s", d, r)
+	}
+}
+
+func TestStem(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected string
+	}{
+		{"/path/to/foo.txt", "foo"},
+		{"/path/to/foo", "foo"},
+		{"/path/to/foo.tar.gz", "foo.tar"},
+		{"/path.d/foo", "foo"},
+	} {
+		a, _ := FromSlash(fixAbsPath(c.input))
+		if s := a.Stem(); s != c.expected {
+			t.Errorf("Expected %q but actually %q", c.expected, s)
+		}
+	}
+}
+
+func TestAddSuffix(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected string
+		multi    string
+	}{
+		{"/path/to/report.pdf", "/path/to/report-v2.pdf", "/path/to/report-v2.pdf"},
+		{"/path/to/report", "/path/to/report-v2", "/path/to/report-v2"},
+		{"/path/to/backup.tar.gz", "/path/to/backup.tar-v2.gz", "/path/to/backup-v2.tar.gz"},
+		{"/path/to/BACKUP.TAR.XZ", "/path/to/BACKUP.TAR-v2.XZ", "/path/to/BACKUP-v2.TAR.XZ"},
+		{"/path/to/.tar.gz", "/path/to/.tar-v2.gz", "/path/to/.tar-v2.gz"},
+		{"/path.d/foo", "/path.d/foo-v2", "/path.d/foo-v2"},
+		{"/", "/", "/"},
+	} {
+		a, _ := FromSlash(fixAbsPath(c.input))
+		e, _ := FromSlash(fixAbsPath(c.expected))
+		m, _ := FromSlash(fixAbsPath(c.multi))
+		if r, err := a.AddSuffix("-v2"); err != nil || r != e {
+			t.Errorf("Expected %s but actually %s (%v)", e, r, err)
+		}
+		if r, err := a.AddSuffixMultiExt("-v2"); err != nil || r != m {
+			t.Errorf("Expected %s but actually %s (%v)", m, r, err)
+		}
+	}
+}
+
+func TestAddSuffixInvalid(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/path/to/backup.tar.gz"))
+	for _, suffix := range []string{"/../x", "-v2/x", string(filepath.Separator) + "x"} {
+		if r, err := a.AddSuffix(suffix); err == nil {
+			t.Errorf("Error should occur for suffix %q but got %s", suffix, r)
+		}
+		if r, err := a.AddSuffixMultiExt(suffix); err == nil {
+			t.Errorf("Error should occur for suffix %q but got %s", suffix, r)
+		}
+	}
+}
+
+func TestWithName(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/path/to/foo.txt"))
+	e, _ := FromSlash(fixAbsPath("/path/to/bar.txt"))
+	r, err := a.WithName("bar.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r != e {
+		t.Errorf("Expected %s but actually %s", e, r)
+	}
+
+	for _, n := range []string{"", ".", "..", "foo/bar", string(filepath.Separator) + "foo"} {
+		if _, err := a.WithName(n); err == nil {
+			t.Errorf("Error was expected for name %q", n)
+		}
+	}
+
+	root, _ := FromSlash(fixAbsPath("/"))
+	if _, err := root.WithName("foo"); err == nil {
+		t.Errorf("Root directory must cause an error")
+	}
+}
+
+func TestNameMethodsWithUncleanPath(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		opt      Option
+		ext      string
+		stem     string
+		withName string
+		withExt  string
+		suffixed string
+	}{
+		{"/foo/bar.txt/", KeepTrailingSeparator(), ".txt", "bar", "/foo/baz.txt", "/foo/bar.json", "/foo/bar-v2.txt"},
+		{"/foo//bar.txt/.", NoClean(), ".txt", "bar", "/foo/baz.txt", "/foo/bar.json", "/foo/bar-v2.txt"},
+		{"/foo/./x/../bar.txt", NoClean(), ".txt", "bar", "/foo/x/../baz.txt", "/foo/x/../bar.json", "/foo/x/../bar-v2.txt"},
+	} {
+		a, err := NewWithOptions(filepath.FromSlash(fixAbsPath(c.input)), c.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := a.Ext(); e != c.ext {
+			t.Errorf("Ext() of %q should be %q but got %q", a, c.ext, e)
+		}
+		if s := a.Stem(); s != c.stem {
+			t.Errorf("Stem() of %q should be %q but got %q", a, c.stem, s)
+		}
+
+		want := filepath.FromSlash(fixAbsPath(c.withName))
+		if r, err := a.WithName("baz.txt"); err != nil || r.String() != want {
+			t.Errorf("WithName() of %q should be %q but got %q (%v)", a, want, r, err)
+		}
+		want = filepath.FromSlash(fixAbsPath(c.withExt))
+		if r, err := a.WithExt(".json"); err != nil || r.String() != want {
+			t.Errorf("WithExt() of %q should be %q but got %q (%v)", a, want, r, err)
+		}
+		want = filepath.FromSlash(fixAbsPath(c.suffixed))
+		if r, err := a.AddSuffix("-v2"); err != nil || r.String() != want {
+			t.Errorf("AddSuffix() of %q should be %q but got %q (%v)", a, want, r, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SymlinkEscapeError is an error returned when a path escapes from the root directory after resolving symbolic links.
+type SymlinkEscapeError struct {
+	// Path is the path which escapes from the root.
+	Path AbsPath
+	// Resolved is the path after resolving symbolic links.
+	Resolved AbsPath
+	// Root is the root directory which the path must not escape from.
+	Root AbsPath
+}
+
+func (err *SymlinkEscapeError) Error() string {
+	return fmt.Sprintf("Path '%s' escapes from '%s' since it is resolved to '%s'", err.Path.underlying, err.Root.underlying, err.Resolved.underlying)
+}
+
+type options struct {
+	noClean       bool
+	keepTrailing  bool
+	mustExist     bool
+	symlinkRoot   AbsPath
+	checkSymlinks bool
+}
+
+// Option is an option for NewWithOptions() and ExpandFromWithOptions().
+type Option func(o *options)
+
+// NoClean makes constructors keep the path as given without cleaning it.  Redundant separators, '.' and '..'
+// components are kept so that '..' after a symbolic link is resolved physically by the OS instead of lexically.  Note
+// that methods which split the path into components, such as Base(), Dir(), Segments() and ContainsPath(), ignore
+// redundant separators, '.' components and a trailing separator, but treat '..' components as names.
+func NoClean() Option {
+	return func(o *options) {
+		o.noClean = true
+	}
+}
+
+// KeepTrailingSeparator makes constructors keep a trailing path separator of the given path.  The path is cleaned,
+// then the separator is appended again.  It is useful when a trailing separator has a meaning, for example, rsync
+// treats 'src/' and 'src' differently.  Methods which split the path into components, such as Base(), Dir() and
+// Segments(), ignore the trailing separator.
+func KeepTrailingSeparator() Option {
+	return func(o *options) {
+		o.keepTrailing = true
+	}
+}
+
+// RequireExisting makes constructors return an error when nothing exists at the path.
+func RequireExisting() Option {
+	return func(o *options) {
+		o.mustExist = true
+	}
+}
+
+// DisallowSymlinkEscape makes constructors return *SymlinkEscapeError when the path is not under the root after
+// resolving symbolic links in both of them.  The path does not need to exist.  It is useful to validate a path given
+// by an untrusted user which must stay in a sandbox directory.
+func DisallowSymlinkEscape(root AbsPath) Option {
+	return func(o *options) {
+		o.symlinkRoot = root
+		o.checkSymlinks = true
+	}
+}
+
+func (o *options) apply(given string, a AbsPath) (AbsPath, error) {
+	if o.keepTrailing && len(given) > 0 && os.IsPathSeparator(given[len(given)-1]) && !a.IsRoot() {
+		a = AbsPath{a.underlying + string(filepath.Separator)}
+	}
+
+	if o.mustExist {
+		if _, err := fsys().Stat(a.underlying); err != nil {
+			return AbsPath{""}, err
+		}
+	}
+
+	if o.checkSymlinks {
+		root, err := o.symlinkRoot.Resolve(nil)
+		if err != nil {
+			return AbsPath{""}, err
+		}
+		r, err := a.Resolve(nil)
+		if err != nil {
+			return AbsPath{""}, err
+		}
+		contained := root.ContainsPath(r)
+		if caseInsensitiveOS {
+			contained = root.ContainsPathFold(r)
+		}
+		if !contained {
+			return AbsPath{""}, &SymlinkEscapeError{a, r, o.symlinkRoot}
+		}
+	}
+
+	return a, nil
+}
+
+// NewWithOptions is the same as New() but its behavior can be changed with options.
+//
+// Example:
+//	root, _ := abspath.New("/srv/sandbox")
+//	a, err := abspath.NewWithOptions(input, abspath.RequireExisting(), abspath.DisallowSymlinkEscape(root))
+func NewWithOptions(from string, opts ...Option) (AbsPath, error) {
+	o := &options{}
+	for _, f := range opts {
+		f(o)
+	}
+
+	if !filepath.IsAbs(from) {
+		return AbsPath{""}, &NotAbsolutePathError{from}
+	}
+	a := AbsPath{from}
+	if !o.noClean {
+		a = AbsPath{filepath.Clean(from)}
+	}
+	return o.apply(from, a)
+}
+
+// ExpandFromWithOptions is the same as ExpandFrom() but its behavior can be changed with options.
+//
+// Example:
+//	a, err := abspath.ExpandFromWithOptions("~/.config/", abspath.KeepTrailingSeparator())
+func ExpandFromWithOptions(specified string, opts ...Option) (AbsPath, error) {
+	o := &options{}
+	for _, f := range opts {
+		f(o)
+	}
+
+	var a AbsPath
+	if o.noClean {
+		if specified == "" {
+			return AbsPath{""}, &NotAbsolutePathError{""}
+		}
+		base := ""
+		rel := specified
+		if specified[0] == '~' {
+			h, err := HomeDir()
+			if err != nil {
+				return AbsPath{""}, err
+			}
+			base, rel = h.underlying, specified[1:]
+		} else if !filepath.IsAbs(specified) {
+			cwd, err := getwd()
+			if err != nil {
+				return AbsPath{""}, err
+			}
+			base = cwd
+		}
+		if base != "" && rel != "" && !os.IsPathSeparator(base[len(base)-1]) && !os.IsPathSeparator(rel[0]) {
+			base += string(filepath.Separator)
+		}
+		a = AbsPath{base + rel}
+	} else {
+		p, err := ExpandFrom(specified)
+		if err != nil {
+			return AbsPath{""}, err
+		}
+		a = p
+	}
+	return o.apply(specified, a)
+}
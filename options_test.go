@@ -0,0 +1,186 @@
+package abspath
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewWithOptions(t *testing.T) {
+	sep := string(filepath.Separator)
+	unclean := filepath.FromSlash(fixAbsPath("/foo/./bar/../baz/"))
+
+	a, err := NewWithOptions(unclean)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != filepath.Clean(unclean) {
+		t.Errorf("Path should be cleaned by default: %q", a)
+	}
+
+	a, err = NewWithOptions(unclean, NoClean())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != unclean {
+		t.Errorf("Path should not be cleaned: %q", a)
+	}
+	if a.Base().String() != "baz" {
+		t.Errorf("Base of path with trailing separator should be its last component: %q", a.Base())
+	}
+
+	a, err = NewWithOptions(unclean, KeepTrailingSeparator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != filepath.Clean(unclean)+sep {
+		t.Errorf("Trailing separator should be kept: %q", a)
+	}
+	root := filepath.FromSlash(fixAbsPath("/"))
+	if a, err := NewWithOptions(root, KeepTrailingSeparator()); err != nil || a.String() != root {
+		t.Errorf("Root should not have an additional separator: %q %v", a, err)
+	}
+	if a, err := NewWithOptions(filepath.Clean(unclean), KeepTrailingSeparator()); err != nil || a.String() != filepath.Clean(unclean) {
+		t.Errorf("Separator should not be added when input has no trailing separator: %q %v", a, err)
+	}
+
+	if _, err := NewWithOptions("relative", NoClean()); err == nil {
+		t.Error("Relative path should cause an error")
+	}
+}
+
+func TestRequireExistingOption(t *testing.T) {
+	root := makeTree(t, map[string]string{"file": ""})
+	if _, err := NewWithOptions(root.Join("file").String(), RequireExisting()); err != nil {
+		t.Error(err)
+	}
+	if _, err := NewWithOptions(root.Join("missing").String(), RequireExisting()); !os.IsNotExist(err) {
+		t.Errorf("Not existing path should cause an error: %v", err)
+	}
+}
+
+func TestDisallowSymlinkEscapeOption(t *testing.T) {
+	if isWindows {
+		t.Skip("Creating symbolic links requires privilege on Windows")
+	}
+	root := makeTree(t, map[string]string{"sandbox/file": "", "outside/secret": ""})
+	sandbox := root.Join("sandbox")
+	if err := os.Symlink(root.Join("outside").String(), sandbox.Join("escape").String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("file", sandbox.Join("inside").String()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []AbsPath{
+		sandbox.Join("file"),
+		sandbox.Join("inside"),
+		sandbox.Join("not-exist", "yet"),
+	} {
+		if _, err := NewWithOptions(p.String(), DisallowSymlinkEscape(sandbox)); err != nil {
+			t.Errorf("%q should be allowed: %v", p, err)
+		}
+	}
+
+	for _, p := range []AbsPath{
+		sandbox.Join("escape", "secret"),
+		sandbox.Join("escape", "not-exist"),
+		root.Join("outside"),
+	} {
+		_, err := NewWithOptions(p.String(), DisallowSymlinkEscape(sandbox))
+		if _, ok := err.(*SymlinkEscapeError); !ok {
+			t.Errorf("%q should not be allowed: %v", p, err)
+		}
+	}
+}
+
+func TestExpandFromWithOptions(t *testing.T) {
+	cwd, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sep := string(filepath.Separator)
+
+	a, err := ExpandFromWithOptions("foo"+sep+".."+sep+"bar", NoClean())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != cwd.String()+sep+"foo"+sep+".."+sep+"bar" {
+		t.Errorf("Relative path should be joined without cleaning: %q", a)
+	}
+
+	a, err = ExpandFromWithOptions("foo"+sep, KeepTrailingSeparator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != cwd.Join("foo").String()+sep {
+		t.Errorf("Trailing separator should be kept: %q", a)
+	}
+
+	h, err := HomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err = ExpandFromWithOptions("~", NoClean())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != h {
+		t.Errorf("'~' should be expanded to home directory: %q", a)
+	}
+
+	if _, err := ExpandFromWithOptions("", NoClean()); err == nil {
+		t.Error("Empty path should cause an error")
+	}
+}
+
+func TestUncleanPathComponents(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		opt   Option
+		segs  []string
+		base  string
+		dir   string
+	}{
+		{"/a//b", NoClean(), []string{"a", "b"}, "b", "/a"},
+		{"/a/./b/.", NoClean(), []string{"a", "b"}, "b", "/a"},
+		{"/a/b/", NoClean(), []string{"a", "b"}, "b", "/a"},
+		{"/a/../b", NoClean(), []string{"a", "..", "b"}, "b", "/a/.."},
+		{"/a/b/..", NoClean(), []string{"a", "b", ".."}, "..", "/a/b"},
+		{"/a/b/", KeepTrailingSeparator(), []string{"a", "b"}, "b", "/a"},
+		{"//", NoClean(), []string{}, "/", "/"},
+	} {
+		a, err := NewWithOptions(filepath.FromSlash(fixAbsPath(tc.input)), tc.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := a.Segments(); !reflect.DeepEqual(s, tc.segs) {
+			t.Errorf("Segments() of %q should be %q but got %q", a, tc.segs, s)
+		}
+		if d := a.Depth(); d != len(tc.segs) {
+			t.Errorf("Depth() of %q should be %d but got %d", a, len(tc.segs), d)
+		}
+		if b := a.Base().String(); b != filepath.FromSlash(tc.base) {
+			t.Errorf("Base() of %q should be %q but got %q", a, tc.base, b)
+		}
+		if d, want := a.Dir().String(), filepath.FromSlash(fixAbsPath(tc.dir)); d != want {
+			t.Errorf("Dir() of %q should be %q but got %q", a, want, d)
+		}
+	}
+
+	clean, _ := New(filepath.FromSlash(fixAbsPath("/a/b")))
+	for _, s := range []string{"/a//b/", "/a/./b"} {
+		a, err := NewWithOptions(filepath.FromSlash(fixAbsPath(s)), NoClean())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !a.ContainsPath(clean) || !clean.ContainsPath(a) {
+			t.Errorf("%q and %q should contain each other", a, clean)
+		}
+	}
+	dotdot, _ := NewWithOptions(filepath.FromSlash(fixAbsPath("/a/../b")), NoClean())
+	if !clean.Dir().ContainsPath(dotdot) || dotdot.ContainsPath(clean) {
+		t.Errorf("'..' component should be compared as a name: %q", dotdot)
+	}
+}
@@ -0,0 +1,72 @@
+// Package oteltrace provides an implementation of abspath.Tracer with OpenTelemetry so that spans of long-running
+// operations of abspath package such as walking and copying directory trees are recorded in distributed traces.
+//
+// Example:
+//	abspath.SetTracer(oteltrace.New(otel.Tracer("myapp")))
+//
+// Ref: https://opentelemetry.io/docs/instrumentation/go/
+package oteltrace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rhysd/abspath"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer is an abspath.Tracer which starts spans with an OpenTelemetry tracer.
+type Tracer struct {
+	t trace.Tracer
+}
+
+// New creates a new Tracer which starts spans with the given OpenTelemetry tracer.
+//
+// Example:
+//	prev := abspath.SetTracer(oteltrace.New(otel.Tracer("myapp")))
+//	defer abspath.SetTracer(prev)
+func New(t trace.Tracer) *Tracer {
+	return &Tracer{t}
+}
+
+// Start starts an OpenTelemetry span as a child of the span in the context.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...abspath.SpanAttr) (context.Context, abspath.Span) {
+	ctx, s := t.t.Start(ctx, name, trace.WithAttributes(convert(attrs)...))
+	return ctx, span{s}
+}
+
+// span is an abspath.Span which wraps an OpenTelemetry span.
+type span struct {
+	s trace.Span
+}
+
+func (s span) SetAttributes(attrs ...abspath.SpanAttr) {
+	s.s.SetAttributes(convert(attrs)...)
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+	s.s.End()
+}
+
+func convert(attrs []abspath.SpanAttr) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(a.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(a.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(a.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(a.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
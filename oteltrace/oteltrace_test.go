@@ -0,0 +1,118 @@
+package oteltrace
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rhysd/abspath"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+)
+
+type testSpan struct {
+	trace.Span
+	name   string
+	attrs  map[attribute.Key]attribute.Value
+	status codes.Code
+	ended  bool
+}
+
+func (s *testSpan) SetAttributes(kvs ...attribute.KeyValue) {
+	for _, kv := range kvs {
+		s.attrs[kv.Key] = kv.Value
+	}
+}
+
+func (s *testSpan) SetStatus(c codes.Code, _ string) {
+	s.status = c
+}
+
+func (s *testSpan) RecordError(error, ...trace.EventOption) {}
+
+func (s *testSpan) End(...trace.SpanEndOption) {
+	s.ended = true
+}
+
+type testTracer struct {
+	embedded.Tracer
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	s := &testSpan{
+		Span:  trace.SpanFromContext(context.Background()),
+		name:  name,
+		attrs: map[attribute.Key]attribute.Value{},
+	}
+	cfg := trace.NewSpanStartConfig(opts...)
+	s.SetAttributes(cfg.Attributes()...)
+	t.spans = append(t.spans, s)
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+func TestWalkSpan(t *testing.T) {
+	d := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(d, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	root, err := abspath.New(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := &testTracer{}
+	prev := abspath.SetTracer(New(tr))
+	defer abspath.SetTracer(prev)
+
+	if err := root.Walk(func(string, os.FileInfo, error) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.spans) != 1 {
+		t.Fatalf("One span should be started but got %d", len(tr.spans))
+	}
+	s := tr.spans[0]
+	if s.name != "abspath.Walk" || !s.ended || s.status == codes.Error {
+		t.Errorf("Unexpected span: %+v", s)
+	}
+	if v := s.attrs["abspath.path"]; v.AsString() != root.String() {
+		t.Errorf("Path attribute should be %q but got %q", root, v.AsString())
+	}
+	if v := s.attrs["abspath.entries"]; v.AsInt64() != 2 {
+		t.Errorf("Entries attribute should be 2 but got %d", v.AsInt64())
+	}
+
+	if err := root.Join("missing").CopyDir(root.Join("dst"), nil); err == nil {
+		t.Fatal("Error did not occur")
+	}
+	if s := tr.spans[1]; s.name != "abspath.CopyDir" || !s.ended || s.status != codes.Error {
+		t.Errorf("Error should be recorded: %+v", s)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	kvs := convert([]abspath.SpanAttr{
+		{Key: "s", Value: "foo"},
+		{Key: "i", Value: int64(42)},
+		{Key: "b", Value: true},
+		{Key: "o", Value: 1.5},
+	})
+	want := []attribute.KeyValue{
+		attribute.String("s", "foo"),
+		attribute.Int64("i", 42),
+		attribute.Bool("b", true),
+		attribute.String("o", "1.5"),
+	}
+	if len(kvs) != len(want) {
+		t.Fatalf("Wanted %v but got %v", want, kvs)
+	}
+	for i, kv := range kvs {
+		if kv != want[i] {
+			t.Errorf("Wanted %v but got %v", want[i], kv)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PermOptions is a set of options for ChmodR() and ChownR() methods.  Patterns are matched in the same way as
+// CountOptions.
+type PermOptions struct {
+	// Include is a list of glob patterns.  When it is not empty, only files matching one of them are changed.
+	// Directories are changed regardless of it.
+	Include []string
+	// Exclude is a list of glob patterns.  Matched entries are not changed and matched directories are not entered.
+	Exclude []string
+	// DirExec makes ChmodR() add execute permission to directories for each class (user, group and others) which gets
+	// read permission, as 'X' of chmod(1) does.  Files which already have some execute permission get it as well so
+	// that executables keep executable.  It is ignored by ChownR().
+	DirExec bool
+}
+
+// walkPerm calls fn for every entry in the tree which is not filtered out by the options.  Symbolic links are not
+// followed.  Directories are passed after all entries under them so that changing their permissions does not prevent
+// walking.
+func (a AbsPath) walkPerm(opts *PermOptions, fn func(p string, info os.FileInfo) error) error {
+	if opts == nil {
+		opts = &PermOptions{}
+	}
+
+	dirs := []string{}
+	infos := map[string]os.FileInfo{}
+	err := walk(a.underlying, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if p != a.underlying {
+			rel, err := filepath.Rel(a.underlying, p)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+
+			m, err := matchPatterns(opts.Exclude, rel)
+			if err != nil {
+				return err
+			}
+			if m {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !info.IsDir() && len(opts.Include) > 0 {
+				m, err := matchPatterns(opts.Include, rel)
+				if err != nil {
+					return err
+				}
+				if !m {
+					return nil
+				}
+			}
+		}
+
+		if info.IsDir() {
+			dirs = append(dirs, p)
+			infos[p] = info
+			return nil
+		}
+		return fn(p, info)
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := fn(dirs[i], infos[dirs[i]]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ChmodR changes permissions of all entries in the directory tree rooted at the path to the mode, as 'chmod -R'.
+// Only permission bits of the mode are used.  Symbolic links are neither followed nor changed.  When DirExec option is
+// set, directories get execute permission for each class which gets read permission, as 'chmod -R a=rX'.  Directories
+// are changed after entries under them.  opts can be nil.
+//
+// Example:
+//	// Files get 0644 and directories get 0755
+//	dir, _ := abspath.New("/opt/myapp/share")
+//	err := dir.ChmodR(0644, &abspath.PermOptions{DirExec: true})
+func (a AbsPath) ChmodR(mode os.FileMode, opts *PermOptions) (err error) {
+	defer annotate(&err, "chmod", a)
+	mode &= os.ModePerm
+	execOnRead := false
+	if opts != nil {
+		execOnRead = opts.DirExec
+	}
+	x := (mode & 0444) >> 2
+
+	return a.walkPerm(opts, func(p string, info os.FileInfo) error {
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		m := mode
+		if execOnRead && (info.IsDir() || info.Mode()&0111 != 0) {
+			m |= x
+		}
+		return fsys().Chmod(p, m)
+	})
+}
+
+// lchowner is implemented by FS which can change owners of files without following symbolic links.
+type lchowner interface {
+	Lchown(name string, uid, gid int) error
+}
+
+// ChownR changes the owner and the group of all entries in the directory tree rooted at the path, as 'chown -R'.  A
+// uid or gid of -1 means not changing it.  Symbolic links are not followed and their own owners are changed.  When the
+// current FS is not the OS filesystem, it must implement 'Lchown(name string, uid, gid int) error' method.  Otherwise
+// an error which satisfies errors.Is(err, ErrUnsupported) is returned.  DirExec option is ignored.  opts can be nil.
+//
+// Example:
+//	dir, _ := abspath.New("/srv/www")
+//	err := dir.ChownR(33, 33, &abspath.PermOptions{Exclude: []string{".git"}})
+func (a AbsPath) ChownR(uid, gid int, opts *PermOptions) (err error) {
+	defer annotate(&err, "chown", a)
+	var chown func(string, int, int) error
+	if usesOSFS() {
+		chown = os.Lchown
+	} else if c, ok := rawFS().(lchowner); ok {
+		chown = c.Lchown
+	} else {
+		return &os.PathError{Op: "lchown", Path: a.underlying, Err: ErrUnsupported}
+	}
+
+	detail := fmt.Sprintf("uid=%d gid=%d", uid, gid)
+	return a.walkPerm(opts, func(p string, info os.FileInfo) error {
+		return audit("lchown", detail, func() error { return chown(p, uid, gid) }, p)
+	})
+}
@@ -0,0 +1,142 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestChmodR(t *testing.T) {
+	if isWindows {
+		t.Skip("Permission bits are not supported on Windows")
+	}
+
+	for _, tc := range []struct {
+		what string
+		mode os.FileMode
+		opts *PermOptions
+		want map[string]os.FileMode
+	}{
+		{
+			what: "nil options",
+			mode: 0700,
+			opts: nil,
+			want: map[string]os.FileMode{"": 0700, "a.txt": 0700, "run.sh": 0700, "d": 0700, "d/b.go": 0700},
+		},
+		{
+			what: "directories get execute bits",
+			mode: 0644,
+			opts: &PermOptions{DirExec: true},
+			want: map[string]os.FileMode{"": 0755, "a.txt": 0644, "run.sh": 0755, "d": 0755, "d/b.go": 0644},
+		},
+		{
+			what: "execute bits only for readable classes",
+			mode: 0640,
+			opts: &PermOptions{DirExec: true},
+			want: map[string]os.FileMode{"": 0750, "a.txt": 0640, "run.sh": 0750, "d": 0750, "d/b.go": 0640},
+		},
+		{
+			what: "include and exclude",
+			mode: 0600,
+			opts: &PermOptions{Include: []string{"*.{go,txt}"}, Exclude: []string{"d"}},
+			want: map[string]os.FileMode{"": 0600, "a.txt": 0600, "run.sh": 0755, "d": 0755, "d/b.go": 0644},
+		},
+	} {
+		t.Run(tc.what, func(t *testing.T) {
+			root := makeTree(t, map[string]string{"a.txt": "", "run.sh": "", "d/b.go": ""})
+			for _, p := range []string{"", "d"} {
+				if err := os.Chmod(root.Join(p).String(), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for p, m := range map[string]os.FileMode{"a.txt": 0644, "run.sh": 0755, "d/b.go": 0644} {
+				if err := os.Chmod(root.Join(filepath.FromSlash(p)).String(), m); err != nil {
+					t.Fatal(err)
+				}
+			}
+			defer os.Chmod(root.String(), 0755)
+
+			if err := root.ChmodR(tc.mode, tc.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := os.Stat(root.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have, want := s.Mode().Perm(), tc.want[""]; have != want {
+				t.Errorf("Mode of root should be %o but got %o", want, have)
+			}
+
+			os.Chmod(root.String(), 0755) // Ensure entries are accessible
+			for p, want := range tc.want {
+				if p == "" {
+					continue
+				}
+				s, err := os.Stat(root.Join(filepath.FromSlash(p)).String())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if have := s.Mode().Perm(); have != want {
+					t.Errorf("Mode of %q should be %o but got %o", p, want, have)
+				}
+			}
+		})
+	}
+}
+
+func TestChmodRDoesNotFollowSymlinks(t *testing.T) {
+	if isWindows {
+		t.Skip("Symlink requires privilege on Windows")
+	}
+
+	outside := makeTree(t, map[string]string{"secret.txt": ""})
+	target := outside.Join("secret.txt")
+	if err := os.Chmod(target.String(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	root := makeTree(t, map[string]string{})
+	if err := os.Symlink(target.String(), root.Join("link").String()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := root.ChmodR(0755, nil); err != nil {
+		t.Fatal(err)
+	}
+	s, err := os.Stat(target.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode().Perm() != 0600 {
+		t.Errorf("Target of symlink should not be changed but got %o", s.Mode().Perm())
+	}
+}
+
+func TestChownR(t *testing.T) {
+	if isWindows {
+		t.Skip("Chown is not supported on Windows")
+	}
+
+	root := makeTree(t, map[string]string{"a.txt": "", "d/b.txt": ""})
+	// Changing owner to oneself is always permitted
+	if err := root.ChownR(os.Getuid(), -1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.ChownR(-1, -1, &PermOptions{Exclude: []string{"d"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.Join("missing").ChownR(-1, -1, nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
+
+func TestChownRUnsupportedFS(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": ""})
+	prev := SetFS(&recordingFS{FS: OSFS})
+	defer SetFS(prev)
+
+	if err := root.ChownR(-1, -1, nil); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Unsupported error should be returned but got %v", err)
+	}
+}
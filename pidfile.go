@@ -0,0 +1,170 @@
+package abspath
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+)
+
+// maxPidFileAttempts is the maximum number of attempts to replace a stale PID file.
+const maxPidFileAttempts = 5
+
+// PidFileError is an error returned from AcquirePidFile() when the PID file is owned by another running process.
+// errors.Is(err, ErrLocked) returns true for this error.
+type PidFileError struct {
+	// Path is the path of the PID file.
+	Path AbsPath
+	// Pid is the process ID of the owner.
+	Pid int
+}
+
+func (err *PidFileError) Error() string {
+	return fmt.Sprintf("PID file '%s' is owned by running process %d", err.Path.underlying, err.Pid)
+}
+
+// Is returns true when the target is ErrLocked.
+func (err *PidFileError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// PidFile is a PID file acquired by AcquirePidFile().  It must be released with Release() method.
+type PidFile struct {
+	path AbsPath
+	pid  int
+	// StalePid is the process ID of the previous owner which had exited without removing the PID file.  It is zero
+	// when there was no stale PID file.
+	StalePid int
+}
+
+// Path returns the path of the PID file.
+func (f *PidFile) Path() AbsPath {
+	return f.path
+}
+
+// Pid returns the process ID written to the PID file.
+func (f *PidFile) Pid() int {
+	return f.pid
+}
+
+// Release removes the PID file.  When the PID file was replaced by another process, it is not removed.
+func (f *PidFile) Release() error {
+	pid, err := readPid(f.path.underlying)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pid != f.pid {
+		return nil
+	}
+	return fsys().Remove(f.path.underlying)
+}
+
+func readPid(p string) (int, error) {
+	f, err := fsys().Open(p)
+	if err != nil {
+		return 0, err
+	}
+	b, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return 0, err
+	}
+	s := string(bytes.TrimSpace(b))
+	pid, err := strconv.Atoi(s)
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("PID file '%s' contains invalid PID %q", p, s)
+	}
+	return pid, nil
+}
+
+func createPidFile(p string, pid int) error {
+	f, err := fsys().OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte(strconv.Itoa(pid) + "\n")); err != nil {
+		f.Close()
+		fsys().Remove(p)
+		return err
+	}
+	return f.Close()
+}
+
+// removeStalePidFile removes the PID file only when it still contains the stale PID.  The file is renamed to a unique
+// name before checking its content so that a fresh PID file created by another process in the meantime is never
+// removed.  Such a file is renamed back to the path.
+func removeStalePidFile(p string, stale int) error {
+	tmp := fmt.Sprintf("%s.stale-%d-%d", p, os.Getpid(), time.Now().UnixNano())
+	if err := fsys().Rename(p, tmp); err != nil {
+		return err
+	}
+	if pid, err := readPid(tmp); err != nil || pid != stale {
+		return fsys().Rename(tmp, p)
+	}
+	return fsys().Remove(tmp)
+}
+
+// AcquirePidFile creates the PID file at the path and writes the current process ID to it.  The file is created
+// exclusively so that only one process can own it at the same time.  When the PID file already exists and its owner
+// is still running, *PidFileError is returned.  When the owner process has exited without removing the file, the
+// stale file is replaced and the PID of the previous owner is reported in StalePid field.  It is useful to ensure only
+// one instance of a daemon or a CLI is running.  A stale file is replaced only while it still contains the PID of the
+// exited owner so that processes acquiring the PID file at the same time never remove each other's file.
+//
+// Example:
+//	p, _ := abspath.New("/var/run/mydaemon.pid")
+//	f, err := abspath.AcquirePidFile(p)
+//	if errors.Is(err, abspath.ErrLocked) {
+//		fmt.Println("Already running:", err)
+//		os.Exit(1)
+//	}
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer f.Release()
+//	if f.StalePid != 0 {
+//		fmt.Println("Previous instance", f.StalePid, "did not exit cleanly")
+//	}
+func AcquirePidFile(p AbsPath) (_ *PidFile, err error) {
+	defer annotate(&err, "acquire PID file", p)
+	pid := os.Getpid()
+	stale := 0
+	for i := 0; i < maxPidFileAttempts; i++ {
+		err := createPidFile(p.underlying, pid)
+		if err == nil {
+			return &PidFile{p, pid, stale}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+
+		owner, err := readPid(p.underlying)
+		if os.IsNotExist(err) {
+			continue // Removed by the owner just now
+		}
+		if err != nil {
+			return nil, err
+		}
+		if owner == pid {
+			return nil, &PidFileError{p, owner}
+		}
+		alive, err := processAlive(owner)
+		if err != nil {
+			return nil, err
+		}
+		if alive {
+			return nil, &PidFileError{p, owner}
+		}
+
+		stale = owner
+		if err := removeStalePidFile(p.underlying, owner); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("could not acquire PID file '%s' since it was replaced repeatedly", p.underlying)
+}
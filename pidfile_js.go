@@ -0,0 +1,6 @@
+package abspath
+
+// processAlive always returns an error since other processes are not visible on GOOS=js.
+func processAlive(pid int) (bool, error) {
+	return false, &UnsupportedError{"Checking process", nil}
+}
@@ -0,0 +1,15 @@
+package abspath
+
+import (
+	"os"
+	"strconv"
+)
+
+// processAlive returns whether the process is running by checking /proc.
+func processAlive(pid int) (bool, error) {
+	_, err := os.Stat("/proc/" + strconv.Itoa(pid))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
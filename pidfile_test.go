@@ -0,0 +1,175 @@
+package abspath
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAcquirePidFile(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+	p := root.Join("app.pid")
+
+	f, err := AcquirePidFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Path() != p || f.Pid() != os.Getpid() || f.StalePid != 0 {
+		t.Errorf("Unexpected PID file: %+v", f)
+	}
+	b, err := ioutil.ReadFile(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := strings.TrimSpace(string(b)); s != strconv.Itoa(os.Getpid()) {
+		t.Errorf("PID file should contain current PID but got %q", s)
+	}
+
+	_, err = AcquirePidFile(p)
+	var perr *PidFileError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrLocked) {
+		t.Fatalf("PidFileError should be returned but got %v", err)
+	}
+	if perr.Pid != os.Getpid() || perr.Path != p {
+		t.Errorf("Unexpected error: %+v", perr)
+	}
+
+	if err := f.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(p.String()); !os.IsNotExist(err) {
+		t.Errorf("PID file should be removed on release: %v", err)
+	}
+	if err := f.Release(); err != nil {
+		t.Errorf("Releasing twice should not fail: %v", err)
+	}
+}
+
+func TestAcquirePidFileRunningOwner(t *testing.T) {
+	if runtime.GOOS == "js" {
+		t.Skip("Processes are not visible on js")
+	}
+	root := makeTree(t, map[string]string{})
+	p := root.Join("app.pid")
+	ppid := os.Getppid()
+	if err := ioutil.WriteFile(p.String(), []byte(strconv.Itoa(ppid)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := AcquirePidFile(p)
+	var perr *PidFileError
+	if !errors.As(err, &perr) || perr.Pid != ppid {
+		t.Fatalf("PID file owned by parent process should not be acquired: %v", err)
+	}
+}
+
+func TestAcquirePidFileStale(t *testing.T) {
+	if runtime.GOOS == "js" {
+		t.Skip("Processes are not visible on js")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	cmd := exec.Command(exe, "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	dead := cmd.Process.Pid
+
+	root := makeTree(t, map[string]string{})
+	p := root.Join("app.pid")
+	if err := ioutil.WriteFile(p.String(), []byte(strconv.Itoa(dead)+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := AcquirePidFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Release()
+	if f.StalePid != dead {
+		t.Errorf("Stale PID %d should be reported but got %d", dead, f.StalePid)
+	}
+}
+
+func TestPidFileReleaseReplaced(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+	p := root.Join("app.pid")
+
+	f, err := AcquirePidFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(p.String(), []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(p.String()); err != nil {
+		t.Errorf("PID file owned by other process should not be removed: %v", err)
+	}
+}
+
+func TestAcquirePidFileInvalid(t *testing.T) {
+	root := makeTree(t, map[string]string{"app.pid": "not a pid"})
+	if _, err := AcquirePidFile(root.Join("app.pid")); err == nil || !strings.Contains(err.Error(), "invalid PID") {
+		t.Errorf("Invalid PID error should be returned but got %v", err)
+	}
+	if _, err := AcquirePidFile(root.Join("missing", "app.pid")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
+
+func TestRemoveStalePidFileKeepsFreshFile(t *testing.T) {
+	root := makeTree(t, map[string]string{"app.pid": "12345\n"})
+	p := root.Join("app.pid")
+
+	// Another process replaced the stale file just before it is removed
+	if err := removeStalePidFile(p.String(), 54321); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(p.String())
+	if err != nil {
+		t.Fatalf("Fresh PID file should not be removed: %v", err)
+	}
+	if string(b) != "12345\n" {
+		t.Errorf("Fresh PID file should not be modified: %q", b)
+	}
+
+	if err := removeStalePidFile(p.String(), 12345); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(p.String()); !os.IsNotExist(err) {
+		t.Errorf("Stale PID file should be removed: %v", err)
+	}
+	if es, err := ioutil.ReadDir(root.String()); err != nil || len(es) != 0 {
+		t.Errorf("No temporary file should remain: %v %v", es, err)
+	}
+}
+
+func TestAcquirePidFileViaFS(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+	p := root.Join("app.pid")
+
+	r := &recordingFS{FS: OSFS}
+	prev := SetFS(r)
+	defer SetFS(prev)
+
+	f, err := AcquirePidFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.opened) != 1 || r.opened[0] != p.String() {
+		t.Errorf("PID file should be read via FS on release: %v", r.opened)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !windows && !plan9 && !js
+// +build !windows,!plan9,!js
+
+package abspath
+
+import (
+	"syscall"
+)
+
+// processAlive returns whether the process is running by sending signal 0 to it.
+func processAlive(pid int) (bool, error) {
+	err := syscall.Kill(pid, 0)
+	switch err {
+	case nil, syscall.EPERM:
+		return true, nil
+	case syscall.ESRCH:
+		return false, nil
+	default:
+		return false, err
+	}
+}
@@ -0,0 +1,29 @@
+package abspath
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is an exit code of a running process returned from GetExitCodeProcess().
+const stillActive = 259
+
+// processAlive returns whether the process is running by opening its handle.
+func processAlive(pid int) (bool, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	switch err {
+	case nil:
+	case windows.ERROR_INVALID_PARAMETER:
+		return false, nil
+	case windows.ERROR_ACCESS_DENIED:
+		return true, nil
+	default:
+		return false, err
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(h, &code); err != nil {
+		return false, err
+	}
+	return code == stillActive, nil
+}
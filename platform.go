@@ -0,0 +1,61 @@
+package abspath
+
+import (
+	"fmt"
+
+	"github.com/rhysd/abspath/lexical"
+)
+
+// Platform is a set of rules of paths on some platform.  Methods of AbsPath follow the rules of the platform where the
+// program is running.  Platform and PlatformPath are useful to manipulate paths for other platforms, for example,
+// Windows paths on a Linux build server.  The rules are implemented in lexical package.
+type Platform = lexical.Platform
+
+const (
+	// Unix is the platform using '/' as a path separator without volume names.
+	Unix = lexical.Unix
+	// Windows is the platform using '\' (and '/') as path separators with volume names.
+	Windows = lexical.Windows
+)
+
+// HostPlatform is the platform where the program is running.
+var HostPlatform = lexical.HostPlatform
+
+// PlatformPath is an absolute path on a specific platform.  Unlike AbsPath, it follows the rules of the platform
+// instead of the platform where the program is running.  All methods are lexical and never access the filesystem.
+type PlatformPath = lexical.Path
+
+// NewPlatformPath creates a PlatformPath instance from a string.  The string must represent an absolute path on the
+// platform.  Otherwise it returns an error.  The path is cleaned following the rules of the platform.
+//
+// Example:
+//	// Even on Linux
+//	p, err := abspath.NewPlatformPath(abspath.Windows, `C:/Users/foo/../bar`)
+//	p.String()                 // => `C:\Users\bar`
+//	p.Join("AppData").String() // => `C:\Users\bar\AppData`
+func NewPlatformPath(p Platform, s string) (PlatformPath, error) {
+	l, err := lexical.NewForPlatform(p, s)
+	if err != nil {
+		return PlatformPath{}, &NotAbsolutePathError{s}
+	}
+	return l, nil
+}
+
+// PlatformPath converts the path into PlatformPath for the host platform.  When the path is a zero value, it returns a
+// zero value of PlatformPath.
+func (a AbsPath) PlatformPath() PlatformPath {
+	l, err := lexical.New(a.underlying)
+	if err != nil {
+		return PlatformPath{}
+	}
+	return l
+}
+
+// FromPlatformPath converts PlatformPath into AbsPath.  It returns an error when the platform of the path is not the
+// host platform.
+func FromPlatformPath(p PlatformPath) (AbsPath, error) {
+	if p.Platform() != HostPlatform {
+		return AbsPath{""}, fmt.Errorf("cannot convert %s path '%s' to path on %s", p.Platform(), p, HostPlatform)
+	}
+	return AbsPath{p.String()}, nil
+}
@@ -0,0 +1,57 @@
+package abspath
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPlatformHostCompatibility(t *testing.T) {
+	for _, s := range []string{"/foo//bar/../baz/", "/", "/foo/./bar", "/../foo"} {
+		s = filepath.FromSlash(fixAbsPath(s))
+		if want, have := filepath.Clean(s), HostPlatform.Clean(s); want != have {
+			t.Errorf("Clean(%q) should be compatible with filepath.Clean: want %q but have %q", s, want, have)
+		}
+		if want, have := filepath.IsAbs(s), HostPlatform.IsAbs(s); want != have {
+			t.Errorf("IsAbs(%q) should be compatible with filepath.IsAbs: want %v but have %v", s, want, have)
+		}
+	}
+
+	a, _ := FromSlash(fixAbsPath("/foo/bar.txt"))
+	p := a.PlatformPath()
+	if p.Platform() != HostPlatform || p.String() != a.String() {
+		t.Errorf("Unexpected conversion from %s: %s (%s)", a, p, p.Platform())
+	}
+	b, err := FromPlatformPath(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b != a {
+		t.Errorf("Wanted %s but have %s", a, b)
+	}
+	if p.Base() != a.Base().String() || p.Ext() != a.Ext() || p.Dir().String() != a.Dir().String() {
+		t.Errorf("Base, Ext or Dir is not compatible with AbsPath: %q %q %q", p.Base(), p.Ext(), p.Dir())
+	}
+}
+
+func TestPlatformPathConversion(t *testing.T) {
+	p, err := NewPlatformPath(Windows, `C:\foo\bar`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.String() != `C:\foo\bar` {
+		t.Errorf("Unexpected path %q", p)
+	}
+	if _, err := FromPlatformPath(p); HostPlatform != Windows && err == nil {
+		t.Errorf("Windows path should not be converted to AbsPath on %s", HostPlatform)
+	}
+
+	if _, err := NewPlatformPath(Windows, "/foo"); err == nil {
+		t.Errorf("Error should occur for non-absolute Windows path")
+	} else if _, ok := err.(*NotAbsolutePathError); !ok {
+		t.Errorf("Unexpected error %T: %v", err, err)
+	}
+
+	if z := (AbsPath{""}).PlatformPath(); z.String() != "" {
+		t.Errorf("Zero value should be converted to zero value but %q", z)
+	}
+}
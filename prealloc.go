@@ -0,0 +1,47 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+)
+
+// Preallocate reserves disk space for the file at the path so that its size becomes at least the given size.  Writing
+// to the reserved range later does not fail due to a full disk, and the allocation may be contiguous.  When the disk
+// does not have enough space, it fails immediately.  The file is never shrunk.  It is implemented with fallocate(2) on
+// Linux, fcntl(2) with F_PREALLOCATE on macOS and SetEndOfFile() on Windows.  On other platforms, it returns
+// UnsupportedError.  The file must exist.  Since it requires OS-specific features, it always accesses the OS
+// filesystem.
+//
+// Example:
+//	dst, _ := abspath.New("/path/to/download.iso")
+//	f, err := dst.Create(0644)
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer f.Close()
+//	if err := dst.Preallocate(contentLength); err != nil {
+//		panic(err) // e.g. No space left on device
+//	}
+func (a AbsPath) Preallocate(size int64) (err error) {
+	defer annotate(&err, "preallocate", a)
+	return audit("preallocate", fmt.Sprintf("size=%d", size), func() error {
+		return a.preallocate(size)
+	}, a.underlying)
+}
+
+func (a AbsPath) preallocate(size int64) error {
+	f, err := os.OpenFile(a.underlying, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	s, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := preallocate(f, s.Size(), size); err != nil {
+		f.Close()
+		return &os.PathError{Op: "preallocate", Path: a.underlying, Err: err}
+	}
+	return f.Close()
+}
@@ -0,0 +1,28 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func preallocate(f *os.File, cur, size int64) error {
+	if size <= cur {
+		return nil
+	}
+	fd := f.Fd()
+	st := &unix.Fstore_t{
+		Flags:   unix.F_ALLOCATECONTIG,
+		Posmode: unix.F_PEOFPOSMODE,
+		Length:  size - cur,
+	}
+	if err := unix.FcntlFstore(fd, unix.F_PREALLOCATE, st); err != nil {
+		// Contiguous space is not available. Retry allowing fragmentation
+		st.Flags = unix.F_ALLOCATEALL
+		if err := unix.FcntlFstore(fd, unix.F_PREALLOCATE, st); err != nil {
+			return err
+		}
+	}
+	// F_PREALLOCATE does not change the size of the file
+	return unix.Ftruncate(int(fd), size)
+}
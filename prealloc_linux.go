@@ -0,0 +1,14 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func preallocate(f *os.File, cur, size int64) error {
+	if size <= cur {
+		return nil
+	}
+	return unix.Fallocate(int(f.Fd()), 0, 0, size)
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package abspath
+
+import (
+	"os"
+)
+
+func preallocate(f *os.File, cur, size int64) error {
+	return &UnsupportedError{"Preallocate", nil}
+}
@@ -0,0 +1,56 @@
+package abspath
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestPreallocate(t *testing.T) {
+	root := makeTree(t, map[string]string{"file": "hello"})
+	p := root.Join("file")
+	const size = 1 << 20
+
+	err := p.Preallocate(size)
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && !isWindows {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Unsupported error should be returned but got %v", err)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != size {
+		t.Fatalf("Size should be %d but got %d", size, len(b))
+	}
+	if !bytes.HasPrefix(b, []byte("hello")) || !bytes.Equal(b[5:], make([]byte, size-5)) {
+		t.Error("Content should be kept and extended with zeros")
+	}
+	if sparse, err := p.IsSparse(); err == nil && sparse {
+		t.Error("Preallocated file should not be sparse")
+	}
+
+	if err := p.Preallocate(10); err != nil {
+		t.Fatal(err)
+	}
+	s, err := os.Stat(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Size() != size {
+		t.Errorf("File should not be shrunk but size is %d", s.Size())
+	}
+
+	if err := root.Join("missing").Preallocate(size); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package abspath
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+func preallocate(f *os.File, cur, size int64) error {
+	if size <= cur {
+		return nil
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		return err
+	}
+	return windows.SetEndOfFile(windows.Handle(f.Fd()))
+}
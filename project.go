@@ -0,0 +1,146 @@
+package abspath
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+)
+
+// ErrProjectRootNotFound is an error returned from ProjectRoot() when no detector matches any directory.
+var ErrProjectRootNotFound = errors.New("project root was not found")
+
+// RootDetector detects whether a directory is a root of project.  It is used by ProjectRoot() function.
+type RootDetector interface {
+	// Name returns a name of the detector like "git".
+	Name() string
+	// Detect returns whether the directory is a root of project.
+	Detect(dir AbsPath) (bool, error)
+}
+
+// ProjectRootMatch is a result of ProjectRoot() function.
+type ProjectRootMatch struct {
+	// Root is the root directory of the project.
+	Root AbsPath
+	// Detector is the detector which matched the root directory.
+	Detector RootDetector
+}
+
+type markerDetector struct {
+	name    string
+	markers []string
+}
+
+func (d *markerDetector) Name() string {
+	return d.name
+}
+
+func (d *markerDetector) Detect(dir AbsPath) (bool, error) {
+	for _, m := range d.markers {
+		_, err := fsys().Stat(dir.Join(m).underlying)
+		if err == nil {
+			return true, nil
+		}
+		if !os.IsNotExist(err) && !isNotDir(err) {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// MarkerDetector returns a RootDetector which detects a directory containing one of the given marker files or
+// directories as a project root.  The name is returned from Name() method of the detector.
+//
+// Example:
+//	d := abspath.MarkerDetector("npm", "package.json")
+func MarkerDetector(name string, markers ...string) RootDetector {
+	return &markerDetector{name, markers}
+}
+
+type gitDetector struct{}
+
+func (d gitDetector) Name() string {
+	return "git"
+}
+
+func (d gitDetector) Detect(dir AbsPath) (bool, error) {
+	p := dir.Join(".git")
+	s, err := fsys().Stat(p.underlying)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if s.IsDir() {
+		return true, nil
+	}
+
+	// .git file is used for worktrees and submodules.  It contains a line like 'gitdir: /path/to/.git/worktrees/foo'
+	f, err := fsys().Open(p.underlying)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	l, err := r.ReadString('\n')
+	if err != nil && l == "" {
+		return false, nil
+	}
+	return strings.HasPrefix(l, "gitdir: "), nil
+}
+
+var (
+	// GoModuleDetector detects a directory containing go.mod as a project root.
+	GoModuleDetector RootDetector = MarkerDetector("go", "go.mod")
+	// GitDetector detects a root of Git repository as a project root.  Worktrees and submodules whose .git is a file
+	// are also detected.
+	GitDetector RootDetector = gitDetector{}
+	// MercurialDetector detects a root of Mercurial repository as a project root.
+	MercurialDetector RootDetector = MarkerDetector("hg", ".hg")
+)
+
+// ProjectRoot finds a root directory of project by going up from start to the root directory.  At each directory,
+// detectors are tried in order and the first matched one is reported in the result.  When no detector is given,
+// GoModuleDetector, GitDetector and MercurialDetector are used.  When start is a file, it starts from the directory
+// containing the file.  When no root is found, it returns an error which satisfies errors.Is(err, ErrProjectRootNotFound).
+// The error is annotated with start as *PathError.
+//
+// Example:
+//	cwd, _ := abspath.Getwd()
+//	m, err := abspath.ProjectRoot(cwd, abspath.GitDetector, abspath.MarkerDetector("npm", "package.json"))
+//	if err != nil {
+//		panic(err)
+//	}
+//	fmt.Println(m.Root, "detected by", m.Detector.Name())
+func ProjectRoot(start AbsPath, detectors ...RootDetector) (_ *ProjectRootMatch, err error) {
+	defer annotate(&err, "find project root from", start)
+	if len(detectors) == 0 {
+		detectors = []RootDetector{GoModuleDetector, GitDetector, MercurialDetector}
+	}
+
+	s, err := fsys().Stat(start.underlying)
+	if err != nil {
+		return nil, err
+	}
+	dir := start
+	if !s.IsDir() {
+		dir = start.Dir()
+	}
+
+	for {
+		for _, d := range detectors {
+			ok, err := d.Detect(dir)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return &ProjectRootMatch{dir, d}, nil
+			}
+		}
+		if dir.IsRoot() {
+			return nil, ErrProjectRootNotFound
+		}
+		dir = dir.Dir()
+	}
+}
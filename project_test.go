@@ -0,0 +1,66 @@
+package abspath
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProjectRoot(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"repo/.git/HEAD":              "ref: refs/heads/master\n",
+		"repo/mod/go.mod":             "module example.com/mod\n",
+		"repo/mod/pkg/foo.go":         "package pkg\n",
+		"repo/docs/":                  "",
+		"repo/sub/.git":               "gitdir: ../.git/modules/sub\n",
+		"repo/sub/dir/":               "",
+		"repo/fake/.git":              "not a git file",
+		"repo/fake/dir/":              "",
+		"hg/.hg/store/":               "",
+		"hg/src/":                     "",
+		"npm/package.json":            "{}",
+		"npm/node_modules/foo/index":  "",
+		"npm/node_modules/foo/x/y.js": "",
+	})
+
+	for _, c := range []struct {
+		start    []string
+		root     []string
+		detector string
+	}{
+		{[]string{"repo", "mod", "pkg"}, []string{"repo", "mod"}, "go"},
+		{[]string{"repo", "mod", "pkg", "foo.go"}, []string{"repo", "mod"}, "go"},
+		{[]string{"repo", "docs"}, []string{"repo"}, "git"},
+		{[]string{"repo"}, []string{"repo"}, "git"},
+		{[]string{"repo", "sub", "dir"}, []string{"repo", "sub"}, "git"},
+		{[]string{"repo", "fake", "dir"}, []string{"repo"}, "git"},
+		{[]string{"hg", "src"}, []string{"hg"}, "hg"},
+	} {
+		m, err := ProjectRoot(root.Join(c.start...))
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if e := root.Join(c.root...); m.Root != e {
+			t.Errorf("Expected root %s but actually %s", e, m.Root)
+		}
+		if m.Detector.Name() != c.detector {
+			t.Errorf("Expected detector %q but actually %q", c.detector, m.Detector.Name())
+		}
+	}
+
+	npm := MarkerDetector("npm", "package.json")
+	m, err := ProjectRoot(root.Join("npm", "node_modules", "foo", "x"), npm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := root.Join("npm"); m.Root != e || m.Detector != npm {
+		t.Errorf("Expected root %s detected by npm but actually %s detected by %s", e, m.Root, m.Detector.Name())
+	}
+
+	if _, err := ProjectRoot(root.Join("npm"), MarkerDetector("none", "no-such-marker-file")); !errors.Is(err, ErrProjectRootNotFound) {
+		t.Errorf("Expected ErrProjectRootNotFound but actually %v", err)
+	}
+	if _, err := ProjectRoot(root.Join("not-exist")); err == nil {
+		t.Errorf("Not existing start must cause an error")
+	}
+}
@@ -0,0 +1,19 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func punchHole(p string, off, length int64) error {
+	f, err := os.OpenFile(p, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if err := unix.Fallocate(int(f.Fd()), unix.FALLOC_FL_PUNCH_HOLE|unix.FALLOC_FL_KEEP_SIZE, off, length); err != nil {
+		f.Close()
+		return &os.PathError{Op: "fallocate", Path: p, Err: err}
+	}
+	return f.Close()
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package abspath
+
+func punchHole(p string, off, length int64) error {
+	return &UnsupportedError{"PunchHole", nil}
+}
@@ -0,0 +1,147 @@
+package abspath
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// tokenBucket is a token bucket which is refilled at the constant rate up to the burst size.  Tokens can be taken
+// beyond the bucket.  The debt is paid by waiting until the bucket is refilled.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, now time.Time) *tokenBucket {
+	// The bucket can hold tokens for one second so that short bursts are not throttled
+	return &tokenBucket{rate, rate, rate, now}
+}
+
+// take takes n tokens and returns how long the caller needs to wait until the tokens are available.
+func (b *tokenBucket) take(n float64, now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// sleepContext waits for the duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RateLimiter limits the rate of filesystem operations and the throughput of file contents.  It is set to RateLimiter
+// option of WalkOptions, CopyOptions, SyncOptions and HashOptions.  One RateLimiter can be shared by multiple
+// operations (even concurrently) to limit their total rate.  A nil RateLimiter does not limit anything.
+type RateLimiter struct {
+	mu    sync.Mutex
+	ops   *tokenBucket
+	bytes *tokenBucket
+}
+
+// NewRateLimiter creates a new RateLimiter which allows opsPerSec operations and bytesPerSec bytes per second.
+// Visiting one entry while walking or copying a directory tree is counted as one operation.  Bytes are counted when
+// file contents are read for copying or hashing.  When a rate is zero or negative, it is not limited.  Bursts up to one
+// second of the rates are allowed.
+//
+// Example:
+//	// Run the maintenance job without saturating the disk shared with the database
+//	l := abspath.NewRateLimiter(1000, 10*1024*1024)
+//	res, err := abspath.Sync(src, dst, &abspath.SyncOptions{RateLimiter: l})
+func NewRateLimiter(opsPerSec, bytesPerSec float64) *RateLimiter {
+	l := &RateLimiter{}
+	now := time.Now()
+	if opsPerSec > 0 {
+		l.ops = newTokenBucket(opsPerSec, now)
+	}
+	if bytesPerSec > 0 {
+		l.bytes = newTokenBucket(bytesPerSec, now)
+	}
+	return l
+}
+
+func (l *RateLimiter) wait(ctx context.Context, b *tokenBucket, n int64) error {
+	if b == nil || n <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	d := b.take(float64(n), time.Now())
+	l.mu.Unlock()
+	return sleepContext(ctx, d)
+}
+
+// waitOps waits until n operations are allowed.  It returns the error of the context when the context is done.
+func (l *RateLimiter) waitOps(ctx context.Context, n int64) error {
+	if l == nil {
+		return nil
+	}
+	return l.wait(ctx, l.ops, n)
+}
+
+// waitBytes waits until n bytes are allowed.  It returns the error of the context when the context is done.
+func (l *RateLimiter) waitBytes(ctx context.Context, n int64) error {
+	if l == nil {
+		return nil
+	}
+	return l.wait(ctx, l.bytes, n)
+}
+
+// walkFunc returns a walk function which waits for the rate of operations before visiting each entry.
+func (l *RateLimiter) walkFunc(ctx context.Context, fn filepath.WalkFunc) filepath.WalkFunc {
+	if l == nil || l.ops == nil {
+		return fn
+	}
+	return func(p string, info os.FileInfo, err error) error {
+		if err := l.waitOps(ctx, 1); err != nil {
+			return err
+		}
+		return fn(p, info, err)
+	}
+}
+
+// limitedReader is a reader which waits for the rate of bytes after reading each chunk.
+type limitedReader struct {
+	ctx context.Context
+	l   *RateLimiter
+	r   io.Reader
+}
+
+func (r limitedReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if werr := r.l.waitBytes(r.ctx, int64(n)); werr != nil && err == nil {
+		err = werr
+	}
+	return n, err
+}
+
+// reader returns a reader which limits the rate of reading from r.
+func (l *RateLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil || l.bytes == nil {
+		return r
+	}
+	return limitedReader{ctx, l, r}
+}
@@ -0,0 +1,140 @@
+package abspath
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	_ "crypto/sha256"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(10, now)
+
+	if d := b.take(10, now); d != 0 {
+		t.Errorf("Burst should not be throttled but waited %s", d)
+	}
+	if d := b.take(5, now); d != 500*time.Millisecond {
+		t.Errorf("5 tokens over the bucket should wait 500ms but waited %s", d)
+	}
+	now = now.Add(time.Second)
+	if d := b.take(5, now); d != 0 {
+		t.Errorf("Debt should be paid after waiting but waited %s", d)
+	}
+	// Tokens are not accumulated beyond the burst size
+	now = now.Add(time.Hour)
+	if d := b.take(20, now); d != time.Second {
+		t.Errorf("Tokens should be capped by the burst size but waited %s", d)
+	}
+}
+
+func TestRateLimiterWalk(t *testing.T) {
+	root := makeTree(t, map[string]string{"a": "", "b": "", "c": "", "d/e": ""})
+	l := NewRateLimiter(100, 0)
+	ctx := context.Background()
+	if err := l.waitOps(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	n := 0
+	err := root.WalkWithOptions(func(string, os.FileInfo, error) error {
+		n++
+		return nil
+	}, &WalkOptions{RateLimiter: l})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 6 {
+		t.Fatalf("All 6 entries should be visited but got %d", n)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Visiting 6 entries at 100 ops/sec should take 60ms but took %s", elapsed)
+	}
+}
+
+func TestRateLimiterBytes(t *testing.T) {
+	content := strings.Repeat("x", 1000)
+	root := makeTree(t, map[string]string{"src/a.txt": content})
+	src := root.Join("src")
+	ctx := context.Background()
+
+	l := NewRateLimiter(0, 10000)
+	if err := l.waitBytes(ctx, 10000); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if err := src.CopyDir(root.Join("dst"), &CopyOptions{RateLimiter: l}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Copying 1000 bytes at 10000 bytes/sec should take 100ms but took %s", elapsed)
+	}
+	b, err := ioutil.ReadFile(root.Join("dst", "a.txt").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != content {
+		t.Errorf("Copied content is broken: %q", b)
+	}
+
+	start = time.Now()
+	sum, err := src.Join("a.txt").HashWithOptions(crypto.SHA256, &HashOptions{RateLimiter: l})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Hashing 1000 bytes at 10000 bytes/sec should take 100ms but took %s", elapsed)
+	}
+	want, err := src.Join("a.txt").Hash(crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sum, want) {
+		t.Errorf("Digest should be %x but got %x", want, sum)
+	}
+}
+
+func TestRateLimiterSync(t *testing.T) {
+	root := makeTree(t, map[string]string{"src/a.txt": "hello", "src/d/b.txt": "world"})
+	l := NewRateLimiter(100, 0)
+	if err := l.waitOps(context.Background(), 100); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	res, err := Sync(root.Join("src"), root.Join("dst"), &SyncOptions{RateLimiter: l})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Copied) != 2 {
+		t.Errorf("2 files should be copied but got %v", res.Copied)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("Visiting 4 entries at 100 ops/sec should take 40ms but took %s", elapsed)
+	}
+}
+
+func TestRateLimiterCanceled(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	ctx := canceledContext()
+	if err := l.waitOps(ctx, 10); err != context.Canceled {
+		t.Errorf("Canceled error should be returned but got %v", err)
+	}
+	if err := l.waitBytes(ctx, 10); err != context.Canceled {
+		t.Errorf("Canceled error should be returned but got %v", err)
+	}
+
+	var nilLimiter *RateLimiter
+	if err := nilLimiter.waitOps(ctx, 10); err != nil {
+		t.Errorf("Nil limiter should not wait: %v", err)
+	}
+	if err := NewRateLimiter(0, 0).waitBytes(ctx, 10); err != nil {
+		t.Errorf("Unlimited limiter should not wait: %v", err)
+	}
+}
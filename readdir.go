@@ -0,0 +1,127 @@
+package abspath
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// dirBatchSize is a default number of entries read at once by DirIter.
+const dirBatchSize = 1024
+
+// readdirer is implemented by File which can read directory entries in batches such as *os.File.
+type readdirer interface {
+	Readdir(n int) ([]os.FileInfo, error)
+}
+
+// DirIter is an iterator to read entries of a directory in batches.  It is returned from ReadDirIter() method.  It
+// must be closed by Close() method after use.
+type DirIter struct {
+	dir  AbsPath
+	n    int
+	file File
+	rd   readdirer
+	rest []os.FileInfo
+}
+
+// ReadDirIter opens the directory and returns an iterator to read its entries in batches of n entries.  Unlike
+// ReadDir() of FS, entries are not loaded into memory at once so that a directory with extreme fan-out can be read in
+// constant memory.  Entries are returned in directory order, not sorted.  When n is zero or negative, a default batch
+// size is used.  When File of the current FS cannot read directory entries in batches, all entries are read on
+// opening.
+//
+// Example:
+//	it, err := dir.ReadDirIter(1000)
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer it.Close()
+//	for {
+//		entries, err := it.Next()
+//		for _, e := range entries {
+//			fmt.Println(e.Path)
+//		}
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			panic(err)
+//		}
+//	}
+func (a AbsPath) ReadDirIter(n int) (_ *DirIter, err error) {
+	defer annotate(&err, "read directory", a)
+	if n <= 0 {
+		n = dirBatchSize
+	}
+
+	f, err := fsys().Open(a.underlying)
+	if err != nil {
+		return nil, err
+	}
+	s, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !s.IsDir() {
+		f.Close()
+		return nil, fmt.Errorf("cannot read entries of '%s' since it is not a directory", a.underlying)
+	}
+
+	it := &DirIter{dir: a, n: n}
+	if rd, ok := f.(readdirer); ok {
+		it.file, it.rd = f, rd
+		return it, nil
+	}
+
+	f.Close()
+	es, err := fsys().ReadDir(a.underlying)
+	if err != nil {
+		return nil, err
+	}
+	it.rest = es
+	return it, nil
+}
+
+// Next returns the next batch of entries.  It returns at most n entries where n is the batch size passed to
+// ReadDirIter().  When all entries were read, it returns io.EOF.  When an error occurs, entries read before the error
+// are returned with the error.
+func (it *DirIter) Next() ([]Entry, error) {
+	var infos []os.FileInfo
+	var err error
+	if it.rd != nil {
+		infos, err = it.rd.Readdir(it.n)
+		if err == io.EOF {
+			err = nil
+		}
+		if len(infos) == 0 && err == nil {
+			return nil, io.EOF
+		}
+	} else {
+		if len(it.rest) == 0 {
+			return nil, io.EOF
+		}
+		l := it.n
+		if l > len(it.rest) {
+			l = len(it.rest)
+		}
+		infos, it.rest = it.rest[:l], it.rest[l:]
+	}
+
+	entries := make([]Entry, 0, len(infos))
+	for _, i := range infos {
+		entries = append(entries, Entry{it.dir.Join(i.Name()), i})
+	}
+	return entries, err
+}
+
+// Close closes the directory.  It must be called after reading entries.
+func (it *DirIter) Close() error {
+	it.rest = nil
+	if it.file == nil {
+		return nil
+	}
+	f := it.file
+	it.file, it.rd = nil, nil
+	return f.Close()
+}
@@ -0,0 +1,110 @@
+package abspath
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+)
+
+func readAllIter(t *testing.T, it *DirIter, n int) []AbsPath {
+	t.Helper()
+	ps := []AbsPath{}
+	for {
+		es, err := it.Next()
+		if len(es) > n {
+			t.Errorf("Batch should have at most %d entries but has %d", n, len(es))
+		}
+		for _, e := range es {
+			if e.Info.Name() != e.Path.Base().String() {
+				t.Errorf("Info %q does not match to path %s", e.Info.Name(), e.Path)
+			}
+			ps = append(ps, e.Path)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].String() < ps[j].String() })
+	return ps
+}
+
+func TestReadDirIter(t *testing.T) {
+	files := map[string]string{"sub/": ""}
+	for i := 0; i < 25; i++ {
+		files[fmt.Sprintf("f%02d", i)] = ""
+	}
+	root := makeTree(t, files)
+
+	want := []AbsPath{}
+	for i := 0; i < 25; i++ {
+		want = append(want, root.Join(fmt.Sprintf("f%02d", i)))
+	}
+	want = append(want, root.Join("sub"))
+
+	for _, n := range []int{1, 7, 26, 100, 0} {
+		it, err := root.ReadDirIter(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		max := n
+		if max <= 0 {
+			max = dirBatchSize
+		}
+		assertPathsEqual(t, want, readAllIter(t, it, max))
+		if _, err := it.Next(); err != io.EOF {
+			t.Errorf("EOF should be returned after reading all entries but %v", err)
+		}
+		if err := it.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	it, err := root.Join("sub").ReadDirIter(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	if es, err := it.Next(); err != io.EOF || len(es) != 0 {
+		t.Errorf("Empty directory should return EOF but %v %v", es, err)
+	}
+}
+
+func TestReadDirIterError(t *testing.T) {
+	root := makeTree(t, map[string]string{"file": ""})
+	if _, err := root.Join("file").ReadDirIter(10); err == nil {
+		t.Errorf("Error should occur for file")
+	}
+	if _, err := root.Join("missing").ReadDirIter(10); err == nil {
+		t.Errorf("Error should occur for missing directory")
+	}
+}
+
+// noReaddirFS hides Readdir() method of opened files
+type noReaddirFS struct {
+	FS
+}
+
+func (f noReaddirFS) Open(name string) (File, error) {
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return struct{ File }{file}, nil
+}
+
+func TestReadDirIterFallback(t *testing.T) {
+	root := makeTree(t, map[string]string{"a": "", "b": "", "c": ""})
+	prev := SetFS(noReaddirFS{OSFS})
+	defer SetFS(prev)
+
+	it, err := root.ReadDirIter(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	assertPathsEqual(t, []AbsPath{root.Join("a"), root.Join("b"), root.Join("c")}, readAllIter(t, it, 2))
+}
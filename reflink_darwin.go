@@ -0,0 +1,14 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func reflink(src, dst string, perm os.FileMode) error {
+	if err := unix.Clonefile(src, dst, unix.CLONE_NOFOLLOW); err != nil {
+		return &os.LinkError{Op: "clonefile", Old: src, New: dst, Err: err}
+	}
+	return os.Chmod(dst, perm)
+}
@@ -0,0 +1,26 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func reflink(src, dst string, perm os.FileMode) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if err := unix.IoctlFileClone(int(w.Fd()), int(r.Fd())); err != nil {
+		w.Close()
+		os.Remove(dst)
+		return &os.LinkError{Op: "ioctl_ficlone", Old: src, New: dst, Err: err}
+	}
+	return w.Close()
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package abspath
+
+import (
+	"os"
+)
+
+func reflink(src, dst string, perm os.FileMode) error {
+	return &UnsupportedError{"Reflink", nil}
+}
@@ -0,0 +1,168 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxSymlinks is the maximum number of symbolic links followed while resolving a path.
+const maxSymlinks = 255
+
+// ResolveOptions is a set of options for Resolve() method.
+type ResolveOptions struct {
+	// MustExist makes Resolve() return an error when some component does not exist, like `realpath -e`.
+	MustExist bool
+	// MaxLinks is the maximum number of symbolic links followed while resolving.  When it is exceeded,
+	// *SymlinkDepthError is returned.  Zero means the default value 255.
+	MaxLinks int
+}
+
+// SymlinkLoopError is an error returned when symbolic links form a cycle while resolving a path.
+type SymlinkLoopError struct {
+	// Path is the path being resolved.
+	Path AbsPath
+	// Members is a list of symbolic links forming the cycle in order of visiting.
+	Members []AbsPath
+}
+
+func (err *SymlinkLoopError) Error() string {
+	ss := make([]string, 0, len(err.Members))
+	for _, m := range err.Members {
+		ss = append(ss, m.underlying)
+	}
+	return fmt.Sprintf("Symbolic link loop while resolving '%s': %s", err.Path.underlying, strings.Join(ss, " -> "))
+}
+
+// SymlinkDepthError is an error returned when the number of symbolic links followed while resolving a path exceeds the
+// limit.
+type SymlinkDepthError struct {
+	// Path is the path being resolved.
+	Path AbsPath
+	// Max is the maximum number of symbolic links.
+	Max int
+}
+
+func (err *SymlinkDepthError) Error() string {
+	return fmt.Sprintf("Too many symbolic links (more than %d) while resolving '%s'", err.Max, err.Path.underlying)
+}
+
+// splitComponents splits a path into its components.  Empty components are removed.
+func splitComponents(p string) []string {
+	ss := strings.Split(p, string(os.PathSeparator))
+	cs := make([]string, 0, len(ss))
+	for _, s := range ss {
+		if s != "" {
+			cs = append(cs, s)
+		}
+	}
+	return cs
+}
+
+// Resolve resolves symbolic links in the path as much as possible.  Unlike EvalSymlinks(), the path does not need to
+// exist.  Components are resolved from the root and once a component does not exist, it and the rest of components are
+// joined lexically.  This is the same as `realpath -m`.  It is useful to resolve the destination path before creating
+// it.  opts can be nil.
+//
+// Example:
+//	// When /tmp is a symbolic link to /private/tmp
+//	a, _ := abspath.New("/tmp/not-created-yet/file")
+//	r, err := a.Resolve(nil) // => "/private/tmp/not-created-yet/file"
+func (a AbsPath) Resolve(opts *ResolveOptions) (_ AbsPath, err error) {
+	defer annotate(&err, "resolve", a)
+	if opts == nil {
+		opts = &ResolveOptions{}
+	}
+
+	max := opts.MaxLinks
+	if max <= 0 {
+		max = maxSymlinks
+	}
+
+	v, rest := a.SplitAll()
+	cur := Volume{v}.Root()
+	missing := false
+
+	// States of resolution when visiting each symbolic link to detect a cycle.  When the same link is visited with the
+	// same remaining components twice, the resolution never terminates.
+	var visited []AbsPath
+	seen := map[string]int{}
+
+	for len(rest) > 0 {
+		c := rest[0]
+		rest = rest[1:]
+
+		switch c {
+		case ".":
+			continue
+		case "..":
+			cur = cur.Dir()
+			continue
+		}
+
+		next := cur.Join(c)
+		if missing {
+			cur = next
+			continue
+		}
+
+		s, err := fsys().Lstat(next.underlying)
+		if err != nil {
+			if !os.IsNotExist(err) && !isNotDir(err) {
+				return AbsPath{""}, err
+			}
+			if opts.MustExist {
+				return AbsPath{""}, err
+			}
+			missing = true
+			cur = next
+			continue
+		}
+
+		if s.Mode()&os.ModeSymlink == 0 {
+			cur = next
+			continue
+		}
+
+		state := next.underlying + "\x00" + strings.Join(rest, string(os.PathSeparator))
+		if i, ok := seen[state]; ok {
+			return AbsPath{""}, &SymlinkLoopError{a, visited[i:]}
+		}
+		seen[state] = len(visited)
+		visited = append(visited, next)
+		if len(visited) > max {
+			return AbsPath{""}, &SymlinkDepthError{a, max}
+		}
+
+		t, err := fsys().Readlink(next.underlying)
+		if err != nil {
+			return AbsPath{""}, err
+		}
+		if filepath.IsAbs(t) {
+			cur = Volume{filepath.VolumeName(t)}.Root()
+			t = t[len(filepath.VolumeName(t)):]
+		}
+		rest = append(splitComponents(t), rest...)
+	}
+
+	return cur, nil
+}
+
+// EvalSymlinksDepth is the same as EvalSymlinks() but the number of symbolic links followed is limited by max.  When
+// symbolic links form a cycle, the error wraps *SymlinkLoopError including the members of the cycle.  When the limit
+// is exceeded, the error wraps *SymlinkDepthError.  It is useful to bound work on untrusted directory trees.
+//
+// Example:
+//	r, err := a.EvalSymlinksDepth(8)
+//	var loop *abspath.SymlinkLoopError
+//	if errors.As(err, &loop) {
+//		fmt.Println("Loop:", loop.Members)
+//	}
+func (a AbsPath) EvalSymlinksDepth(max int) (_ AbsPath, err error) {
+	defer annotate(&err, "evaluate symbolic links of", a)
+	if max <= 0 {
+		return AbsPath{""}, &SymlinkDepthError{a, max}
+	}
+	return a.Resolve(&ResolveOptions{MustExist: true, MaxLinks: max})
+}
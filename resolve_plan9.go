@@ -0,0 +1,7 @@
+package abspath
+
+// isNotDir returns whether the error was caused by a non-directory file in the middle of a path.  Plan 9 reports the
+// case as "not found" error so it is covered by os.IsNotExist().
+func isNotDir(err error) bool {
+	return false
+}
@@ -0,0 +1,141 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	if isWindows {
+		t.Skip("Symlink requires privilege on Windows")
+	}
+
+	root := makeTree(t, map[string]string{"real/dir/file": "", "file": ""})
+	root, err := root.EvalSymlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []struct{ link, target string }{
+		{"abs-link", root.Join("real").String()},
+		{"rel-link", "real/dir"},
+		{"real/up-link", "../real"},
+		{"chain", "abs-link"},
+		{"dangling", "not-exist/foo"},
+	} {
+		if err := os.Symlink(l.target, root.Join(l.link).String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, c := range []struct {
+		input    []string
+		expected []string
+	}{
+		{[]string{"real", "dir", "file"}, []string{"real", "dir", "file"}},
+		{[]string{"abs-link", "dir", "file"}, []string{"real", "dir", "file"}},
+		{[]string{"rel-link", "file"}, []string{"real", "dir", "file"}},
+		{[]string{"chain", "up-link", "dir"}, []string{"real", "dir"}},
+		{[]string{"abs-link", "new", "file"}, []string{"real", "new", "file"}},
+		{[]string{"rel-link", "new"}, []string{"real", "dir", "new"}},
+		{[]string{"dangling", "bar"}, []string{"not-exist", "foo", "bar"}},
+		{[]string{"file", "foo"}, []string{"file", "foo"}},
+	} {
+		r, err := root.Join(c.input...).Resolve(nil)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		e := root.Join(c.expected...)
+		if r != e {
+			t.Errorf("Expected %s but actually %s", e, r)
+		}
+	}
+
+	if _, err := root.Join("abs-link", "new").Resolve(&ResolveOptions{MustExist: true}); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not exist error but actually %v", err)
+	}
+	r, err := root.Join("chain", "dir").Resolve(&ResolveOptions{MustExist: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := root.Join("real", "dir"); r != e {
+		t.Errorf("Expected %s but actually %s", e, r)
+	}
+
+	if err := os.Symlink("loop2", root.Join("loop1").String()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("loop1", root.Join("loop2").String()); err != nil {
+		t.Fatal(err)
+	}
+	_, err = root.Join("loop1").Resolve(nil)
+	var loop *SymlinkLoopError
+	if !errors.As(err, &loop) {
+		t.Fatalf("Expected SymlinkLoopError but actually %v", err)
+	}
+	if len(loop.Members) != 2 || loop.Members[0] != root.Join("loop1") || loop.Members[1] != root.Join("loop2") {
+		t.Errorf("Unexpected members of loop: %v", loop.Members)
+	}
+	if loop.Path != root.Join("loop1") {
+		t.Errorf("Unexpected path %s", loop.Path)
+	}
+}
+
+func TestEvalSymlinksDepth(t *testing.T) {
+	if isWindows {
+		t.Skip("Symlink requires privilege on Windows")
+	}
+
+	root := makeTree(t, map[string]string{"file": ""})
+	root, err := root.EvalSymlinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range []struct{ link, target string }{
+		{"link1", "file"},
+		{"link2", "link1"},
+		{"link3", "link2"},
+		{"self", "self"},
+		{"dir", "."},
+	} {
+		if err := os.Symlink(l.target, root.Join(l.link).String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := root.Join("link3").EvalSymlinksDepth(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := root.Join("file"); r != e {
+		t.Errorf("Expected %s but actually %s", e, r)
+	}
+
+	// Visiting the same link multiple times is not a loop
+	r, err = root.Join("dir", "dir", "dir", "file").EvalSymlinksDepth(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := root.Join("file"); r != e {
+		t.Errorf("Expected %s but actually %s", e, r)
+	}
+
+	for _, max := range []int{0, 2} {
+		_, err = root.Join("link3").EvalSymlinksDepth(max)
+		var d *SymlinkDepthError
+		if !errors.As(err, &d) || d.Max != max {
+			t.Errorf("Expected SymlinkDepthError with max %d but actually %v", max, err)
+		}
+	}
+
+	_, err = root.Join("self").EvalSymlinksDepth(10)
+	var loop *SymlinkLoopError
+	if !errors.As(err, &loop) || len(loop.Members) != 1 {
+		t.Errorf("Expected SymlinkLoopError with one member but actually %v", err)
+	}
+
+	if _, err := root.Join("link3", "not-exist").EvalSymlinksDepth(10); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package abspath
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isNotDir returns whether the error was caused by a non-directory file in the middle of a path.
+func isNotDir(err error) bool {
+	return errors.Is(err, syscall.ENOTDIR)
+}
@@ -0,0 +1,12 @@
+package abspath
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isNotDir returns whether the error was caused by a non-directory file in the middle of a path.
+func isNotDir(err error) bool {
+	return errors.Is(err, windows.ERROR_PATH_NOT_FOUND) || errors.Is(err, windows.ERROR_DIRECTORY)
+}
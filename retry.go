@@ -0,0 +1,84 @@
+package abspath
+
+import (
+	"time"
+)
+
+// RetryOptions is a set of options for RenameRetry(), RemoveRetry() and RemoveAllRetry() methods.  Zero values mean
+// default values.
+type RetryOptions struct {
+	// MaxAttempts is the maximum number of attempts including the first one.  Default value is 8.
+	MaxAttempts int
+	// InitialDelay is a delay before the first retry.  The delay is doubled on every retry.  Default value is 10ms.
+	InitialDelay time.Duration
+	// MaxDelay is the upper limit of the delay between retries.  Default value is 500ms.
+	MaxDelay time.Duration
+}
+
+func (o *RetryOptions) withDefaults() RetryOptions {
+	r := RetryOptions{MaxAttempts: 8, InitialDelay: 10 * time.Millisecond, MaxDelay: 500 * time.Millisecond}
+	if o == nil {
+		return r
+	}
+	if o.MaxAttempts > 0 {
+		r.MaxAttempts = o.MaxAttempts
+	}
+	if o.InitialDelay > 0 {
+		r.InitialDelay = o.InitialDelay
+	}
+	if o.MaxDelay > 0 {
+		r.MaxDelay = o.MaxDelay
+	}
+	return r
+}
+
+// retry calls f until it succeeds, it returns an error which is not temporary or the number of attempts reaches the limit.
+func retry(opts *RetryOptions, temporary func(error) bool, f func() error) error {
+	o := opts.withDefaults()
+	d := o.InitialDelay
+	for i := 1; ; i++ {
+		err := f()
+		if err == nil || i >= o.MaxAttempts || !temporary(err) {
+			return err
+		}
+		time.Sleep(d)
+		d *= 2
+		if d > o.MaxDelay {
+			d = o.MaxDelay
+		}
+	}
+}
+
+// RenameRetry renames the path to the other path as os.Rename() does.  On Windows, renaming a file sometimes fails
+// with ERROR_SHARING_VIOLATION or ERROR_ACCESS_DENIED because other processes such as anti-virus software or search
+// indexer open the file for a moment.  This method retries renaming with exponential backoff on such errors.  On other
+// platforms, no error is retried.  opts can be nil.
+//
+// Example:
+//	tmp, _ := abspath.New(`C:\path\to\file.tmp`)
+//	dst, _ := abspath.New(`C:\path\to\file`)
+//	err := tmp.RenameRetry(dst, nil)
+func (a AbsPath) RenameRetry(to AbsPath, opts *RetryOptions) (err error) {
+	defer annotate(&err, "rename", a, to)
+	return retry(opts, isTemporaryFileError, func() error {
+		return fsys().Rename(a.underlying, to.underlying)
+	})
+}
+
+// RemoveRetry removes the file or empty directory at the path as os.Remove() does.  It retries removing on temporary
+// errors on Windows as RenameRetry() does.  opts can be nil.
+func (a AbsPath) RemoveRetry(opts *RetryOptions) (err error) {
+	defer annotate(&err, "remove", a)
+	return retry(opts, isTemporaryFileError, func() error {
+		return fsys().Remove(a.underlying)
+	})
+}
+
+// RemoveAllRetry removes the path and all its children as os.RemoveAll() does.  It retries removing on temporary
+// errors on Windows as RenameRetry() does.  opts can be nil.
+func (a AbsPath) RemoveAllRetry(opts *RetryOptions) (err error) {
+	defer annotate(&err, "remove", a)
+	return retry(opts, isTemporaryFileError, func() error {
+		return fsys().RemoveAll(a.underlying)
+	})
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+// +build !windows
+
+package abspath
+
+// isTemporaryFileError returns whether the error is caused by other processes opening the file for a moment.  It can
+// happen only on Windows.
+func isTemporaryFileError(err error) bool {
+	return false
+}
@@ -0,0 +1,85 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	temporary := errors.New("temporary")
+	permanent := errors.New("permanent")
+	isTemp := func(err error) bool { return err == temporary }
+	opts := &RetryOptions{MaxAttempts: 4, InitialDelay: time.Millisecond}
+
+	for _, c := range []struct {
+		what     string
+		errs     []error
+		expected error
+		calls    int
+	}{
+		{"success", []error{nil}, nil, 1},
+		{"success after retry", []error{temporary, temporary, nil}, nil, 3},
+		{"permanent error", []error{temporary, permanent, nil}, permanent, 2},
+		{"too many attempts", []error{temporary, temporary, temporary, temporary, nil}, temporary, 4},
+	} {
+		calls := 0
+		err := retry(opts, isTemp, func() error {
+			err := c.errs[calls]
+			calls++
+			return err
+		})
+		if err != c.expected {
+			t.Errorf("Expected error %v for %s but actually %v", c.expected, c.what, err)
+		}
+		if calls != c.calls {
+			t.Errorf("Expected %d calls for %s but actually %d", c.calls, c.what, calls)
+		}
+	}
+}
+
+func TestRetryOptionsDefaults(t *testing.T) {
+	var nilOpts *RetryOptions
+	d := nilOpts.withDefaults()
+	if d.MaxAttempts != 8 || d.InitialDelay != 10*time.Millisecond || d.MaxDelay != 500*time.Millisecond {
+		t.Errorf("Unexpected default values: %+v", d)
+	}
+
+	o := (&RetryOptions{MaxAttempts: 3}).withDefaults()
+	if o.MaxAttempts != 3 || o.InitialDelay != d.InitialDelay || o.MaxDelay != d.MaxDelay {
+		t.Errorf("Unexpected values: %+v", o)
+	}
+}
+
+func TestRenameRemoveRetry(t *testing.T) {
+	root := makeTree(t, map[string]string{"a": "a", "dir/b": "b"})
+
+	if err := root.Join("a").RenameRetry(root.Join("c"), nil); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("c"), "a")
+
+	start := time.Now()
+	if err := root.Join("not-exist").RenameRetry(root.Join("d"), nil); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Not existing path should not be retried")
+	}
+
+	if err := root.Join("c").RemoveRetry(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := root.Join("dir").RemoveRetry(nil); err == nil {
+		t.Errorf("Non-empty directory must cause an error")
+	}
+	if err := root.Join("dir").RemoveAllRetry(nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"c", "dir"} {
+		if _, err := os.Stat(root.Join(p).String()); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed: %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package abspath
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// isTemporaryFileError returns whether the error is caused by other processes opening the file for a moment.
+func isTemporaryFileError(err error) bool {
+	return errors.Is(err, windows.ERROR_SHARING_VIOLATION) ||
+		errors.Is(err, windows.ERROR_ACCESS_DENIED) ||
+		errors.Is(err, windows.ERROR_LOCK_VIOLATION)
+}
@@ -0,0 +1,79 @@
+package abspath
+
+// ScratchOptions is a set of options for NewScratchDir() function.
+type ScratchOptions struct {
+	// Pattern is a pattern of the name of the scratch directory interpreted as MkdirTemp().  When it is empty,
+	// "scratch-*" is used.
+	Pattern string
+	// KeepOnFailure makes Close() keep the scratch directory when Fail() was called so that its contents can be
+	// inspected for debugging.
+	KeepOnFailure bool
+}
+
+// ScratchDir is a temporary directory whose whole tree is removed by Close().  It embeds AbsPath so that all methods
+// of AbsPath can be called on it directly.  It implements io.Closer and is intended to be used with defer.
+type ScratchDir struct {
+	AbsPath
+	cleanup       func() error
+	keepOnFailure bool
+	failed        bool
+	kept          bool
+	closed        bool
+}
+
+// NewScratchDir creates a new scratch directory under the parent directory.  The directory is removed with all its
+// contents when Close() is called.  opts can be nil.
+//
+// Example:
+//	s, err := abspath.NewScratchDir(buildDir, &abspath.ScratchOptions{KeepOnFailure: true})
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer s.Close()
+//	if err := compile(s.Join("obj")); err != nil {
+//		s.Fail() // Keep the directory to investigate the failure
+//		return err
+//	}
+func NewScratchDir(parent AbsPath, opts *ScratchOptions) (*ScratchDir, error) {
+	if opts == nil {
+		opts = &ScratchOptions{}
+	}
+	pat := opts.Pattern
+	if pat == "" {
+		pat = "scratch-*"
+	}
+	p, cleanup, err := parent.MkdirTemp(pat)
+	if err != nil {
+		return nil, err
+	}
+	return &ScratchDir{AbsPath: p, cleanup: cleanup, keepOnFailure: opts.KeepOnFailure}, nil
+}
+
+// Fail marks the work in the scratch directory as failed.  When KeepOnFailure option is set, Close() does not remove
+// the directory after it was called.
+func (s *ScratchDir) Fail() {
+	s.failed = true
+}
+
+// Keep makes Close() not remove the directory regardless of the failure.
+func (s *ScratchDir) Keep() {
+	s.kept = true
+}
+
+// Kept returns whether the directory is kept by Close().
+func (s *ScratchDir) Kept() bool {
+	return s.kept || s.failed && s.keepOnFailure
+}
+
+// Close removes the scratch directory and all its contents unless it should be kept.  It is safe to call it multiple
+// times.  Only the first call removes the directory.
+func (s *ScratchDir) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.Kept() {
+		return nil
+	}
+	return s.cleanup()
+}
@@ -0,0 +1,99 @@
+package abspath
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+var _ io.Closer = &ScratchDir{}
+
+func TestScratchDir(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+
+	s, err := NewScratchDir(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Dir() != root || !strings.HasPrefix(s.Base().String(), "scratch-") {
+		t.Errorf("Unexpected scratch directory: %s", s)
+	}
+	if err := ioutil.WriteFile(s.Join("file").String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s.Kept() {
+		t.Error("Scratch directory should not be kept")
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.String()); !os.IsNotExist(err) {
+		t.Errorf("Scratch directory should be removed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Closing twice should not fail: %v", err)
+	}
+}
+
+func TestScratchDirKeep(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+
+	for _, tc := range []struct {
+		what string
+		opts *ScratchOptions
+		fail bool
+		keep bool
+		kept bool
+	}{
+		{"failed without option", nil, true, false, false},
+		{"failed with option", &ScratchOptions{KeepOnFailure: true}, true, false, true},
+		{"succeeded with option", &ScratchOptions{KeepOnFailure: true}, false, false, false},
+		{"kept explicitly", nil, false, true, true},
+	} {
+		t.Run(tc.what, func(t *testing.T) {
+			s, err := NewScratchDir(root, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.fail {
+				s.Fail()
+			}
+			if tc.keep {
+				s.Keep()
+			}
+			if s.Kept() != tc.kept {
+				t.Errorf("Kept() should be %v", tc.kept)
+			}
+			if err := s.Close(); err != nil {
+				t.Fatal(err)
+			}
+			_, err = os.Stat(s.String())
+			if tc.kept && err != nil {
+				t.Errorf("Scratch directory should be kept: %v", err)
+			}
+			if !tc.kept && !os.IsNotExist(err) {
+				t.Errorf("Scratch directory should be removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestScratchDirPattern(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+
+	s, err := NewScratchDir(root, &ScratchOptions{Pattern: "build-*.tmp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if n := s.Base().String(); !strings.HasPrefix(n, "build-") || !strings.HasSuffix(n, ".tmp") {
+		t.Errorf("Name should match to pattern: %s", n)
+	}
+
+	if _, err := NewScratchDir(root.Join("missing"), nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+)
+
+// FirstExisting returns the first path which exists among the candidates.  It is useful to search a configuration file
+// from multiple locations.  When none of them exists, it returns an error which satisfies errors.Is(err, os.ErrNotExist).
+// When checking some candidate fails for other reason (e.g. permission), the error is returned.  The error is annotated
+// with the candidates as *PathError.
+//
+// Example:
+//	home, _ := abspath.HomeDir()
+//	etc, _ := abspath.New("/etc/app/config")
+//	p, err := abspath.FirstExisting(home.Join(".config", "app", "config"), etc)
+func FirstExisting(candidates ...AbsPath) (_ AbsPath, err error) {
+	defer annotate(&err, "find any of", candidates...)
+	if len(candidates) == 0 {
+		return AbsPath{""}, fmt.Errorf("no candidate was given: %w", os.ErrNotExist)
+	}
+
+	for _, c := range candidates {
+		_, err := fsys().Stat(c.underlying)
+		if err == nil {
+			return c, nil
+		}
+		if !os.IsNotExist(err) && !isNotDir(err) {
+			return AbsPath{""}, err
+		}
+	}
+
+	return AbsPath{""}, os.ErrNotExist
+}
+
+// FirstExistingExpand is the same as FirstExisting() but candidates are strings expanded by ExpandFrom().  So they can
+// be relative paths or paths starting with '~'.
+//
+// Example:
+//	p, err := abspath.FirstExistingExpand("./app.conf", "~/.config/app/config", "/etc/app/config")
+func FirstExistingExpand(candidates ...string) (AbsPath, error) {
+	ps := make([]AbsPath, 0, len(candidates))
+	for _, c := range candidates {
+		p, err := ExpandFrom(c)
+		if err != nil {
+			return AbsPath{""}, err
+		}
+		ps = append(ps, p)
+	}
+	return FirstExisting(ps...)
+}
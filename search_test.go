@@ -0,0 +1,64 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestFirstExisting(t *testing.T) {
+	root := makeTree(t, map[string]string{"b": "", "c/": "", "file": ""})
+
+	p, err := FirstExisting(root.Join("a"), root.Join("file", "x"), root.Join("b"), root.Join("c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := root.Join("b"); p != e {
+		t.Errorf("Expected %s but actually %s", e, p)
+	}
+
+	p, err = FirstExisting(root.Join("c"), root.Join("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := root.Join("c"); p != e {
+		t.Errorf("Expected %s but actually %s", e, p)
+	}
+
+	_, err = FirstExisting(root.Join("x"), root.Join("y"))
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not exist error but actually %v", err)
+	}
+	var pe *PathError
+	if !errors.As(err, &pe) || len(pe.Paths) != 2 || pe.Paths[0] != root.Join("x") || pe.Paths[1] != root.Join("y") {
+		t.Errorf("Error should be annotated with candidates: %v", err)
+	}
+	_, err = FirstExisting()
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not exist error for no candidate but actually %v", err)
+	}
+}
+
+func TestFirstExistingExpand(t *testing.T) {
+	root := makeTree(t, map[string]string{"b": ""})
+
+	p, err := FirstExistingExpand(root.Join("a").String(), root.Join("b").String(), "relative-not-exist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e := root.Join("b"); p != e {
+		t.Errorf("Expected %s but actually %s", e, p)
+	}
+
+	p, err = FirstExistingExpand("no-such-file", "testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := ExpandFrom("testdata"); p != e {
+		t.Errorf("Expected %s but actually %s", e, p)
+	}
+
+	if _, err := FirstExistingExpand("", "testdata"); err == nil {
+		t.Errorf("Empty candidate must cause an error")
+	}
+}
@@ -0,0 +1,112 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Segments returns components of the path.  A volume name (e.g. 'C:' or '\\server\share' on Windows) is stripped and
+// separators are not included.  The root directory returns an empty slice.  For a path created with NoClean() or
+// KeepTrailingSeparator() option, redundant separators, '.' components and a trailing separator are ignored, and '..'
+// components are returned as they are.
+//
+// Example:
+//	a, _ := abspath.New("/foo/bar/baz")
+//	a.Segments() // => []string{"foo", "bar", "baz"}
+func (a AbsPath) Segments() []string {
+	p := a.tidy()
+	s := p[len(filepath.VolumeName(p)):]
+	s = strings.Trim(s, string(os.PathSeparator))
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, string(os.PathSeparator))
+}
+
+// SegmentAt returns the i-th component of the path.  Negative index counts from the last component (-1 means the last
+// component).  The second return value is false when the index is out of range.
+//
+// Example:
+//	a, _ := abspath.New("/foo/bar/baz")
+//	a.SegmentAt(0)  // => "foo", true
+//	a.SegmentAt(-1) // => "baz", true
+//	a.SegmentAt(3)  // => "", false
+func (a AbsPath) SegmentAt(i int) (string, bool) {
+	ss := a.Segments()
+	if i < 0 {
+		i += len(ss)
+	}
+	if i < 0 || len(ss) <= i {
+		return "", false
+	}
+	return ss[i], true
+}
+
+// Depth returns the number of components below the root directory (or the volume on Windows).  The root directory
+// returns 0.  It is the same as the length of Segments() so '..' components created with NoClean() option are counted.
+//
+// Example:
+//	a, _ := abspath.New("/foo/bar")
+//	a.Depth() // => 2
+func (a AbsPath) Depth() int {
+	p := a.tidy()
+	s := p[len(filepath.VolumeName(p)):]
+	s = strings.Trim(s, string(os.PathSeparator))
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, string(os.PathSeparator)) + 1
+}
+
+// IsRoot returns whether the path is a root directory.  It treats '/' on Unix, and 'C:\' and '\\server\share' on
+// Windows as root directories.  It is useful as a termination condition of a loop going up to parent directories.
+//
+// Example:
+//	for a := start; !a.IsRoot(); a = a.Dir() {
+//		// ...
+//	}
+func (a AbsPath) IsRoot() bool {
+	return a.Depth() == 0
+}
+
+// Rebase re-roots the path from oldBase to newBase.  It calculates the relative part of the path under oldBase and joins
+// it to newBase.  When the path is not under oldBase (or equal to oldBase), it returns an error.
+//
+// Example:
+//	a, _ := abspath.New("/src/project/foo/bar.txt")
+//	src, _ := abspath.New("/src/project")
+//	dst, _ := abspath.New("/backup/project")
+//	b, err := a.Rebase(src, dst) // => "/backup/project/foo/bar.txt"
+func (a AbsPath) Rebase(oldBase, newBase AbsPath) (AbsPath, error) {
+	if !oldBase.ContainsPath(a) {
+		return AbsPath{""}, fmt.Errorf("cannot rebase '%s' since it is not under '%s'", a.underlying, oldBase.underlying)
+	}
+	return newBase.Join(a.Segments()[oldBase.Depth():]...), nil
+}
+
+// SplitAll decomposes the path into its volume name and components.  The volume name is empty on Unix.  The path can be
+// restored from the return values with FromComponents().
+//
+// Example:
+//	a, _ := abspath.New(`C:\foo\bar`)
+//	v, ss := a.SplitAll() // => "C:", []string{"foo", "bar"}
+func (a AbsPath) SplitAll() (volume string, segments []string) {
+	return filepath.VolumeName(a.underlying), a.Segments()
+}
+
+// FromComponents creates AbsPath instance from a volume name and components.  It is an inverse of SplitAll().  Each
+// component must not be empty, '.' nor '..' and must not contain any path separator.  The volume name must be empty on
+// Unix.  When the result is not an absolute path, it returns an error.
+//
+// Example:
+//	a, err := abspath.FromComponents("", "foo", "bar") // => "/foo/bar" on Unix
+func FromComponents(volume string, segments ...string) (AbsPath, error) {
+	for _, s := range segments {
+		if s == "" || s == "." || s == ".." || strings.ContainsAny(s, `/`+string(os.PathSeparator)) {
+			return AbsPath{""}, fmt.Errorf("invalid path component %q", s)
+		}
+	}
+	return New(volume + string(os.PathSeparator) + strings.Join(segments, string(os.PathSeparator)))
+}
@@ -0,0 +1,191 @@
+package abspath
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSegments(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected []string
+	}{
+		{"/", []string{}},
+		{"/foo", []string{"foo"}},
+		{"/foo/bar/baz", []string{"foo", "bar", "baz"}},
+		{"/foo//bar/", []string{"foo", "bar"}},
+		{"/path includes/white space", []string{"path includes", "white space"}},
+	} {
+		a, err := FromSlash(fixAbsPath(c.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := a.Segments()
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("Expected %#v for %s but actually %#v", c.expected, a, actual)
+		}
+	}
+
+	if isWindows {
+		a, _ := New(`\\server\share\foo\bar`)
+		actual := a.Segments()
+		expected := []string{"foo", "bar"}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %#v for %s but actually %#v", expected, a, actual)
+		}
+	}
+}
+
+func TestSegmentAt(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/foo/bar/baz"))
+	for _, c := range []struct {
+		index    int
+		expected string
+		ok       bool
+	}{
+		{0, "foo", true},
+		{2, "baz", true},
+		{3, "", false},
+		{-1, "baz", true},
+		{-3, "foo", true},
+		{-4, "", false},
+	} {
+		s, ok := a.SegmentAt(c.index)
+		if s != c.expected || ok != c.ok {
+			t.Errorf("Expected (%q, %v) for index %d but actually (%q, %v)", c.expected, c.ok, c.index, s, ok)
+		}
+	}
+
+	r, _ := FromSlash(fixAbsPath("/"))
+	if _, ok := r.SegmentAt(0); ok {
+		t.Errorf("Root directory should have no segment")
+	}
+}
+
+func TestDepthIsRoot(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		expected int
+	}{
+		{"/", 0},
+		{"/foo", 1},
+		{"/foo/bar/baz", 3},
+		{"/foo/bar/", 2},
+	} {
+		a, err := FromSlash(fixAbsPath(c.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := a.Depth(); d != c.expected {
+			t.Errorf("Expected depth %d for %s but actually %d", c.expected, a, d)
+		}
+		if d := len(a.Segments()); d != c.expected {
+			t.Errorf("Depth should be equal to the number of segments for %s: %d", a, d)
+		}
+		if r := a.IsRoot(); r != (c.expected == 0) {
+			t.Errorf("Unexpected IsRoot() result %v for %s", r, a)
+		}
+	}
+
+	if isWindows {
+		for _, s := range []string{`C:\`, `\\server\share`, `\\server\share\`} {
+			a, err := New(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !a.IsRoot() {
+				t.Errorf("%s should be root", a)
+			}
+		}
+	}
+
+	a, _ := FromSlash(fixAbsPath("/foo/bar/baz"))
+	n := 0
+	for ; !a.IsRoot(); a = a.Dir() {
+		n++
+	}
+	if n != 3 {
+		t.Errorf("Loop going up to root should stop after 3 iterations but actually %d", n)
+	}
+}
+
+func TestRebase(t *testing.T) {
+	for _, c := range []struct {
+		path     string
+		oldBase  string
+		newBase  string
+		expected string
+	}{
+		{"/src/project/foo/bar.txt", "/src/project", "/backup/project", "/backup/project/foo/bar.txt"},
+		{"/src/project", "/src/project", "/backup", "/backup"},
+		{"/foo/bar", "/", "/baz", "/baz/foo/bar"},
+		{"/foo/bar", "/foo", "/", "/bar"},
+	} {
+		a, _ := FromSlash(fixAbsPath(c.path))
+		o, _ := FromSlash(fixAbsPath(c.oldBase))
+		n, _ := FromSlash(fixAbsPath(c.newBase))
+		e, _ := FromSlash(fixAbsPath(c.expected))
+		r, err := a.Rebase(o, n)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if r != e {
+			t.Errorf("Expected %s but actually %s", e, r)
+		}
+	}
+
+	a, _ := FromSlash(fixAbsPath("/foobar/baz"))
+	o, _ := FromSlash(fixAbsPath("/foo"))
+	n, _ := FromSlash(fixAbsPath("/dst"))
+	if _, err := a.Rebase(o, n); err == nil {
+		t.Errorf("Path not under the old base must cause an error")
+	}
+}
+
+func TestSplitAllFromComponents(t *testing.T) {
+	for _, input := range []string{"/", "/foo", "/foo/bar/baz"} {
+		a, _ := FromSlash(fixAbsPath(input))
+		v, ss := a.SplitAll()
+		if v != a.VolumeName() {
+			t.Errorf("Expected volume %q but actually %q", a.VolumeName(), v)
+		}
+		if !reflect.DeepEqual(ss, a.Segments()) {
+			t.Errorf("Expected %#v but actually %#v", a.Segments(), ss)
+		}
+		b, err := FromComponents(v, ss...)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if a != b {
+			t.Errorf("Expected %s but actually %s", a, b)
+		}
+	}
+
+	if isWindows {
+		for _, s := range []string{`\\server\share\foo`, `D:\`} {
+			a, _ := New(s)
+			v, ss := a.SplitAll()
+			b, err := FromComponents(v, ss...)
+			if err != nil {
+				t.Error(err)
+				continue
+			}
+			if a != b {
+				t.Errorf("Expected %s but actually %s", a, b)
+			}
+		}
+	}
+
+	for _, ss := range [][]string{
+		{"foo", ""},
+		{"foo", "."},
+		{"..", "foo"},
+		{"foo/bar"},
+	} {
+		if _, err := FromComponents("", ss...); err == nil {
+			t.Errorf("Error was expected for %#v", ss)
+		}
+	}
+}
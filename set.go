@@ -0,0 +1,97 @@
+package abspath
+
+// PathSet is a set of paths.  Paths are compared as strings.  The zero value is an empty set ready to use.  It is not
+// safe for concurrent use.
+type PathSet struct {
+	m map[AbsPath]struct{}
+}
+
+// NewPathSet creates a new set containing the given paths.
+//
+// Example:
+//	excludes := abspath.NewPathSet(a, b, c)
+func NewPathSet(ps ...AbsPath) *PathSet {
+	s := &PathSet{make(map[AbsPath]struct{}, len(ps))}
+	s.Add(ps...)
+	return s
+}
+
+// Add adds the paths to the set.
+func (s *PathSet) Add(ps ...AbsPath) {
+	if s.m == nil {
+		s.m = make(map[AbsPath]struct{}, len(ps))
+	}
+	for _, p := range ps {
+		s.m[p] = struct{}{}
+	}
+}
+
+// Remove removes the path from the set.
+func (s *PathSet) Remove(p AbsPath) {
+	delete(s.m, p)
+}
+
+// Contains returns whether the set contains the path.
+func (s *PathSet) Contains(p AbsPath) bool {
+	_, ok := s.m[p]
+	return ok
+}
+
+// ContainsOrParent returns whether the set contains the path or any of its ancestors.  It takes time proportional to
+// the depth of the path.  It is useful for exclusion lists where excluding a directory excludes everything under it.
+//
+// Example:
+//	s := abspath.NewPathSet(nodeModules)
+//	s.ContainsOrParent(nodeModules.Join("foo", "index.js")) // => true
+func (s *PathSet) ContainsOrParent(p AbsPath) bool {
+	if len(s.m) == 0 {
+		return false
+	}
+	for {
+		if s.Contains(p) {
+			return true
+		}
+		if p.IsRoot() {
+			return false
+		}
+		p = p.Dir()
+	}
+}
+
+// Len returns the number of paths in the set.
+func (s *PathSet) Len() int {
+	return len(s.m)
+}
+
+// Union returns a new set containing paths in either the set or the other set.
+func (s *PathSet) Union(o *PathSet) *PathSet {
+	r := &PathSet{make(map[AbsPath]struct{}, len(s.m)+len(o.m))}
+	for p := range s.m {
+		r.m[p] = struct{}{}
+	}
+	for p := range o.m {
+		r.m[p] = struct{}{}
+	}
+	return r
+}
+
+// Intersect returns a new set containing paths in both the set and the other set.
+func (s *PathSet) Intersect(o *PathSet) *PathSet {
+	r := &PathSet{map[AbsPath]struct{}{}}
+	for p := range s.m {
+		if o.Contains(p) {
+			r.m[p] = struct{}{}
+		}
+	}
+	return r
+}
+
+// Slice returns paths in the set sorted by SortLexical().
+func (s *PathSet) Slice() []AbsPath {
+	ps := make([]AbsPath, 0, len(s.m))
+	for p := range s.m {
+		ps = append(ps, p)
+	}
+	SortLexical(ps)
+	return ps
+}
@@ -0,0 +1,74 @@
+package abspath
+
+import (
+	"testing"
+)
+
+func TestPathSet(t *testing.T) {
+	ps := pathsFromSlash("/a", "/b/c", "/d")
+	s := NewPathSet(ps[0], ps[1])
+
+	if s.Len() != 2 {
+		t.Errorf("Expected 2 paths but actually %d", s.Len())
+	}
+	if !s.Contains(ps[0]) || !s.Contains(ps[1]) || s.Contains(ps[2]) {
+		t.Errorf("Unexpected contents: %v", s.Slice())
+	}
+
+	s.Add(ps[2], ps[2])
+	if s.Len() != 3 || !s.Contains(ps[2]) {
+		t.Errorf("Unexpected contents after adding: %v", s.Slice())
+	}
+
+	s.Remove(ps[2])
+	s.Remove(ps[2])
+	if s.Len() != 2 || s.Contains(ps[2]) {
+		t.Errorf("Unexpected contents after removing: %v", s.Slice())
+	}
+
+	assertPathsEqual(t, pathsFromSlash("/a", "/b/c"), s.Slice())
+
+	var zero PathSet
+	if zero.Contains(ps[0]) || zero.ContainsOrParent(ps[0]) || zero.Len() != 0 {
+		t.Errorf("Zero value should be empty")
+	}
+	zero.Add(ps[0])
+	if !zero.Contains(ps[0]) {
+		t.Errorf("Zero value should be usable")
+	}
+}
+
+func TestPathSetContainsOrParent(t *testing.T) {
+	s := NewPathSet(pathsFromSlash("/a/b", "/c")...)
+	for _, c := range []struct {
+		input    string
+		expected bool
+	}{
+		{"/a/b", true},
+		{"/a/b/c/d", true},
+		{"/c/foo", true},
+		{"/a", false},
+		{"/a/bc", false},
+		{"/", false},
+	} {
+		p := pathsFromSlash(c.input)[0]
+		if r := s.ContainsOrParent(p); r != c.expected {
+			t.Errorf("Expected %v for %s but actually %v", c.expected, p, r)
+		}
+	}
+
+	root := NewPathSet(pathsFromSlash("/")...)
+	if !root.ContainsOrParent(pathsFromSlash("/foo/bar")[0]) {
+		t.Errorf("Root directory should contain everything")
+	}
+}
+
+func TestPathSetUnionIntersect(t *testing.T) {
+	s1 := NewPathSet(pathsFromSlash("/a", "/b", "/c")...)
+	s2 := NewPathSet(pathsFromSlash("/b", "/c", "/d")...)
+
+	assertPathsEqual(t, pathsFromSlash("/a", "/b", "/c", "/d"), s1.Union(s2).Slice())
+	assertPathsEqual(t, pathsFromSlash("/b", "/c"), s1.Intersect(s2).Slice())
+	assertPathsEqual(t, pathsFromSlash("/a", "/b", "/c"), s1.Slice())
+	assertPathsEqual(t, []AbsPath{}, s1.Intersect(&PathSet{}).Slice())
+}
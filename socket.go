@@ -0,0 +1,101 @@
+package abspath
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"unicode/utf8"
+)
+
+// UnixSocketPathError is an error returned from ValidateUnixSocket() when the path is too long for a Unix domain
+// socket.
+type UnixSocketPathError struct {
+	// Path is the path of the socket.
+	Path AbsPath
+	// Max is the maximum length of a socket path in bytes on the platform.
+	Max int
+	// Suggestion is a shorter path which can be used instead.
+	Suggestion AbsPath
+}
+
+func (err *UnixSocketPathError) Error() string {
+	return fmt.Sprintf("Path '%s' is too long for Unix domain socket (%d bytes > %d bytes on %s). Consider '%s' instead", err.Path.underlying, len(err.Path.underlying), err.Max, runtime.GOOS, err.Suggestion.underlying)
+}
+
+// sunPathSize returns the size of sun_path field of struct sockaddr_un on the platform.
+func sunPathSize(goos string) int {
+	switch goos {
+	case "darwin", "ios", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return 104
+	case "aix":
+		return 1023
+	default:
+		// Linux, Solaris, illumos and Windows
+		return 108
+	}
+}
+
+// MaxUnixSocketPathLen returns the maximum length in bytes of a path of Unix domain socket on the current platform.
+// It is smaller than the size of sun_path by one for the terminating NUL character.
+func MaxUnixSocketPathLen() int {
+	return sunPathSize(runtime.GOOS) - 1
+}
+
+// shortSocketDir returns the directory where a shortened socket path is suggested.
+func shortSocketDir() string {
+	if runtime.GOOS == "windows" {
+		return os.TempDir()
+	}
+	// $TMPDIR on macOS is too deep for sockets
+	return "/tmp"
+}
+
+// ValidateUnixSocket checks that the path can be used as an address of a Unix domain socket.  The length of a socket
+// path is limited by the size of sun_path (104 bytes on macOS and BSDs, 108 bytes on Linux and Windows) and a longer
+// path is silently truncated or rejected by some libraries.  When the path is too long, it returns
+// *UnixSocketPathError with a suggested shorter path under /tmp (the temporary directory on Windows).  The suggestion
+// contains a hash of the original path so that different paths are not shortened to the same path.
+//
+// Example:
+//	sock, _ := abspath.ExpandFrom("~/.local/share/myapp/instances/default/control.sock")
+//	if err := sock.ValidateUnixSocket(); err != nil {
+//		var serr *abspath.UnixSocketPathError
+//		if errors.As(err, &serr) {
+//			sock = serr.Suggestion
+//		}
+//	}
+func (a AbsPath) ValidateUnixSocket() error {
+	max := MaxUnixSocketPathLen()
+	if len(a.underlying) <= max {
+		return nil
+	}
+	return &UnixSocketPathError{a, max, a.shortSocketPath(max)}
+}
+
+func (a AbsPath) shortSocketPath(max int) AbsPath {
+	dir := shortSocketDir()
+	sum := sha256.Sum256([]byte(a.underlying))
+	hash := hex.EncodeToString(sum[:4])
+
+	stem, ext := a.Stem(), a.Ext()
+	// dir + separator + stem + '-' + hash + ext
+	room := max - len(dir) - 1 - len(hash) - 1 - len(ext)
+	if room < 0 {
+		// The extension is too long to be kept
+		stem, ext = "", ""
+	} else if room < len(stem) {
+		// Do not cut a multi-byte character in the middle
+		for room > 0 && !utf8.RuneStart(stem[room]) {
+			room--
+		}
+		stem = stem[:room]
+	}
+	name := hash + ext
+	if stem != "" {
+		name = stem + "-" + name
+	}
+	return AbsPath{filepath.Join(dir, name)}
+}
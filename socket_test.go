@@ -0,0 +1,112 @@
+package abspath
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSunPathSize(t *testing.T) {
+	for goos, want := range map[string]int{
+		"linux":   108,
+		"darwin":  104,
+		"freebsd": 104,
+		"windows": 108,
+	} {
+		if have := sunPathSize(goos); have != want {
+			t.Errorf("sun_path size on %s should be %d but got %d", goos, want, have)
+		}
+	}
+}
+
+func TestValidateUnixSocket(t *testing.T) {
+	root, err := FromSlash(fixAbsPath("/run"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	max := MaxUnixSocketPathLen()
+
+	ok := root.Join("app.sock")
+	if err := ok.ValidateUnixSocket(); err != nil {
+		t.Errorf("Short path should be valid: %v", err)
+	}
+
+	exact := root.Join(strings.Repeat("a", max-len(root.String())-1))
+	if len(exact.String()) != max {
+		t.Fatalf("Unexpected length: %d", len(exact.String()))
+	}
+	if err := exact.ValidateUnixSocket(); err != nil {
+		t.Errorf("Path of %d bytes should be valid: %v", max, err)
+	}
+
+	for _, long := range []AbsPath{
+		root.Join(strings.Repeat("dir/", 30), "control.sock"),
+		root.Join(strings.Repeat("dir/", 30), "control"),
+		root.Join(strings.Repeat("x", max) + ".sock"),
+		exact.Join("a"),
+	} {
+		err := long.ValidateUnixSocket()
+		var serr *UnixSocketPathError
+		if !errors.As(err, &serr) {
+			t.Errorf("UnixSocketPathError should be returned for %s but got %v", long, err)
+			continue
+		}
+		if serr.Path != long || serr.Max != max {
+			t.Errorf("Unexpected error: %+v", serr)
+		}
+		s := serr.Suggestion
+		if err := s.ValidateUnixSocket(); err != nil {
+			t.Errorf("Suggestion %s should be valid: %v", s, err)
+		}
+		if filepath.Ext(s.String()) != filepath.Ext(long.String()) {
+			t.Errorf("Suggestion %s should keep extension of %s", s, long)
+		}
+		if !isWindows && s.Dir().String() != "/tmp" {
+			t.Errorf("Suggestion should be under /tmp but got %s", s)
+		}
+		if !strings.Contains(err.Error(), s.String()) {
+			t.Errorf("Error message should contain suggestion: %s", err)
+		}
+	}
+
+	a := root.Join(strings.Repeat("a/", 60), "control.sock")
+	b := root.Join(strings.Repeat("b/", 60), "control.sock")
+	var ea, eb *UnixSocketPathError
+	if !errors.As(a.ValidateUnixSocket(), &ea) || !errors.As(b.ValidateUnixSocket(), &eb) {
+		t.Fatal("Long paths should be invalid")
+	}
+	if ea.Suggestion == eb.Suggestion {
+		t.Errorf("Different paths should have different suggestions: %s", ea.Suggestion)
+	}
+}
+
+func TestUnixSocketSuggestionLength(t *testing.T) {
+	root := makeTree(t, nil)
+	max := MaxUnixSocketPathLen()
+
+	for i := 0; i < 3; i++ {
+		// Shift multi-byte characters so that the limit falls in the middle of a character
+		a := root.Join(strings.Repeat("x", i) + strings.Repeat("あ", max) + ".sock")
+		s := a.shortSocketPath(max)
+		if !utf8.ValidString(s.String()) {
+			t.Errorf("Suggestion should not cut a multi-byte character: %q", s)
+		}
+		if len(s.String()) > max {
+			t.Errorf("Suggestion %s should not be longer than %d bytes", s, max)
+		}
+		if filepath.Ext(s.String()) != ".sock" {
+			t.Errorf("Suggestion %s should keep extension", s)
+		}
+	}
+
+	a := root.Join("control." + strings.Repeat("x", max))
+	s := a.shortSocketPath(max)
+	if len(s.String()) > max {
+		t.Errorf("Suggestion %s should not be longer than %d bytes when extension is too long", s, max)
+	}
+	if err := s.ValidateUnixSocket(); err != nil {
+		t.Errorf("Suggestion %s should be valid: %v", s, err)
+	}
+}
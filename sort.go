@@ -0,0 +1,130 @@
+package abspath
+
+import (
+	"sort"
+	"strings"
+)
+
+// compareSegments compares two paths component by component.  cmp is used to compare each pair of components.  When one
+// path is an ancestor of the other, descendantFirst decides which comes first.
+func compareSegments(a, b AbsPath, cmp func(x, y string) int, descendantFirst bool) int {
+	if c := strings.Compare(a.VolumeName(), b.VolumeName()); c != 0 {
+		return c
+	}
+	as, bs := a.Segments(), b.Segments()
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := cmp(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	c := 0
+	switch {
+	case len(as) < len(bs):
+		c = -1
+	case len(as) > len(bs):
+		c = 1
+	}
+	if descendantFirst {
+		c = -c
+	}
+	return c
+}
+
+// Compare compares two paths component by component.  It returns a negative integer when the path is less than the
+// other, zero when they are equal, and a positive integer otherwise.  Since components are compared, a directory and
+// its descendants are adjacent in the sorted order ('/a/b' < '/a-b' while "/a/b" > "/a-b" as strings).
+//
+// Example:
+//	a, _ := abspath.New("/a/b")
+//	b, _ := abspath.New("/a-b")
+//	a.Compare(b) // => -1
+func (a AbsPath) Compare(b AbsPath) int {
+	return compareSegments(a, b, strings.Compare, false)
+}
+
+// isDigit returns whether the byte is an ASCII digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// compareNatural compares two strings treating sequences of digits as numbers.
+func compareNatural(x, y string) int {
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		if !isDigit(x[i]) || !isDigit(y[j]) {
+			if x[i] != y[j] {
+				if x[i] < y[j] {
+					return -1
+				}
+				return 1
+			}
+			i++
+			j++
+			continue
+		}
+
+		// Compare numbers.  Leading zeros are ignored so the longer number is greater.
+		si, sj := i, j
+		for i < len(x) && isDigit(x[i]) {
+			i++
+		}
+		for j < len(y) && isDigit(y[j]) {
+			j++
+		}
+		nx := strings.TrimLeft(x[si:i], "0")
+		ny := strings.TrimLeft(y[sj:j], "0")
+		if len(nx) != len(ny) {
+			if len(nx) < len(ny) {
+				return -1
+			}
+			return 1
+		}
+		if c := strings.Compare(nx, ny); c != 0 {
+			return c
+		}
+	}
+	if c := (len(x) - i) - (len(y) - j); c != 0 {
+		if c < 0 {
+			return -1
+		}
+		return 1
+	}
+	// Equal as natural order (e.g. "01" and "1").  Fall back to lexical order to make the order deterministic.
+	return strings.Compare(x, y)
+}
+
+// SortLexical sorts the paths in place in the order of Compare() method.
+//
+// Example:
+//	ps := []abspath.AbsPath{...}
+//	abspath.SortLexical(ps)
+func SortLexical(ps []AbsPath) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		return ps[i].Compare(ps[j]) < 0
+	})
+}
+
+// SortNatural sorts the paths in place in natural order.  It is similar to SortLexical() but sequences of digits in
+// each component are compared as numbers.  So 'file2' comes before 'file10'.
+//
+// Example:
+//	// [/img/file1.png /img/file2.png /img/file10.png]
+//	abspath.SortNatural(ps)
+func SortNatural(ps []AbsPath) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		return compareSegments(ps[i], ps[j], compareNatural, false) < 0
+	})
+}
+
+// SortDepthFirst sorts the paths in place in post-order of depth-first traversal.  Descendants of a directory come
+// before the directory and siblings are ordered as Compare() method.  It is useful to remove directories from the
+// bottom or to process children before their parents.
+//
+// Example:
+//	// [/a/b/c /a/b /a/d /a]
+//	abspath.SortDepthFirst(ps)
+func SortDepthFirst(ps []AbsPath) {
+	sort.SliceStable(ps, func(i, j int) bool {
+		return compareSegments(ps[i], ps[j], strings.Compare, true) < 0
+	})
+}
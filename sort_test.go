@@ -0,0 +1,90 @@
+package abspath
+
+import (
+	"testing"
+)
+
+func pathsFromSlash(ss ...string) []AbsPath {
+	ps := make([]AbsPath, 0, len(ss))
+	for _, s := range ss {
+		p, err := FromSlash(fixAbsPath(s))
+		if err != nil {
+			panic(err)
+		}
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+func assertPathsEqual(t *testing.T, expected, actual []AbsPath) {
+	t.Helper()
+	if len(expected) != len(actual) {
+		t.Errorf("Expected %v but actually %v", expected, actual)
+		return
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Errorf("Expected %v but actually %v", expected, actual)
+			return
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	for _, c := range []struct {
+		lhs      string
+		rhs      string
+		expected int
+	}{
+		{"/a", "/a", 0},
+		{"/a", "/b", -1},
+		{"/b", "/a", 1},
+		{"/a/b", "/a-b", -1},
+		{"/a", "/a/b", -1},
+		{"/a/b", "/a", 1},
+	} {
+		ps := pathsFromSlash(c.lhs, c.rhs)
+		if r := ps[0].Compare(ps[1]); r != c.expected {
+			t.Errorf("Expected %d for %s and %s but actually %d", c.expected, c.lhs, c.rhs, r)
+		}
+	}
+}
+
+func TestSortLexical(t *testing.T) {
+	ps := pathsFromSlash("/b", "/a-b", "/a/c", "/a", "/a/b/c", "/file10", "/file2")
+	SortLexical(ps)
+	assertPathsEqual(t, pathsFromSlash("/a", "/a/b/c", "/a/c", "/a-b", "/b", "/file10", "/file2"), ps)
+}
+
+func TestSortNatural(t *testing.T) {
+	ps := pathsFromSlash("/img/file10.png", "/img/file2.png", "/img/file1.png", "/v1.10/a", "/v1.9/a", "/x01", "/x1", "/x001a", "/x1b")
+	SortNatural(ps)
+	assertPathsEqual(t, pathsFromSlash("/img/file1.png", "/img/file2.png", "/img/file10.png", "/v1.9/a", "/v1.10/a", "/x01", "/x1", "/x001a", "/x1b"), ps)
+}
+
+func TestCompareNatural(t *testing.T) {
+	for _, c := range []struct {
+		lhs      string
+		rhs      string
+		expected int
+	}{
+		{"a2", "a10", -1},
+		{"a10", "a2", 1},
+		{"a", "a1", -1},
+		{"a1", "a1", 0},
+		{"a01", "a1", -1},
+		{"abc", "abd", -1},
+		{"10", "9", 1},
+		{"", "", 0},
+	} {
+		if r := compareNatural(c.lhs, c.rhs); r != c.expected {
+			t.Errorf("Expected %d for %q and %q but actually %d", c.expected, c.lhs, c.rhs, r)
+		}
+	}
+}
+
+func TestSortDepthFirst(t *testing.T) {
+	ps := pathsFromSlash("/a", "/a/d", "/a/b", "/a/b/c", "/e", "/")
+	SortDepthFirst(ps)
+	assertPathsEqual(t, pathsFromSlash("/a/b/c", "/a/b", "/a/d", "/a", "/e", "/"), ps)
+}
@@ -0,0 +1,43 @@
+package abspath
+
+import (
+	"fmt"
+)
+
+// IsSparse returns whether the file at the path is a sparse file.  On Unix, a file is considered sparse when the
+// number of allocated blocks is smaller than its size.  Note that files on compressing filesystems may also be
+// considered sparse.  On Windows, the sparse file attribute is checked.  Symbolic links are followed.  When the
+// platform or the current FS does not provide the information, it returns UnsupportedError.
+//
+// Example:
+//	img, _ := abspath.New("/var/lib/vm/disk.img")
+//	sparse, err := img.IsSparse()
+func (a AbsPath) IsSparse() (_ bool, err error) {
+	defer annotate(&err, "check sparseness of", a)
+	s, err := fsys().Stat(a.underlying)
+	if err != nil {
+		return false, err
+	}
+	sparse, ok := isSparse(s)
+	if !ok {
+		return false, &UnsupportedError{"Checking sparse file", nil}
+	}
+	return sparse, nil
+}
+
+// PunchHole deallocates the byte range [off, off+length) of the file at the path so that the space is reclaimed while
+// the size of the file is kept.  Reading the range returns zeros after that.  It is implemented with fallocate(2) with
+// FALLOC_FL_PUNCH_HOLE on Linux and FSCTL_SET_ZERO_DATA on Windows.  On Windows, the file is marked as sparse before
+// punching.  On other platforms, it returns UnsupportedError.  Since it requires OS-specific features, it always
+// accesses the OS filesystem.
+//
+// Example:
+//	log, _ := abspath.New("/var/log/huge.log")
+//	// Reclaim the first 1GiB which was already processed
+//	err := log.PunchHole(0, 1<<30)
+func (a AbsPath) PunchHole(off, length int64) (err error) {
+	defer annotate(&err, "punch hole in", a)
+	return audit("punchhole", fmt.Sprintf("offset=%d length=%d", off, length), func() error {
+		return punchHole(a.underlying, off, length)
+	}, a.underlying)
+}
@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+// +build !unix,!windows
+
+package abspath
+
+import (
+	"os"
+)
+
+// isSparse always fails since the number of allocated blocks is not available on this platform.
+func isSparse(info os.FileInfo) (bool, bool) {
+	return false, false
+}
@@ -0,0 +1,115 @@
+package abspath
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestIsSparse(t *testing.T) {
+	if runtime.GOOS == "plan9" {
+		t.Skip("Sparse files are not detectable on Plan 9")
+	}
+	root := makeTree(t, map[string]string{})
+
+	dense := root.Join("dense")
+	if err := ioutil.WriteFile(dense.String(), bytes.Repeat([]byte{'a'}, 1<<20), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := dense.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	sparse, err := dense.IsSparse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sparse {
+		t.Errorf("File filled with data should not be sparse: %s", dense)
+	}
+
+	if _, err := root.Join("missing").IsSparse(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
+
+func TestIsSparseOtherFS(t *testing.T) {
+	root := makeTree(t, map[string]string{"file": "hello"})
+	prev := SetFS(noSysFS{OSFS})
+	defer SetFS(prev)
+
+	if _, err := root.Join("file").IsSparse(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Unsupported error should be returned but got %v", err)
+	}
+}
+
+// noSysFS hides Sys() of file infos as fake filesystems do
+type noSysFS struct {
+	FS
+}
+
+type noSysInfo struct {
+	os.FileInfo
+}
+
+func (noSysInfo) Sys() interface{} { return nil }
+
+func (f noSysFS) Stat(name string) (os.FileInfo, error) {
+	s, err := f.FS.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	return noSysInfo{s}, nil
+}
+
+func TestPunchHole(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+	p := root.Join("file")
+	const size = 1 << 20
+	if err := ioutil.WriteFile(p.String(), bytes.Repeat([]byte{'a'}, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := p.PunchHole(0, size/2)
+	if runtime.GOOS != "linux" && !isWindows {
+		if !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Unsupported error should be returned but got %v", err)
+		}
+		return
+	}
+	if err != nil {
+		// Filesystem may not support punching holes
+		t.Skip(err)
+	}
+
+	b, err := ioutil.ReadFile(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != size {
+		t.Errorf("Size should be kept but got %d", len(b))
+	}
+	if !bytes.Equal(b[:size/2], make([]byte, size/2)) {
+		t.Error("Punched range should be filled with zeros")
+	}
+	if !bytes.Equal(b[size/2:], bytes.Repeat([]byte{'a'}, size/2)) {
+		t.Error("Rest of file should be kept")
+	}
+
+	sparse, err := p.IsSparse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sparse {
+		t.Errorf("File should be sparse after punching hole: %s", p)
+	}
+
+	if err := root.Join("missing").PunchHole(0, 1); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
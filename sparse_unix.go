@@ -0,0 +1,18 @@
+//go:build unix
+// +build unix
+
+package abspath
+
+import (
+	"os"
+	"syscall"
+)
+
+// isSparse compares the number of 512-byte blocks allocated for the file with its size.
+func isSparse(info os.FileInfo) (bool, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false, false
+	}
+	return int64(st.Blocks)*512 < info.Size(), true
+}
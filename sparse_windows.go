@@ -0,0 +1,44 @@
+package abspath
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// isSparse checks the sparse file attribute of the file.
+func isSparse(info os.FileInfo) (bool, bool) {
+	d, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return false, false
+	}
+	return d.FileAttributes&windows.FILE_ATTRIBUTE_SPARSE_FILE != 0, true
+}
+
+// fileZeroDataInformation is FILE_ZERO_DATA_INFORMATION structure.
+type fileZeroDataInformation struct {
+	FileOffset      int64
+	BeyondFinalZero int64
+}
+
+func punchHole(p string, off, length int64) error {
+	f, err := os.OpenFile(p, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	h := windows.Handle(f.Fd())
+
+	var n uint32
+	if err := windows.DeviceIoControl(h, windows.FSCTL_SET_SPARSE, nil, 0, nil, 0, &n, nil); err != nil {
+		f.Close()
+		return &os.PathError{Op: "punchhole", Path: p, Err: err}
+	}
+	z := fileZeroDataInformation{off, off + length}
+	if err := windows.DeviceIoControl(h, windows.FSCTL_SET_ZERO_DATA, (*byte)(unsafe.Pointer(&z)), uint32(unsafe.Sizeof(z)), nil, 0, &n, nil); err != nil {
+		f.Close()
+		return &os.PathError{Op: "punchhole", Path: p, Err: err}
+	}
+	return f.Close()
+}
@@ -0,0 +1,56 @@
+package abspath
+
+import (
+	"os"
+)
+
+// lstatMode returns the file mode of the path without following symbolic links.
+func (a AbsPath) lstatMode() (_ os.FileMode, err error) {
+	defer annotate(&err, "stat", a)
+	s, err := fsys().Lstat(a.underlying)
+	if err != nil {
+		return 0, err
+	}
+	return s.Mode(), nil
+}
+
+// IsDevice returns whether the path is a device file, either a character device or a block device.  Symbolic links
+// are not followed.  An error is returned when the file info cannot be obtained.
+//
+// Example:
+//	a, _ := abspath.New("/dev/null")
+//	dev, err := a.IsDevice() // => true, nil
+func (a AbsPath) IsDevice() (bool, error) {
+	m, err := a.lstatMode()
+	return m&os.ModeDevice != 0, err
+}
+
+// IsCharDevice returns whether the path is a character device such as a terminal or '/dev/null'.  Symbolic links are
+// not followed.
+func (a AbsPath) IsCharDevice() (bool, error) {
+	m, err := a.lstatMode()
+	return m&os.ModeDevice != 0 && m&os.ModeCharDevice != 0, err
+}
+
+// IsBlockDevice returns whether the path is a block device such as a disk.  Symbolic links are not followed.
+func (a AbsPath) IsBlockDevice() (bool, error) {
+	m, err := a.lstatMode()
+	return m&os.ModeDevice != 0 && m&os.ModeCharDevice == 0, err
+}
+
+// IsSocket returns whether the path is a Unix domain socket.  Symbolic links are not followed.
+func (a AbsPath) IsSocket() (bool, error) {
+	m, err := a.lstatMode()
+	return m&os.ModeSocket != 0, err
+}
+
+// IsNamedPipe returns whether the path is a named pipe (FIFO).  Symbolic links are not followed.
+//
+// Example:
+//	if fifo, _ := a.IsNamedPipe(); fifo {
+//		return nil // Reading a FIFO would block until a writer opens it
+//	}
+func (a AbsPath) IsNamedPipe() (bool, error) {
+	m, err := a.lstatMode()
+	return m&os.ModeNamedPipe != 0, err
+}
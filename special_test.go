@@ -0,0 +1,89 @@
+package abspath
+
+import (
+	"errors"
+	"net"
+	"os"
+	"runtime"
+	"testing"
+)
+
+type specialChecks struct {
+	device, char, block, socket, pipe bool
+}
+
+func checkSpecial(t *testing.T, a AbsPath, want specialChecks) {
+	t.Helper()
+	for _, c := range []struct {
+		what string
+		f    func() (bool, error)
+		want bool
+	}{
+		{"IsDevice", a.IsDevice, want.device},
+		{"IsCharDevice", a.IsCharDevice, want.char},
+		{"IsBlockDevice", a.IsBlockDevice, want.block},
+		{"IsSocket", a.IsSocket, want.socket},
+		{"IsNamedPipe", a.IsNamedPipe, want.pipe},
+	} {
+		have, err := c.f()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != c.want {
+			t.Errorf("%s() of %s should be %v but got %v", c.what, a, c.want, have)
+		}
+	}
+}
+
+func TestSpecialFileRegular(t *testing.T) {
+	root := makeTree(t, map[string]string{"file": "hello", "dir/file": ""})
+	checkSpecial(t, root.Join("file"), specialChecks{})
+	checkSpecial(t, root.Join("dir"), specialChecks{})
+
+	for _, f := range []func() (bool, error){
+		root.Join("missing").IsDevice,
+		root.Join("missing").IsCharDevice,
+		root.Join("missing").IsBlockDevice,
+		root.Join("missing").IsSocket,
+		root.Join("missing").IsNamedPipe,
+	} {
+		if ok, err := f(); ok || !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Not-exist error should be returned but got %v, %v", ok, err)
+		}
+	}
+}
+
+func TestSpecialFileDevice(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" && runtime.GOOS != "freebsd" {
+		t.Skip("/dev/null is not a character device on", runtime.GOOS)
+	}
+	a, err := New("/dev/null")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSpecial(t, a, specialChecks{device: true, char: true})
+}
+
+func TestSpecialFileFIFOAndSocket(t *testing.T) {
+	if isWindows || runtime.GOOS == "plan9" || runtime.GOOS == "js" {
+		t.Skip("FIFO and Unix domain socket files are not available on", runtime.GOOS)
+	}
+	root := makeTree(t, map[string]string{})
+
+	fifo := root.Join("fifo")
+	if err := fifo.Mkfifo(0600); err != nil {
+		t.Fatal(err)
+	}
+	checkSpecial(t, fifo, specialChecks{pipe: true})
+
+	sock := root.Join("sock")
+	if err := sock.ValidateUnixSocket(); err != nil {
+		t.Skip(err)
+	}
+	l, err := net.Listen("unix", sock.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	checkSpecial(t, sock, specialChecks{socket: true})
+}
@@ -0,0 +1,57 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// SwapWith exchanges the entries at the path and the other path.  Both paths must exist and they can be files or
+// directories.  On Linux it is done atomically with renameat2(2) and RENAME_EXCHANGE flag.  On macOS it is done
+// atomically with renamex_np(2) and RENAME_SWAP flag.  On other platforms, or when the filesystem does not support
+// the atomic exchange, the entries are exchanged with three renames via a temporary name in the same directory.  In the
+// case, other processes may observe the intermediate state, but the renames are rolled back on failure so that both
+// entries are kept at their original paths.
+//
+// Example:
+//	current, _ := abspath.New("/srv/app/current")
+//	next, _ := abspath.New("/srv/app/next")
+//	if err := current.SwapWith(next); err != nil {
+//		panic(err)
+//	}
+func (a AbsPath) SwapWith(b AbsPath) (err error) {
+	defer annotate(&err, "swap", a, b)
+	for _, p := range []AbsPath{a, b} {
+		if _, err := fsys().Lstat(p.underlying); err != nil {
+			return err
+		}
+	}
+	return audit("swap", "", func() error {
+		if usesOSFS() {
+			if ok, err := swapAtomic(a.underlying, b.underlying); ok {
+				return err
+			}
+		}
+		return swapFallback(a.underlying, b.underlying)
+	}, a.underlying, b.underlying)
+}
+
+// swapFallback exchanges two paths with three renames.  It rolls back renames on failure.
+func swapFallback(a, b string) error {
+	fs := fsys()
+	tmp := fmt.Sprintf("%s.swap-%d-%d", a, os.Getpid(), time.Now().UnixNano())
+	if err := fs.Rename(a, tmp); err != nil {
+		return err
+	}
+	if err := fs.Rename(b, a); err != nil {
+		fs.Rename(tmp, a)
+		return err
+	}
+	if err := fs.Rename(tmp, b); err != nil {
+		if fs.Rename(a, b) == nil {
+			fs.Rename(tmp, a)
+		}
+		return err
+	}
+	return nil
+}
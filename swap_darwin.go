@@ -0,0 +1,20 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// swapAtomic exchanges two paths atomically.  The first return value is false when the atomic exchange is not
+// supported.
+func swapAtomic(a, b string) (bool, error) {
+	err := unix.RenamexNp(a, b, unix.RENAME_SWAP)
+	if err == unix.ENOTSUP || err == unix.EINVAL || err == unix.ENOSYS {
+		return false, nil
+	}
+	if err != nil {
+		return true, &os.LinkError{Op: "renamex_np", Old: a, New: b, Err: err}
+	}
+	return true, nil
+}
@@ -0,0 +1,20 @@
+package abspath
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// swapAtomic exchanges two paths atomically.  The first return value is false when the atomic exchange is not
+// supported.
+func swapAtomic(a, b string) (bool, error) {
+	err := unix.Renameat2(unix.AT_FDCWD, a, unix.AT_FDCWD, b, unix.RENAME_EXCHANGE)
+	if err == unix.ENOSYS || err == unix.EINVAL || err == unix.ENOTSUP {
+		return false, nil
+	}
+	if err != nil {
+		return true, &os.LinkError{Op: "renameat2", Old: a, New: b, Err: err}
+	}
+	return true, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package abspath
+
+// swapAtomic exchanges two paths atomically.  The first return value is false when the atomic exchange is not
+// supported.
+func swapAtomic(a, b string) (bool, error) {
+	return false, nil
+}
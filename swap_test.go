@@ -0,0 +1,67 @@
+package abspath
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func assertContent(t *testing.T, p AbsPath, expected string) {
+	t.Helper()
+	b, err := ioutil.ReadFile(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != expected {
+		t.Errorf("Expected content %q for %s but actually %q", expected, p, b)
+	}
+}
+
+func TestSwapWith(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"a":       "a",
+		"b":       "b",
+		"dir1/f":  "1",
+		"dir2/f":  "2",
+		"dir2/f2": "",
+	})
+
+	if err := root.Join("a").SwapWith(root.Join("b")); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("a"), "b")
+	assertContent(t, root.Join("b"), "a")
+
+	if err := root.Join("dir1").SwapWith(root.Join("dir2")); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("dir1", "f"), "2")
+	assertContent(t, root.Join("dir2", "f"), "1")
+
+	if err := root.Join("a").SwapWith(root.Join("not-exist")); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+	assertContent(t, root.Join("a"), "b")
+}
+
+func TestSwapFallback(t *testing.T) {
+	root := makeTree(t, map[string]string{"a": "a", "b": "b"})
+
+	if err := swapFallback(root.Join("a").String(), root.Join("b").String()); err != nil {
+		t.Fatal(err)
+	}
+	assertContent(t, root.Join("a"), "b")
+	assertContent(t, root.Join("b"), "a")
+
+	if err := swapFallback(root.Join("a").String(), root.Join("not-exist").String()); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+	assertContent(t, root.Join("a"), "b")
+
+	fs, err := ioutil.ReadDir(root.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 2 {
+		t.Errorf("Temporary file should be cleaned up: %v", fs)
+	}
+}
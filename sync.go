@@ -0,0 +1,293 @@
+package abspath
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SyncOptions is a set of options for Sync() function.
+type SyncOptions struct {
+	// Delete makes Sync() remove entries in the destination which do not exist in the source.
+	Delete bool
+	// Exclude is a list of glob patterns matched against slash-separated paths relative to the roots and base names
+	// of entries.  Braces like '*.{o,a}' are expanded.  Matched entries are neither copied nor deleted, and matched
+	// directories are not entered.
+	Exclude []string
+	// DryRun makes Sync() only report what would be done without touching the filesystem.
+	DryRun bool
+	// Checksum makes Sync() compare file contents to detect changes.  By default, files are considered changed when
+	// their sizes or modification times differ.
+	Checksum bool
+	// RateLimiter limits the rate of syncing.  Each entry visited in the source and the destination is counted as one
+	// operation and bytes are counted while reading copied files.  When it is nil, the rate is not limited.
+	RateLimiter *RateLimiter
+	// Symlinks is the policy of how Sync() handles symbolic links in the source.  By default, they are copied as
+	// symbolic links with the same targets.
+	Symlinks SymlinkPolicy
+}
+
+// SyncResult is a report of operations done by Sync() function.  All paths are in the destination directory.
+type SyncResult struct {
+	// Created is a list of directories created in the destination.
+	Created []AbsPath
+	// Copied is a list of files and symbolic links copied to the destination.
+	Copied []AbsPath
+	// Deleted is a list of entries removed from the destination.
+	Deleted []AbsPath
+}
+
+// Sync mirrors the directory tree at src to dst in one way.  New or changed files in src are copied to dst and
+// directories missing in dst are created.  Entries in dst whose types conflict with src are replaced.  Modification
+// times of copied files are preserved so that unchanged files are skipped on next sync.  By default, symbolic links
+// are copied as symbolic links.  It can be changed with Symlinks option.  When dst is a symbolic link to a directory,
+// the directory is synced and the link is kept.  opts can be nil.
+//
+// Example:
+//	src, _ := abspath.ExpandFrom("~/Documents")
+//	dst, _ := abspath.New("/mnt/backup/Documents")
+//	res, err := abspath.Sync(src, dst, &abspath.SyncOptions{Delete: true, Exclude: []string{".git"}})
+//	if err != nil {
+//		panic(err)
+//	}
+//	fmt.Println(len(res.Copied), "files were copied")
+func Sync(src, dst AbsPath, opts *SyncOptions) (*SyncResult, error) {
+	return SyncContext(context.Background(), src, dst, opts)
+}
+
+// SyncContext is the same as Sync() but accepts a context.  Syncing is stopped between entries and between chunks of
+// copied files when the context is done.  Entries synced before the cancellation are left in dst.
+func SyncContext(ctx context.Context, src, dst AbsPath, opts *SyncOptions) (_ *SyncResult, err error) {
+	defer annotate(&err, "sync", src, dst)
+	ctx, span := startSpan(ctx, "abspath.Sync", pathAttr("abspath.path", src), pathAttr("abspath.dst", dst))
+	var copied int64
+	defer func() {
+		span.SetAttributes(int64Attr("abspath.bytes", copied))
+		span.End(err)
+	}()
+
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+
+	s, err := fsys().Stat(src.underlying)
+	if err != nil {
+		return nil, err
+	}
+	if !s.IsDir() {
+		return nil, fmt.Errorf("cannot sync '%s' since it is not a directory", src.underlying)
+	}
+
+	r := &SyncResult{}
+	remove := func(p AbsPath) error {
+		r.Deleted = append(r.Deleted, p)
+		if opts.DryRun {
+			return nil
+		}
+		return fsys().RemoveAll(p.underlying)
+	}
+
+	// Entries under directories created by this sync never exist in the destination.  On dry run, looking them up would
+	// see the file or the symbolic link which would be replaced by the directory
+	created := map[string]bool{}
+	var dirs []createdDir
+	w := walk
+	if opts.Symlinks.follows() {
+		w = walkFollow
+	}
+	err = w(src.underlying, opts.RateLimiter.walkFunc(ctx, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src.underlying, p)
+		if err != nil {
+			return err
+		}
+		if rel != "." {
+			m, err := matchPatterns(opts.Exclude, filepath.ToSlash(rel))
+			if err != nil {
+				return err
+			}
+			if m {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+
+		to := dst.Join(rel)
+		var d os.FileInfo
+		exists := false
+		if !created[filepath.Dir(to.underlying)] {
+			stat := fsys().Lstat
+			if rel == "." {
+				// The destination root is followed so that a symbolic link to a directory is synced instead of replaced
+				stat = fsys().Stat
+			}
+			d, err = stat(to.underlying)
+			exists = err == nil
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if exists && d.IsDir() {
+				return nil
+			}
+			if exists && rel == "." {
+				return fmt.Errorf("cannot sync to '%s' since it is not a directory", dst.underlying)
+			}
+			if exists {
+				if err := remove(to); err != nil {
+					return err
+				}
+			}
+			r.Created = append(r.Created, to)
+			created[to.underlying] = true
+			if opts.DryRun {
+				return nil
+			}
+			dirs = append(dirs, createdDir{to.underlying, info})
+			return fsys().MkdirAll(to.underlying, 0700)
+		case mode&os.ModeSymlink != 0:
+			target, err := opts.Symlinks.target(p, src.underlying, dst.underlying)
+			if err != nil {
+				return err
+			}
+			if exists && d.Mode()&os.ModeSymlink != 0 {
+				if t, err := fsys().Readlink(to.underlying); err == nil && t == target {
+					return nil
+				}
+			}
+			if exists {
+				if err := remove(to); err != nil {
+					return err
+				}
+			}
+			r.Copied = append(r.Copied, to)
+			if opts.DryRun {
+				return nil
+			}
+			return fsys().Symlink(target, to.underlying)
+		case mode.IsRegular():
+			if exists && d.Mode().IsRegular() {
+				changed, err := fileChanged(ctx, AbsPath{p}, info, to, d, opts.Checksum)
+				if err != nil {
+					return err
+				}
+				if !changed {
+					return nil
+				}
+			} else if exists {
+				if err := remove(to); err != nil {
+					return err
+				}
+			}
+			r.Copied = append(r.Copied, to)
+			if opts.DryRun {
+				return nil
+			}
+			c := newCopier(ctx, &CopyOptions{RateLimiter: opts.RateLimiter})
+			if err := c.copyFile(p, to.underlying, info, nil); err != nil {
+				return err
+			}
+			copied += info.Size()
+			return nil
+		default:
+			return fmt.Errorf("cannot sync '%s' since it is not a regular file, directory nor symbolic link", p)
+		}
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if err := restoreDirs(dirs); err != nil {
+		return nil, err
+	}
+
+	if !opts.Delete {
+		return r, nil
+	}
+
+	if _, err := fsys().Stat(dst.underlying); err != nil {
+		if opts.DryRun && os.IsNotExist(err) {
+			return r, nil
+		}
+		return nil, err
+	}
+
+	// Walking does not enter the destination root when it is a symbolic link
+	root := dst
+	if d, err := fsys().Lstat(dst.underlying); err == nil && d.Mode()&os.ModeSymlink != 0 {
+		if root, err = dst.Resolve(&ResolveOptions{MustExist: true}); err != nil {
+			return nil, err
+		}
+	}
+
+	stat := opts.Symlinks.stat()
+	err = walk(root.underlying, opts.RateLimiter.walkFunc(ctx, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root.underlying, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		m, err := matchPatterns(opts.Exclude, filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		if m {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if s, err := stat(src.Join(rel).underlying); err == nil {
+			if info.IsDir() && !s.IsDir() {
+				// The directory was already replaced while copying (or would be replaced on dry run)
+				return filepath.SkipDir
+			}
+			return nil
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+
+		if err := remove(dst.Join(rel)); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func fileChanged(ctx context.Context, src AbsPath, srcInfo os.FileInfo, dst AbsPath, dstInfo os.FileInfo, checksum bool) (bool, error) {
+	if srcInfo.Size() != dstInfo.Size() {
+		return true, nil
+	}
+	if checksum {
+		eq, err := src.ContentEqualContext(ctx, dst)
+		return !eq, err
+	}
+	return !srcInfo.ModTime().Equal(dstInfo.ModTime()), nil
+}
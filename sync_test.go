@@ -0,0 +1,233 @@
+package abspath
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func relPaths(t *testing.T, root AbsPath, paths []AbsPath) []string {
+	ss := make([]string, 0, len(paths))
+	for _, p := range paths {
+		r, err := root.Rel(p.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ss = append(ss, filepath.ToSlash(r))
+	}
+	sort.Strings(ss)
+	return ss
+}
+
+func assertPaths(t *testing.T, what string, root AbsPath, actual []AbsPath, expected ...string) {
+	t.Helper()
+	a := relPaths(t, root, actual)
+	if len(a) != len(expected) {
+		t.Errorf("Expected %s %v but actually %v", what, expected, a)
+		return
+	}
+	for i := range a {
+		if a[i] != expected[i] {
+			t.Errorf("Expected %s %v but actually %v", what, expected, a)
+			return
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	src := makeTree(t, map[string]string{
+		"a.txt":          "a",
+		"sub/b.txt":      "b",
+		"sub/c/d.txt":    "d",
+		"conflict":       "file in src",
+		"skip/e.txt":     "e",
+		"unchanged.txt":  "same",
+		"changed.txt":    "new content",
+		"empty/":         "",
+		"sub/ignore.log": "log",
+	})
+	dst := makeTree(t, map[string]string{
+		"conflict/x.txt": "dir in dst",
+		"extra.txt":      "extra",
+		"extra/y.txt":    "y",
+		"skip/keep.txt":  "kept",
+		"changed.txt":    "old content",
+	})
+
+	info, err := os.Stat(src.Join("unchanged.txt").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := copyFile(context.Background(), src.Join("unchanged.txt").String(), dst.Join("unchanged.txt").String(), info); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &SyncOptions{
+		Delete:  true,
+		Exclude: []string{"skip", "*.log"},
+		DryRun:  true,
+	}
+
+	check := func(r *SyncResult) {
+		t.Helper()
+		assertPaths(t, "created", dst, r.Created, "empty", "sub", "sub/c")
+		assertPaths(t, "copied", dst, r.Copied, "a.txt", "changed.txt", "conflict", "sub/b.txt", "sub/c/d.txt")
+		assertPaths(t, "deleted", dst, r.Deleted, "conflict", "extra", "extra.txt")
+	}
+
+	r, err := Sync(src, dst, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(r)
+	if _, err := os.Stat(dst.Join("a.txt").String()); !os.IsNotExist(err) {
+		t.Errorf("Dry run must not copy files: %v", err)
+	}
+	if _, err := os.Stat(dst.Join("extra.txt").String()); err != nil {
+		t.Errorf("Dry run must not delete files: %v", err)
+	}
+
+	opts.DryRun = false
+	r, err = Sync(src, dst, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(r)
+
+	for _, c := range []struct {
+		path    string
+		content string
+	}{
+		{"a.txt", "a"},
+		{"sub/b.txt", "b"},
+		{"sub/c/d.txt", "d"},
+		{"conflict", "file in src"},
+		{"changed.txt", "new content"},
+		{"unchanged.txt", "same"},
+		{"skip/keep.txt", "kept"},
+	} {
+		b, err := ioutil.ReadFile(dst.Join(filepath.FromSlash(c.path)).String())
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if string(b) != c.content {
+			t.Errorf("Expected %q for %s but actually %q", c.content, c.path, b)
+		}
+	}
+	for _, p := range []string{"extra.txt", "extra", "skip/e.txt", "sub/ignore.log"} {
+		if _, err := os.Stat(dst.Join(filepath.FromSlash(p)).String()); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist in destination: %v", p, err)
+		}
+	}
+
+	r, err = Sync(src, dst, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Created) != 0 || len(r.Copied) != 0 || len(r.Deleted) != 0 {
+		t.Errorf("Nothing should be done on second sync but actually %+v", r)
+	}
+
+	r, err = Sync(src, dst, &SyncOptions{Checksum: true, Exclude: opts.Exclude})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Copied) != 0 {
+		t.Errorf("Nothing should be copied with checksum but actually %v", r.Copied)
+	}
+
+	if _, err := Sync(src.Join("a.txt"), dst, nil); err == nil {
+		t.Errorf("Source which is not a directory must cause an error")
+	}
+	if _, err := Sync(src.Join("not-exist"), dst, nil); err == nil {
+		t.Errorf("Not existing source must cause an error")
+	}
+}
+
+func TestSyncNewDestination(t *testing.T) {
+	src := makeTree(t, map[string]string{"a/b.txt": "b"})
+	dst := makeTree(t, nil).Join("new")
+
+	r, err := Sync(src, dst, &SyncOptions{Delete: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPaths(t, "created", dst, r.Created, ".", "a")
+
+	if _, err := Sync(src, dst, &SyncOptions{Delete: true}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(dst.Join("a", "b.txt").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "b" {
+		t.Errorf("Unexpected content %q", b)
+	}
+}
+
+func TestSyncDryRunReplacingFileWithDirectory(t *testing.T) {
+	src := makeTree(t, map[string]string{"d/a/x.txt": "x", "d/a/y/z.txt": "z"})
+	dst := makeTree(t, map[string]string{"d/a": "file in dst"})
+
+	r, err := Sync(src, dst, &SyncOptions{Delete: true, DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPaths(t, "created", dst, r.Created, "d/a", "d/a/y")
+	assertPaths(t, "copied", dst, r.Copied, "d/a/x.txt", "d/a/y/z.txt")
+	assertPaths(t, "deleted", dst, r.Deleted, "d/a")
+	assertContent(t, dst.Join("d", "a"), "file in dst")
+
+	r2, err := Sync(src, dst, &SyncOptions{Delete: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, r2) {
+		t.Errorf("Dry run should report %+v but actually %+v", r2, r)
+	}
+	assertContent(t, dst.Join("d", "a", "y", "z.txt"), "z")
+}
+
+func TestSyncSymlinkedDestination(t *testing.T) {
+	src := makeTree(t, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	root := makeTree(t, map[string]string{"real/extra.txt": "extra", "real/sub/c.txt": "c"})
+	real := root.Join("real")
+	link := root.Join("link")
+	if err := os.Symlink(real.String(), link.String()); err != nil {
+		t.Skip("Symbolic link is not available:", err)
+	}
+
+	r, err := Sync(src, link, &SyncOptions{Delete: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPaths(t, "created", link, r.Created)
+	assertPaths(t, "copied", link, r.Copied, "a.txt", "sub/b.txt")
+	assertPaths(t, "deleted", link, r.Deleted, "extra.txt", "sub/c.txt")
+
+	s, err := os.Lstat(link.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Symbolic link at destination root should be kept: %s", s.Mode())
+	}
+	assertContent(t, real.Join("a.txt"), "a")
+	assertContent(t, real.Join("sub", "b.txt"), "b")
+	for _, p := range []AbsPath{real.Join("extra.txt"), real.Join("sub", "c.txt")} {
+		if _, err := os.Lstat(p.String()); !os.IsNotExist(err) {
+			t.Errorf("%s should be deleted: %v", p, err)
+		}
+	}
+
+	if _, err := Sync(src, src.Join("a.txt"), nil); err == nil {
+		t.Error("Destination which is not a directory must cause an error")
+	}
+	assertContent(t, src.Join("a.txt"), "a")
+}
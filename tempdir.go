@@ -0,0 +1,65 @@
+package abspath
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// maxTempAttempts is the maximum number of attempts to create a temporary directory with a random name.
+const maxTempAttempts = 10000
+
+func randomTempSuffix() (string, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(uint64(binary.LittleEndian.Uint32(b[:])), 10), nil
+}
+
+// MkdirTemp creates a new temporary directory under the directory and returns its path with a cleanup function which
+// removes the temporary directory and all its contents.  The name of the directory is generated by replacing the last
+// '*' in the pattern with a random string.  When the pattern does not contain '*', the random string is appended.  The
+// pattern must not contain path separators.  The directory is created with permission 0700.  Unlike os.MkdirTemp(), the
+// parent is given explicitly so that the temporary directory can be placed on the same filesystem as the destination
+// for atomic renames.  The returned cleanup function is never nil so that it can be deferred safely even on error.
+//
+// Example:
+//	dest, _ := abspath.New("/srv/www/site")
+//	staging, cleanup, err := dest.Dir().MkdirTemp(".staging-*")
+//	defer cleanup()
+//	if err != nil {
+//		panic(err)
+//	}
+//	// Build contents in staging, then rename it to dest
+func (a AbsPath) MkdirTemp(pattern string) (_ AbsPath, _ func() error, err error) {
+	defer annotate(&err, "create temporary directory in", a)
+	nop := func() error { return nil }
+	if strings.IndexFunc(pattern, isSeparatorRune) >= 0 {
+		return AbsPath{""}, nop, fmt.Errorf("pattern %q for temporary directory must not contain path separator", pattern)
+	}
+	prefix, suffix := pattern, ""
+	if i := strings.LastIndexByte(pattern, '*'); i >= 0 {
+		prefix, suffix = pattern[:i], pattern[i+1:]
+	}
+
+	for i := 0; i < maxTempAttempts; i++ {
+		r, err := randomTempSuffix()
+		if err != nil {
+			return AbsPath{""}, nop, err
+		}
+		p := a.JoinOne(prefix + r + suffix)
+		err = fsys().Mkdir(p.underlying, 0700)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return AbsPath{""}, nop, err
+		}
+		return p, func() error { return fsys().RemoveAll(p.underlying) }, nil
+	}
+	return AbsPath{""}, nop, fmt.Errorf("could not create temporary directory under '%s' with pattern %q", a.underlying, pattern)
+}
@@ -0,0 +1,87 @@
+package abspath
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMkdirTemp(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+
+	for _, tc := range []struct {
+		pattern string
+		prefix  string
+		suffix  string
+	}{
+		{"staging-*", "staging-", ""},
+		{"*.tmp", "", ".tmp"},
+		{"a*b*c", "a*b", "c"},
+		{"plain", "plain", ""},
+		{"", "", ""},
+	} {
+		p, cleanup, err := root.MkdirTemp(tc.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Dir() != root {
+			t.Errorf("Temporary directory should be created under %s but got %s", root, p)
+		}
+		name := p.Base().String()
+		if !strings.HasPrefix(name, tc.prefix) || !strings.HasSuffix(name, tc.suffix) || len(name) <= len(tc.prefix)+len(tc.suffix) {
+			t.Errorf("Name %q does not match to pattern %q", name, tc.pattern)
+		}
+		s, err := os.Stat(p.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.IsDir() {
+			t.Errorf("%s is not a directory", p)
+		}
+
+		if err := ioutil.WriteFile(p.Join("file").String(), []byte("hello"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := cleanup(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(p.String()); !os.IsNotExist(err) {
+			t.Errorf("Temporary directory should be removed by cleanup: %v", err)
+		}
+		if err := cleanup(); err != nil {
+			t.Errorf("Cleanup twice should not fail: %v", err)
+		}
+	}
+
+	a, c1, err := root.MkdirTemp("same")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c1()
+	b, c2, err := root.MkdirTemp("same")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2()
+	if a == b {
+		t.Errorf("Temporary directories should be unique: %s", a)
+	}
+}
+
+func TestMkdirTempError(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+
+	_, cleanup, err := root.MkdirTemp("foo/*")
+	if err == nil {
+		t.Fatal("Error should occur for pattern with separator")
+	}
+	if cleanup == nil || cleanup() != nil {
+		t.Error("No-op cleanup function should be returned on error")
+	}
+
+	if _, _, err := root.Join("missing").MkdirTemp("*"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
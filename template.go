@@ -0,0 +1,86 @@
+package abspath
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// templatePath converts a value passed to template functions into AbsPath.  AbsPath values, strings and values
+// implementing fmt.Stringer are accepted.  A string must represent an absolute path.
+func templatePath(v interface{}) (AbsPath, error) {
+	switch v := v.(type) {
+	case AbsPath:
+		return v, nil
+	case *AbsPath:
+		return *v, nil
+	case string:
+		return New(v)
+	case fmt.Stringer:
+		return New(v.String())
+	default:
+		return AbsPath{""}, fmt.Errorf("cannot use value of type %T as absolute path", v)
+	}
+}
+
+// TemplateFuncs returns functions to manipulate paths in text/template and html/template templates.  Arguments of
+// paths can be AbsPath values or strings of absolute paths.  Functions return an error when an argument is not an
+// absolute path so that templates never produce relative paths by mistake.
+//
+//	abs "/path/to/file"    => AbsPath of "/path/to/file"
+//	expand "~/Documents"   => AbsPath of "~/Documents" expanded by ExpandFrom()
+//	join $p "a" "b"        => $p.Join("a", "b")
+//	base $p                => Base name of $p as string
+//	dir $p                 => $p.Dir()
+//	ext $p                 => $p.Ext()
+//	rel $base $p           => $p relative to $base as string
+//
+// Example:
+//	t := template.Must(template.New("config").Funcs(abspath.TemplateFuncs()).Parse(
+//		`log_dir = "{{ join .Root "logs" }}"`,
+//	))
+//	t.Execute(os.Stdout, map[string]interface{}{"Root": root})
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"abs":    New,
+		"expand": ExpandFrom,
+		"join": func(p interface{}, elem ...string) (AbsPath, error) {
+			a, err := templatePath(p)
+			if err != nil {
+				return AbsPath{""}, err
+			}
+			return a.Join(elem...), nil
+		},
+		"base": func(p interface{}) (string, error) {
+			a, err := templatePath(p)
+			if err != nil {
+				return "", err
+			}
+			return a.Base().String(), nil
+		},
+		"dir": func(p interface{}) (AbsPath, error) {
+			a, err := templatePath(p)
+			if err != nil {
+				return AbsPath{""}, err
+			}
+			return a.Dir(), nil
+		},
+		"ext": func(p interface{}) (string, error) {
+			a, err := templatePath(p)
+			if err != nil {
+				return "", err
+			}
+			return a.Ext(), nil
+		},
+		"rel": func(base, p interface{}) (string, error) {
+			b, err := templatePath(base)
+			if err != nil {
+				return "", err
+			}
+			a, err := templatePath(p)
+			if err != nil {
+				return "", err
+			}
+			return b.Rel(a.underlying)
+		},
+	}
+}
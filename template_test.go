@@ -0,0 +1,60 @@
+package abspath
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	root, _ := FromSlash(fixAbsPath("/path/to/project"))
+	data := map[string]interface{}{
+		"Root": root,
+		"File": root.Join("src", "main.go").String(),
+		"Rel":  "relative/path",
+	}
+
+	for _, c := range []struct {
+		tmpl string
+		want string
+	}{
+		{`{{ join .Root "logs" "app.log" }}`, root.Join("logs", "app.log").String()},
+		{`{{ base .File }}`, "main.go"},
+		{`{{ dir .File }}`, root.Join("src").String()},
+		{`{{ ext .File }}`, ".go"},
+		{`{{ rel .Root .File }}`, filepath.Join("src", "main.go")},
+		{`{{ .File | rel .Root }}`, filepath.Join("src", "main.go")},
+		{`{{ abs .File | dir | base }}`, "src"},
+		{`{{ (expand "~/foo").IsRoot }}`, "false"},
+	} {
+		tmpl, err := template.New("test").Funcs(TemplateFuncs()).Parse(c.tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			t.Errorf("Template %q failed: %v", c.tmpl, err)
+			continue
+		}
+		if b.String() != c.want {
+			t.Errorf("Template %q should output %q but got %q", c.tmpl, c.want, b.String())
+		}
+	}
+
+	for _, tmpl := range []string{
+		`{{ abs .Rel }}`,
+		`{{ join .Rel "foo" }}`,
+		`{{ base 42 }}`,
+		`{{ rel .Root .Rel }}`,
+	} {
+		tmpl, err := template.New("test").Funcs(TemplateFuncs()).Parse(tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err == nil {
+			t.Errorf("Template %q should fail but output %q", tmpl.Root.String(), b.String())
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package tomlfile provides functions to read and write TOML files at abspath.AbsPath.  It is separated from abspath
+// package so that abspath does not depend on a TOML library.  Errors on decoding and encoding are returned as
+// *abspath.FileFormatError which annotates the error with the path.
+//
+// Ref: https://github.com/BurntSushi/toml
+package tomlfile
+
+import (
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/rhysd/abspath"
+)
+
+// Read reads the TOML file at the path and decodes it into v as toml.Unmarshal() does.
+//
+// Example:
+//	var c Config
+//	a, _ := abspath.ExpandFrom("~/.config/myapp/config.toml")
+//	err := tomlfile.Read(a, &c)
+func Read(a abspath.AbsPath, v interface{}) error {
+	return a.ReadFormat("TOML", v, toml.Unmarshal)
+}
+
+// Write encodes v as TOML and writes it to the file at the path.  When the file does not exist, it is created with the
+// permission.  Otherwise the file is truncated.
+//
+// Example:
+//	err := tomlfile.Write(a, &c, 0644)
+func Write(a abspath.AbsPath, v interface{}, perm os.FileMode) error {
+	return a.WriteFormat("TOML", v, toml.Marshal, perm)
+}
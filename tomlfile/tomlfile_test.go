@@ -0,0 +1,61 @@
+package tomlfile
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/rhysd/abspath"
+)
+
+type config struct {
+	Name  string   `toml:"name"`
+	Count int      `toml:"count"`
+	Tags  []string `toml:"tags"`
+}
+
+func tempDir(t *testing.T) abspath.AbsPath {
+	d, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := abspath.New(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestReadWrite(t *testing.T) {
+	p := tempDir(t).Join("config.toml")
+	want := config{"foo", 3, []string{"a", "b"}}
+	if err := Write(p, &want, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var have config
+	if err := Read(p, &have); err != nil {
+		t.Fatal(err)
+	}
+	if have.Name != want.Name || have.Count != want.Count || len(have.Tags) != 2 || have.Tags[1] != "b" {
+		t.Errorf("Wanted %+v but have %+v", want, have)
+	}
+}
+
+func TestReadError(t *testing.T) {
+	p := tempDir(t).Join("broken.toml")
+	if err := ioutil.WriteFile(p.String(), []byte("name = [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var c config
+	err := Read(p, &c)
+	var ferr *abspath.FileFormatError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("FileFormatError should be returned but %T: %v", err, err)
+	}
+	if ferr.Path != p || ferr.Op != "decode" || ferr.Format != "TOML" {
+		t.Errorf("Unexpected error: %+v", ferr)
+	}
+}
@@ -0,0 +1,87 @@
+package abspath
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// largeFileSpanSize is the minimum size of a file for which a child span is started while copying it.  Spans for
+// smaller files would only make traces noisy.
+const largeFileSpanSize = 1024 * 1024
+
+// SpanAttr is an attribute of a tracing span.  Value is a string, an int64 or a bool.
+type SpanAttr struct {
+	// Key is a key of the attribute such as "abspath.path".
+	Key string
+	// Value is a value of the attribute.
+	Value interface{}
+}
+
+// Span is a tracing span started by Tracer.
+type Span interface {
+	// SetAttributes adds the attributes to the span.
+	SetAttributes(attrs ...SpanAttr)
+	// End finishes the span with the result of the operation.  err is nil when the operation succeeded.
+	End(err error)
+}
+
+// Tracer is an interface to start tracing spans around long-running operations of this package.  It can be
+// implemented with a tracing library such as OpenTelemetry.  See the oteltrace package for the implementation with
+// OpenTelemetry.  Since operations may be performed concurrently, Start must be safe for concurrent use.
+type Tracer interface {
+	// Start starts a span with the name and the attributes as a child of the span in the context, and returns the
+	// context containing the new span.
+	Start(ctx context.Context, name string, attrs ...SpanAttr) (context.Context, Span)
+}
+
+type tracerHolder struct {
+	t Tracer
+}
+
+var currentTracer atomic.Value
+
+func init() {
+	currentTracer.Store(tracerHolder{})
+}
+
+func tracer() Tracer {
+	return currentTracer.Load().(tracerHolder).t
+}
+
+// SetTracer sets the tracer and returns the previous one.  When nil is given, no span is started.  Spans are started
+// for Walk() (including its variants), CopyDir(), Sync() and Hash() with attributes of paths and transferred bytes.
+// While copying, a child span is started for each file larger than 1MiB.  Methods accepting a context start spans as
+// children of the span in the context so that the spans are connected to distributed traces of the caller.
+//
+// Example:
+//	prev := abspath.SetTracer(oteltrace.New(otel.Tracer("myapp")))
+//	defer abspath.SetTracer(prev)
+//
+//	// Spans are put under the span of the request
+//	err := src.CopyDirContext(r.Context(), dst, nil)
+func SetTracer(t Tracer) Tracer {
+	return currentTracer.Swap(tracerHolder{t}).(tracerHolder).t
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...SpanAttr) {}
+func (noopSpan) End(error)                 {}
+
+// startSpan starts a span with the current tracer.  When no tracer is set, it returns the context as it is and a span
+// which does nothing.
+func startSpan(ctx context.Context, name string, attrs ...SpanAttr) (context.Context, Span) {
+	t := tracer()
+	if t == nil {
+		return ctx, noopSpan{}
+	}
+	return t.Start(ctx, name, attrs...)
+}
+
+func pathAttr(key string, a AbsPath) SpanAttr {
+	return SpanAttr{key, a.underlying}
+}
+
+func int64Attr(key string, n int64) SpanAttr {
+	return SpanAttr{key, n}
+}
@@ -0,0 +1,154 @@
+package abspath
+
+import (
+	"context"
+	"crypto"
+	_ "crypto/sha256"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type spanKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]interface{}
+	ended  bool
+	err    error
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...SpanAttr) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attrs ...SpanAttr) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	s := &recordedSpan{name: name, parent: parent, attrs: map[string]interface{}{}}
+	s.SetAttributes(attrs...)
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (t *recordingTracer) find(name string) []*recordedSpan {
+	var ss []*recordedSpan
+	for _, s := range t.spans {
+		if s.name == name {
+			ss = append(ss, s)
+		}
+	}
+	return ss
+}
+
+func TestTracingWalkAndHash(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello", "d/b.txt": "world"})
+
+	tr := &recordingTracer{}
+	prev := SetTracer(tr)
+	defer SetTracer(prev)
+
+	if err := root.Walk(func(string, os.FileInfo, error) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	ss := tr.find("abspath.Walk")
+	if len(ss) != 1 {
+		t.Fatalf("One walk span should be started but got %d", len(ss))
+	}
+	if s := ss[0]; !s.ended || s.err != nil || s.attrs["abspath.path"] != root.String() || s.attrs["abspath.entries"] != int64(4) {
+		t.Errorf("Unexpected walk span: %+v", s)
+	}
+
+	if _, err := root.Join("a.txt").Hash(crypto.SHA256); err != nil {
+		t.Fatal(err)
+	}
+	ss = tr.find("abspath.Hash")
+	if len(ss) != 1 {
+		t.Fatalf("One hash span should be started but got %d", len(ss))
+	}
+	if s := ss[0]; !s.ended || s.err != nil || s.attrs["abspath.bytes"] != int64(5) {
+		t.Errorf("Unexpected hash span: %+v", s)
+	}
+
+	if _, err := root.Join("missing").Hash(crypto.SHA256); err == nil {
+		t.Fatal("Error did not occur")
+	}
+	if s := tr.find("abspath.Hash")[1]; !s.ended || s.err == nil {
+		t.Errorf("Error should be recorded in span: %+v", s)
+	}
+}
+
+func TestTracingCopyDirAndSync(t *testing.T) {
+	large := strings.Repeat("x", largeFileSpanSize)
+	root := makeTree(t, map[string]string{"src/small.txt": "hello", "src/d/large.txt": large})
+	src := root.Join("src")
+
+	tr := &recordingTracer{}
+	prev := SetTracer(tr)
+	defer SetTracer(prev)
+
+	ctx, parent := tr.Start(context.Background(), "request")
+	if err := src.CopyDirContext(ctx, root.Join("dst"), nil); err != nil {
+		t.Fatal(err)
+	}
+	ss := tr.find("abspath.CopyDir")
+	if len(ss) != 1 {
+		t.Fatalf("One copy span should be started but got %d", len(ss))
+	}
+	dir := ss[0]
+	if !dir.ended || dir.parent != parent || dir.attrs["abspath.bytes"] != int64(len(large)+5) {
+		t.Errorf("Unexpected copy span: %+v", dir)
+	}
+	ss = tr.find("abspath.CopyFile")
+	if len(ss) != 1 {
+		t.Fatalf("Span should be started only for the large file but got %d spans", len(ss))
+	}
+	if s := ss[0]; !s.ended || s.parent != dir || s.attrs["abspath.bytes"] != int64(len(large)) {
+		t.Errorf("Unexpected file span: %+v", s)
+	}
+
+	if _, err := Sync(src, root.Join("sync"), nil); err != nil {
+		t.Fatal(err)
+	}
+	ss = tr.find("abspath.Sync")
+	if len(ss) != 1 {
+		t.Fatalf("One sync span should be started but got %d", len(ss))
+	}
+	if s := ss[0]; !s.ended || s.attrs["abspath.bytes"] != int64(len(large)+5) {
+		t.Errorf("Unexpected sync span: %+v", s)
+	}
+	if s := tr.find("abspath.CopyFile")[1]; s.parent != ss[0] {
+		t.Errorf("File span should be a child of sync span: %+v", s)
+	}
+}
+
+func TestSetTracerNil(t *testing.T) {
+	prev := SetTracer(&recordingTracer{})
+	defer SetTracer(prev)
+
+	if SetTracer(nil) == nil {
+		t.Error("Previous tracer should be returned")
+	}
+	ctx := context.Background()
+	c, s := startSpan(ctx, "foo")
+	if c != ctx {
+		t.Error("Context should not be changed without tracer")
+	}
+	s.SetAttributes(SpanAttr{"foo", "bar"}) // Should not panic
+	s.End(nil)
+}
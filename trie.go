@@ -0,0 +1,117 @@
+package abspath
+
+// trieNode is a node of PathTrie.  Each node corresponds to a path component.
+type trieNode struct {
+	children map[string]*trieNode
+	value    interface{}
+	path     AbsPath
+	has      bool
+}
+
+// PathTrie is a trie keyed by paths.  Paths are split into components and each component is a edge of the trie.  It
+// can find the value for the longest ancestor of a path in time proportional to the depth of the path.  It is useful for
+// routing-style decisions such as which mount point or which policy applies to a file.  The zero value is an empty trie
+// ready to use.  It is not safe for concurrent use.
+type PathTrie struct {
+	root trieNode
+	size int
+}
+
+// keys returns the keys of the path in the trie.  The first key is the volume name.
+func trieKeys(p AbsPath) []string {
+	v, ss := p.SplitAll()
+	return append([]string{v}, ss...)
+}
+
+// Insert inserts the path with the value.  When the path is already in the trie, its value is overwritten.
+//
+// Example:
+//	var t abspath.PathTrie
+//	t.Insert(home, "home policy")
+//	t.Insert(home.Join("tmp"), "temporary policy")
+func (t *PathTrie) Insert(p AbsPath, v interface{}) {
+	n := &t.root
+	for _, k := range trieKeys(p) {
+		if n.children == nil {
+			n.children = map[string]*trieNode{}
+		}
+		c, ok := n.children[k]
+		if !ok {
+			c = &trieNode{}
+			n.children[k] = c
+		}
+		n = c
+	}
+	if !n.has {
+		t.size++
+	}
+	n.value = v
+	n.path = p
+	n.has = true
+}
+
+func (t *PathTrie) find(p AbsPath) *trieNode {
+	n := &t.root
+	for _, k := range trieKeys(p) {
+		c, ok := n.children[k]
+		if !ok {
+			return nil
+		}
+		n = c
+	}
+	return n
+}
+
+// Get returns the value of the path.  The second return value is false when the path is not in the trie.
+func (t *PathTrie) Get(p AbsPath) (interface{}, bool) {
+	n := t.find(p)
+	if n == nil || !n.has {
+		return nil, false
+	}
+	return n.value, true
+}
+
+// Delete removes the path from the trie.  It returns false when the path is not in the trie.
+func (t *PathTrie) Delete(p AbsPath) bool {
+	n := t.find(p)
+	if n == nil || !n.has {
+		return false
+	}
+	n.value = nil
+	n.has = false
+	t.size--
+	return true
+}
+
+// LongestPrefix finds the longest path in the trie which is the path itself or its ancestor.  Paths are compared by
+// components so '/foo' is not a prefix of '/foobar'.  It returns the found path and its value.  The third return value
+// is false when no such path is in the trie.
+//
+// Example:
+//	var t abspath.PathTrie
+//	t.Insert(mnt, "disk1")
+//	t.Insert(mnt.Join("usb"), "disk2")
+//	p, v, ok := t.LongestPrefix(mnt.Join("usb", "foo.txt")) // => mnt.Join("usb"), "disk2", true
+func (t *PathTrie) LongestPrefix(p AbsPath) (AbsPath, interface{}, bool) {
+	var found *trieNode
+	n := &t.root
+	for _, k := range trieKeys(p) {
+		c, ok := n.children[k]
+		if !ok {
+			break
+		}
+		n = c
+		if n.has {
+			found = n
+		}
+	}
+	if found == nil {
+		return AbsPath{""}, nil, false
+	}
+	return found.path, found.value, true
+}
+
+// Len returns the number of paths in the trie.
+func (t *PathTrie) Len() int {
+	return t.size
+}
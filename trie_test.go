@@ -0,0 +1,67 @@
+package abspath
+
+import (
+	"testing"
+)
+
+func TestPathTrie(t *testing.T) {
+	var tr PathTrie
+	ps := pathsFromSlash("/", "/mnt", "/mnt/usb", "/home/foo")
+	for i, p := range ps {
+		tr.Insert(p, i)
+	}
+	if tr.Len() != 4 {
+		t.Errorf("Expected 4 paths but actually %d", tr.Len())
+	}
+
+	for i, p := range ps {
+		v, ok := tr.Get(p)
+		if !ok || v != i {
+			t.Errorf("Expected (%d, true) for %s but actually (%v, %v)", i, p, v, ok)
+		}
+	}
+	if _, ok := tr.Get(pathsFromSlash("/home")[0]); ok {
+		t.Errorf("Intermediate node should not have value")
+	}
+
+	tr.Insert(ps[1], "overwritten")
+	if v, _ := tr.Get(ps[1]); v != "overwritten" || tr.Len() != 4 {
+		t.Errorf("Value should be overwritten: %v", v)
+	}
+
+	for _, c := range []struct {
+		input    string
+		expected string
+	}{
+		{"/mnt/usb/foo.txt", "/mnt/usb"},
+		{"/mnt/usb", "/mnt/usb"},
+		{"/mnt/usbdisk", "/mnt"},
+		{"/mnt", "/mnt"},
+		{"/home/foo/bar", "/home/foo"},
+		{"/home/bar", "/"},
+		{"/etc", "/"},
+	} {
+		in := pathsFromSlash(c.input)[0]
+		e := pathsFromSlash(c.expected)[0]
+		p, _, ok := tr.LongestPrefix(in)
+		if !ok || p != e {
+			t.Errorf("Expected %s for %s but actually %s (%v)", e, in, p, ok)
+		}
+	}
+
+	if !tr.Delete(ps[0]) {
+		t.Errorf("Root should be deleted")
+	}
+	if tr.Delete(ps[0]) {
+		t.Errorf("Root should not be deleted twice")
+	}
+	if tr.Delete(pathsFromSlash("/not/inserted")[0]) {
+		t.Errorf("Not inserted path should not be deleted")
+	}
+	if tr.Len() != 3 {
+		t.Errorf("Expected 3 paths but actually %d", tr.Len())
+	}
+	if _, _, ok := tr.LongestPrefix(pathsFromSlash("/etc")[0]); ok {
+		t.Errorf("No prefix should be found after deleting root")
+	}
+}
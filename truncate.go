@@ -0,0 +1,47 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+)
+
+// truncater is implemented by File which can change the size of the file such as *os.File.
+type truncater interface {
+	Truncate(size int64) error
+}
+
+// Truncate changes the size of the file as os.Truncate().  When the size is larger than the current size, the file is
+// extended with zero bytes.  When the current FS is not the OS filesystem, the file is opened for writing and
+// truncated via Truncate() method of the opened file.  When the file does not have the method, it returns an error
+// which satisfies errors.Is(err, ErrUnsupported).
+//
+// Example:
+//	log, _ := abspath.New("/var/log/myapp.log")
+//	err := log.Truncate(0)
+func (a AbsPath) Truncate(size int64) (err error) {
+	defer annotate(&err, "truncate", a)
+	return audit("truncate", fmt.Sprintf("size=%d", size), func() error {
+		return truncate(a.underlying, size)
+	}, a.underlying)
+}
+
+func truncate(p string, size int64) error {
+	if usesOSFS() {
+		return os.Truncate(p, size)
+	}
+
+	f, err := rawFS().OpenFile(p, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	t, ok := f.(truncater)
+	if !ok {
+		f.Close()
+		return &os.PathError{Op: "truncate", Path: p, Err: ErrUnsupported}
+	}
+	if err := t.Truncate(size); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
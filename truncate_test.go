@@ -0,0 +1,72 @@
+package abspath
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// noTruncateFS hides Truncate() method of files opened for writing
+type noTruncateFS struct {
+	FS
+}
+
+func (f noTruncateFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	file, err := f.FS.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return struct{ File }{file}, nil
+}
+
+func TestTruncate(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello, world"})
+	p := root.Join("a.txt")
+
+	for _, tc := range []struct {
+		size int64
+		want string
+	}{
+		{5, "hello"},
+		{7, "hello\x00\x00"},
+		{0, ""},
+	} {
+		if err := p.Truncate(tc.size); err != nil {
+			t.Fatal(err)
+		}
+		b, err := ioutil.ReadFile(p.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != tc.want {
+			t.Errorf("Content after truncating to %d should be %q but got %q", tc.size, tc.want, b)
+		}
+	}
+
+	if err := root.Join("missing").Truncate(0); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Not-exist error should be returned but got %v", err)
+	}
+}
+
+func TestTruncateOtherFS(t *testing.T) {
+	root := makeTree(t, map[string]string{"a.txt": "hello"})
+	p := root.Join("a.txt")
+
+	prev := SetFS(&recordingFS{FS: OSFS})
+	err := p.Truncate(2)
+	SetFS(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(p.String()); string(b) != "he" {
+		t.Errorf("File should be truncated via opened file but got %q", b)
+	}
+
+	prev = SetFS(noTruncateFS{OSFS})
+	err = p.Truncate(0)
+	SetFS(prev)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Unsupported error should be returned but got %v", err)
+	}
+}
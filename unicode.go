@@ -0,0 +1,55 @@
+package abspath
+
+import (
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizationForm is a Unicode normalization form used by NormalizeUnicode() method.
+type NormalizationForm int
+
+const (
+	// NFC is Unicode normalization form C (canonical decomposition followed by canonical composition).  It is the form
+	// which most user input and most filesystems use.
+	NFC NormalizationForm = iota
+	// NFD is Unicode normalization form D (canonical decomposition).  HFS+ on macOS stores file names in a variant of
+	// this form.
+	NFD
+)
+
+func (f NormalizationForm) form() norm.Form {
+	if f == NFD {
+		return norm.NFD
+	}
+	return norm.NFC
+}
+
+// String returns the name of the normalization form.
+func (f NormalizationForm) String() string {
+	if f == NFD {
+		return "NFD"
+	}
+	return "NFC"
+}
+
+// NormalizeUnicode returns the path normalized in the given Unicode normalization form.  For example, 'é' can be
+// represented as one code point U+00E9 (NFC) or as 'e' followed by a combining acute accent U+0301 (NFD).  macOS
+// returns file names in NFD while user input is usually in NFC so that comparing them as strings fails.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/café")
+//	a.NormalizeUnicode(abspath.NFC) // => "/path/to/café"
+func (a AbsPath) NormalizeUnicode(f NormalizationForm) AbsPath {
+	return AbsPath{f.form().String(a.underlying)}
+}
+
+// EqualNormalized is the same as Equal() but paths are compared after normalizing them in NFC.  So it is
+// insensitive to Unicode normalization forms of paths.
+//
+// Example:
+//	a, _ := abspath.New("/path/to/café")
+//	b, _ := abspath.New("/path/to/café")
+//	a.Equal(b)           // => false
+//	a.EqualNormalized(b) // => true
+func (a AbsPath) EqualNormalized(b AbsPath) bool {
+	return a.NormalizeUnicode(NFC).Equal(b.NormalizeUnicode(NFC))
+}
@@ -0,0 +1,36 @@
+package abspath
+
+import (
+	"testing"
+)
+
+func TestNormalizeUnicode(t *testing.T) {
+	nfd, _ := FromSlash(fixAbsPath("/path/to/café"))
+	nfc, _ := FromSlash(fixAbsPath("/path/to/café"))
+
+	for _, c := range []struct {
+		input    AbsPath
+		form     NormalizationForm
+		expected AbsPath
+	}{
+		{nfd, NFC, nfc},
+		{nfc, NFC, nfc},
+		{nfc, NFD, nfd},
+		{nfd, NFD, nfd},
+	} {
+		if r := c.input.NormalizeUnicode(c.form); r != c.expected {
+			t.Errorf("Expected %q in %s but actually %q", c.expected, c.form, r)
+		}
+	}
+
+	if nfd.Equal(nfc) {
+		t.Errorf("%q and %q should not be equal without normalization", nfd, nfc)
+	}
+	if !nfd.EqualNormalized(nfc) {
+		t.Errorf("%q and %q should be equal after normalization", nfd, nfc)
+	}
+	other, _ := FromSlash(fixAbsPath("/path/to/cafe"))
+	if nfd.EqualNormalized(other) {
+		t.Errorf("%q and %q should not be equal", nfd, other)
+	}
+}
@@ -0,0 +1,59 @@
+package abspath
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ErrUnsupported is an error which indicates that the operation is not supported on the platform.  Errors returned
+// for such operations are UnsupportedError and can be checked with errors.Is().
+//
+// Example:
+//	h, err := abspath.HomeDir()
+//	if errors.Is(err, abspath.ErrUnsupported) {
+//		// e.g. No home directory in a browser with GOOS=js
+//	}
+var ErrUnsupported = errors.New("operation is not supported on this platform")
+
+// UnsupportedError is an error returned when the operation is not available on the platform, for example obtaining
+// the home directory in a browser with GOOS=js.  errors.Is(err, ErrUnsupported) returns true for this error.
+type UnsupportedError struct {
+	// Op is the name of the operation.
+	Op string
+	// Err is the underlying error.  It may be nil.
+	Err error
+}
+
+func (err *UnsupportedError) Error() string {
+	msg := fmt.Sprintf("%s is not supported on %s/%s", err.Op, runtime.GOOS, runtime.GOARCH)
+	if err.Err != nil {
+		msg += ": " + err.Err.Error()
+	}
+	return msg
+}
+
+// Is returns true when the target is ErrUnsupported.
+func (err *UnsupportedError) Is(target error) bool {
+	return target == ErrUnsupported
+}
+
+// Unwrap returns the underlying error.
+func (err *UnsupportedError) Unwrap() error {
+	return err.Err
+}
+
+// getwd is the same as os.Getwd() but falls back to $PWD environment variable on GOOS=js where the working directory
+// may not be available.
+func getwd() (string, error) {
+	cwd, err := os.Getwd()
+	if err == nil || runtime.GOOS != "js" {
+		return cwd, err
+	}
+	if pwd := os.Getenv("PWD"); filepath.IsAbs(pwd) {
+		return filepath.Clean(pwd), nil
+	}
+	return "", &UnsupportedError{"Getwd", err}
+}
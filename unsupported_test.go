@@ -0,0 +1,87 @@
+package abspath
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUnsupportedError(t *testing.T) {
+	cause := errors.New("not implemented")
+	var err error = &UnsupportedError{"Getwd", cause}
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("%v should be ErrUnsupported", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("%v should wrap %v", err, cause)
+	}
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "Getwd is not supported on "+runtime.GOOS) || !strings.HasSuffix(msg, ": not implemented") {
+		t.Errorf("Unexpected error message %q", msg)
+	}
+	if errors.Is(errors.New("other"), ErrUnsupported) {
+		t.Errorf("Other errors should not be ErrUnsupported")
+	}
+}
+
+func TestGetwdSucceeds(t *testing.T) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Skip("Working directory is not available:", err)
+	}
+	have, err := getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have != cwd {
+		t.Errorf("Expected %q but actually %q", cwd, have)
+	}
+	a, err := Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != cwd {
+		t.Errorf("Expected %q but actually %q", cwd, a)
+	}
+}
+
+func TestHomeDirJS(t *testing.T) {
+	if runtime.GOOS != "js" {
+		t.Skip("Environment variable fallback is only used on GOOS=js")
+	}
+
+	for _, env := range []string{"HOME", "USERPROFILE"} {
+		prev, ok := os.LookupEnv(env)
+		defer func(env string) {
+			if ok {
+				os.Setenv(env, prev)
+			} else {
+				os.Unsetenv(env)
+			}
+		}(env)
+		os.Unsetenv(env)
+	}
+
+	if _, err := HomeDir(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ErrUnsupported should be returned without $HOME but actually %v", err)
+	}
+	if _, err := ExpandFrom("~/foo"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ErrUnsupported should be returned without $HOME but actually %v", err)
+	}
+
+	os.Setenv("HOME", "/home/wasm")
+	a, err := ExpandFrom("~/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != "/home/wasm/foo" {
+		t.Errorf("Unexpected expanded path %q", a)
+	}
+
+	// Lexical APIs are always available
+	if b := a.Join("..", "bar"); b.String() != "/home/wasm/bar" {
+		t.Errorf("Unexpected joined path %q", b)
+	}
+}
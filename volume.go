@@ -0,0 +1,95 @@
+package abspath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Volume represents a volume of absolute paths.  On Windows it is a drive letter like 'C:' or a UNC share like
+// '\\server\share'.  On Unix, all paths are on the same volume whose name is empty and whose root is '/'.  Volume values
+// can be compared with Equal() method.
+type Volume struct {
+	name string
+}
+
+// Volume returns the volume of the path.
+//
+// Example:
+//	a, _ := abspath.New(`C:\foo\bar`)
+//	b, _ := abspath.New(`D:\foo\bar`)
+//	a.Volume().Equal(b.Volume()) // => false
+func (a AbsPath) Volume() Volume {
+	return Volume{filepath.VolumeName(a.underlying)}
+}
+
+// DriveVolume creates Volume instance from a drive letter like 'C'.  It returns an error when the letter is not an
+// alphabet or when it is called on other than Windows.
+//
+// Example:
+//	v, err := abspath.DriveVolume('D')
+//	a := v.Root().Join("foo") // => "D:\foo"
+func DriveVolume(letter byte) (Volume, error) {
+	if runtime.GOOS != "windows" {
+		return Volume{}, fmt.Errorf("drive letter is not available on %s", runtime.GOOS)
+	}
+	if !('a' <= letter && letter <= 'z' || 'A' <= letter && letter <= 'Z') {
+		return Volume{}, fmt.Errorf("invalid drive letter %q", letter)
+	}
+	return Volume{strings.ToUpper(string(letter)) + ":"}, nil
+}
+
+// UNCVolume creates Volume instance from a server name and a share name of UNC path like '\\server\share'.  It returns
+// an error when the names are invalid or when it is called on other than Windows.
+//
+// Example:
+//	v, err := abspath.UNCVolume("fileserver", "public")
+//	a := v.Root().Join("foo") // => "\\fileserver\public\foo"
+func UNCVolume(server, share string) (Volume, error) {
+	if runtime.GOOS != "windows" {
+		return Volume{}, fmt.Errorf("UNC path is not available on %s", runtime.GOOS)
+	}
+	for _, s := range []string{server, share} {
+		if s == "" || s == "." || s == ".." || strings.ContainsAny(s, `\/`) {
+			return Volume{}, fmt.Errorf("invalid server or share name %q", s)
+		}
+	}
+	return Volume{`\\` + server + `\` + share}, nil
+}
+
+// Name returns the volume name like 'C:' or '\\server\share'.  It is empty on Unix.  It is the same as VolumeName()
+// method of AbsPath.
+func (v Volume) Name() string {
+	return v.name
+}
+
+// Root returns the root directory of the volume like 'C:\', '\\server\share\' or '/'.
+func (v Volume) Root() AbsPath {
+	return AbsPath{v.name + string(os.PathSeparator)}
+}
+
+// IsDrive returns whether the volume is a drive letter like 'C:'.
+func (v Volume) IsDrive() bool {
+	return len(v.name) == 2 && v.name[1] == ':'
+}
+
+// IsUNC returns whether the volume is a UNC share like '\\server\share'.
+func (v Volume) IsUNC() bool {
+	return len(v.name) > 2 && os.IsPathSeparator(v.name[0]) && os.IsPathSeparator(v.name[1])
+}
+
+// Equal returns whether two volumes are the same.  Volume names are compared in case-insensitive since they are
+// case-insensitive on Windows.
+func (v Volume) Equal(o Volume) bool {
+	return strings.EqualFold(v.name, o.name)
+}
+
+// String returns the volume name.  On Unix it returns '/' since the volume name is empty.
+func (v Volume) String() string {
+	if v.name == "" {
+		return string(os.PathSeparator)
+	}
+	return v.name
+}
@@ -0,0 +1,78 @@
+package abspath
+
+import (
+	"testing"
+)
+
+func TestVolume(t *testing.T) {
+	a, _ := FromSlash(fixAbsPath("/foo/bar"))
+	v := a.Volume()
+	if v.Name() != a.VolumeName() {
+		t.Errorf("Expected %q but actually %q", a.VolumeName(), v.Name())
+	}
+	r, _ := FromSlash(fixAbsPath("/"))
+	if v.Root() != r {
+		t.Errorf("Expected root %s but actually %s", r, v.Root())
+	}
+	b, _ := FromSlash(fixAbsPath("/piyo"))
+	if !v.Equal(b.Volume()) {
+		t.Errorf("%s and %s should be on the same volume", a, b)
+	}
+
+	if !isWindows {
+		if v.String() != "/" || v.IsDrive() || v.IsUNC() {
+			t.Errorf("Unexpected volume on Unix: %q", v)
+		}
+		if _, err := DriveVolume('C'); err == nil {
+			t.Errorf("Drive volume should not be available on Unix")
+		}
+		if _, err := UNCVolume("server", "share"); err == nil {
+			t.Errorf("UNC volume should not be available on Unix")
+		}
+	}
+}
+
+func TestVolumeWindows(t *testing.T) {
+	if !isWindows {
+		t.Skip("Volumes are only available on Windows")
+	}
+
+	d, err := DriveVolume('d')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name() != "D:" || !d.IsDrive() || d.IsUNC() {
+		t.Errorf("Unexpected drive volume %q", d)
+	}
+	if d.Root().String() != `D:\` {
+		t.Errorf("Unexpected root %s", d.Root())
+	}
+	a, _ := New(`d:\foo`)
+	if !a.Volume().Equal(d) {
+		t.Errorf("Volumes should be compared in case-insensitive")
+	}
+	c, _ := New(`C:\foo`)
+	if c.Volume().Equal(d) {
+		t.Errorf("Different drives should not be equal")
+	}
+
+	u, err := UNCVolume("server", "share")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Name() != `\\server\share` || !u.IsUNC() || u.IsDrive() {
+		t.Errorf("Unexpected UNC volume %q", u)
+	}
+	if u.Root().Join("foo").String() != `\\server\share\foo` {
+		t.Errorf("Unexpected path %s", u.Root().Join("foo"))
+	}
+
+	for _, l := range []byte{'1', ':', ' '} {
+		if _, err := DriveVolume(l); err == nil {
+			t.Errorf("Error was expected for %q", l)
+		}
+	}
+	if _, err := UNCVolume("server", `a\b`); err == nil {
+		t.Errorf("Error was expected for invalid share name")
+	}
+}
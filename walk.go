@@ -0,0 +1,321 @@
+package abspath
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WalkOptions is a set of options for WalkWithOptions() method.  All regular expressions are matched against
+// slash-separated paths relative to the root so that the same expressions can be used on all platforms.
+type WalkOptions struct {
+	// Regexp filters entries by their whole relative paths.  When it is set, only entries matching it are passed to
+	// the callback.  Directories are entered even if they do not match.
+	Regexp *regexp.Regexp
+	// ComponentRegexps filters entries by each component of their relative paths.  The i-th regular expression is
+	// matched against the i-th component.  When it is not empty, only entries whose depth is the same as its length
+	// and whose all components match are passed to the callback.  Directories whose component does not match are not
+	// entered.
+	ComponentRegexps []*regexp.Regexp
+	// SkipVCS skips directories of version control systems listed in VCSDirs.
+	SkipVCS bool
+	// SkipHidden skips hidden entries whose names start with '.'.  Hidden directories are not entered.
+	SkipHidden bool
+	// SkipGenerated skips directories of generated or third-party files listed in GeneratedDirs.
+	SkipGenerated bool
+	// GeneratedDirs is a list of names of directories skipped by SkipGenerated.  When it is nil, DefaultGeneratedDirs
+	// is used.
+	GeneratedDirs []string
+	// FollowSymlinks makes walking follow symbolic links to directories.  Entries under a followed link are passed to
+	// the callback with paths through the link.  To break cycles, a link to a directory which is already being walked
+	// (one of its ancestors) is not followed and is passed to the callback with its own file info.
+	FollowSymlinks bool
+	// RateLimiter limits the rate of visiting entries.  Every visited entry is counted including entries filtered out
+	// by the other options.  When it is nil, the rate is not limited.
+	RateLimiter *RateLimiter
+}
+
+// VCSDirs is a list of names of directories used by version control systems.  They are skipped by SkipVCS option of
+// WalkOptions.
+var VCSDirs = []string{".git", ".hg", ".svn", ".bzr", "_darcs", "CVS"}
+
+// DefaultGeneratedDirs is a default list of names of directories for generated or third-party files.  They are
+// skipped by SkipGenerated option of WalkOptions.
+var DefaultGeneratedDirs = []string{"node_modules", "vendor"}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// skip returns whether the entry should be skipped by the built-in skip options.
+func (opts *WalkOptions) skip(info os.FileInfo) bool {
+	name := info.Name()
+	if opts.SkipHidden && strings.HasPrefix(name, ".") {
+		return true
+	}
+	if !info.IsDir() {
+		return false
+	}
+	if opts.SkipVCS && containsName(VCSDirs, name) {
+		return true
+	}
+	if opts.SkipGenerated {
+		dirs := opts.GeneratedDirs
+		if dirs == nil {
+			dirs = DefaultGeneratedDirs
+		}
+		if containsName(dirs, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// accept returns whether the entry should be passed to the callback and whether the directory should be entered.
+func (opts *WalkOptions) accept(rel string) (bool, bool) {
+	report, enter := true, true
+	if len(opts.ComponentRegexps) > 0 {
+		cs := strings.Split(rel, "/")
+		if len(cs) > len(opts.ComponentRegexps) || !opts.ComponentRegexps[len(cs)-1].MatchString(cs[len(cs)-1]) {
+			return false, false
+		}
+		report = len(cs) == len(opts.ComponentRegexps)
+		enter = !report
+	}
+	if opts.Regexp != nil && !opts.Regexp.MatchString(rel) {
+		report = false
+	}
+	return report, enter
+}
+
+// WalkWithOptions is the same as Walk() but entries under the path are filtered with the options.  Entries skipped by
+// the skip options are checked before the regular expressions.  The root itself is always passed to the callback.  Errors for entries which would be entered are passed to the callback even if the
+// entries are filtered out.  When opts is nil, it is the same as Walk().
+//
+// Example:
+//	logs, _ := abspath.New("/var/log/myapp")
+//	err := logs.WalkWithOptions(func(p string, info os.FileInfo, err error) error {
+//		// Only files in date-stamped directories like 2024/05/01/*.log are passed
+//		return err
+//	}, &abspath.WalkOptions{
+//		ComponentRegexps: []*regexp.Regexp{
+//			regexp.MustCompile(`^\d{4}$`),
+//			regexp.MustCompile(`^\d{2}$`),
+//			regexp.MustCompile(`^\d{2}$`),
+//			regexp.MustCompile(`\.log$`),
+//		},
+//	})
+func (a AbsPath) WalkWithOptions(walkFn filepath.WalkFunc, opts *WalkOptions) error {
+	return a.walkTraced(context.Background(), walkFn, opts)
+}
+
+// walkTraced walks the directory tree in a span.  The number of entries passed to the callback is recorded in the span.
+func (a AbsPath) walkTraced(ctx context.Context, walkFn filepath.WalkFunc, opts *WalkOptions) (err error) {
+	_, span := startSpan(ctx, "abspath.Walk", pathAttr("abspath.path", a))
+	var n int64
+	defer func() {
+		span.SetAttributes(int64Attr("abspath.entries", n))
+		span.End(err)
+	}()
+	return a.walkWithOptions(ctx, func(p string, info os.FileInfo, err error) error {
+		n++
+		return walkFn(p, info, err)
+	}, opts)
+}
+
+func (a AbsPath) walkWithOptions(ctx context.Context, walkFn filepath.WalkFunc, opts *WalkOptions) error {
+	if opts == nil {
+		return walk(a.underlying, walkFn)
+	}
+	w := walk
+	if opts.FollowSymlinks {
+		w = walkFollow
+	}
+	return w(a.underlying, opts.RateLimiter.walkFunc(ctx, func(p string, info os.FileInfo, err error) error {
+		if p == a.underlying {
+			return walkFn(p, info, err)
+		}
+		if err == nil && opts.skip(info) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, relErr := filepath.Rel(a.underlying, p)
+		if relErr != nil {
+			return relErr
+		}
+		report, enter := opts.accept(filepath.ToSlash(rel))
+		if report || err != nil && enter {
+			// Errors are not hidden when the entry would be entered
+			return walkFn(p, info, err)
+		}
+		if err == nil && info.IsDir() && !enter {
+			return filepath.SkipDir
+		}
+		return nil
+	}))
+}
+
+// WalkContext is the same as WalkWithOptions() but accepts a context.  Walking is stopped between entries with the
+// error of the context when the context is done.  opts can be nil.
+//
+// Example:
+//	err := root.WalkContext(r.Context(), func(p string, info os.FileInfo, err error) error {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Fprintln(w, p)
+//		return nil
+//	}, &abspath.WalkOptions{SkipVCS: true})
+func (a AbsPath) WalkContext(ctx context.Context, walkFn filepath.WalkFunc, opts *WalkOptions) error {
+	return a.walkTraced(ctx, func(p string, info os.FileInfo, err error) error {
+		if cerr := ctx.Err(); cerr != nil {
+			return cerr
+		}
+		return walkFn(p, info, err)
+	}, opts)
+}
+
+// Entry is an entry found while walking a directory tree.
+type Entry struct {
+	// Path is the path of the entry.
+	Path AbsPath
+	// Info is the file info of the entry obtained by Lstat().  When FollowSymlinks option is set, it is the file info
+	// of the target for followed symbolic links.
+	Info os.FileInfo
+}
+
+// WalkChan walks the directory tree rooted at the path in a separate goroutine and sends found entries to the
+// returned entry channel in the same order as WalkWithOptions().  Since the entry channel is not buffered, walking
+// proceeds only as fast as the consumer receives entries.  When walking finishes, the entry channel is closed.  When
+// walking fails or the context is done, the error is sent to the error channel and walking stops.  The error channel
+// is closed after the entry channel is closed.  opts can be nil.
+//
+// Example:
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	entries, errs := root.WalkChan(ctx, nil)
+//	for e := range entries {
+//		if e.Info.Mode().IsRegular() {
+//			jobs <- e.Path
+//		}
+//	}
+//	if err := <-errs; err != nil {
+//		panic(err)
+//	}
+func (a AbsPath) WalkChan(ctx context.Context, opts *WalkOptions) (<-chan Entry, <-chan error) {
+	entries := make(chan Entry)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(entries)
+		err := a.walkTraced(ctx, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			select {
+			case entries <- Entry{AbsPath{p}, info}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, opts)
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return entries, errs
+}
+
+// fileKey identifies a directory to detect cycles while following symbolic links.  When the platform or the filesystem
+// does not provide file IDs, the path after resolving symbolic links is used instead.
+type fileKey struct {
+	dev  uint64
+	ino  uint64
+	path string
+}
+
+func dirKey(p string, info os.FileInfo) (fileKey, error) {
+	if usesOSFS() {
+		if k, ok := fileID(p, info); ok {
+			return k, nil
+		}
+	}
+	r, err := AbsPath{p}.EvalSymlinks()
+	if err != nil {
+		return fileKey{}, err
+	}
+	return fileKey{path: r.underlying}, nil
+}
+
+// walkFollow is the same as walk() but follows symbolic links to directories.
+func walkFollow(root string, fn filepath.WalkFunc) (err error) {
+	defer observe("walk", time.Now(), &err)
+	f := fsys()
+	info, err := f.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkFollowFS(f, root, info, fn, map[fileKey]struct{}{})
+	}
+	if err == filepath.SkipDir {
+		return nil
+	}
+	return err
+}
+
+func walkFollowFS(f FS, p string, info os.FileInfo, fn filepath.WalkFunc, ancestors map[fileKey]struct{}) error {
+	if !info.IsDir() {
+		return fn(p, info, nil)
+	}
+
+	k, err := dirKey(p, info)
+	if err != nil {
+		return fn(p, info, err)
+	}
+	if _, ok := ancestors[k]; ok {
+		// Cycle was detected. Report the symbolic link itself without entering it
+		l, err := f.Lstat(p)
+		return fn(p, l, err)
+	}
+	ancestors[k] = struct{}{}
+	defer delete(ancestors, k)
+
+	entries, err := f.ReadDir(p)
+	err1 := fn(p, info, err)
+	if err != nil || err1 != nil {
+		return err1
+	}
+
+	for _, e := range entries {
+		child := filepath.Join(p, e.Name())
+		s, err := f.Lstat(child)
+		if err != nil {
+			if err := fn(child, s, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		if s.Mode()&os.ModeSymlink != 0 {
+			// Broken links are passed to the callback as they are
+			if t, err := f.Stat(child); err == nil {
+				s = t
+			}
+		}
+		if err := walkFollowFS(f, child, s, fn, ancestors); err != nil {
+			if !s.IsDir() || err != filepath.SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
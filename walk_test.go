@@ -0,0 +1,292 @@
+package abspath
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func collectWalk(t *testing.T, root AbsPath, opts *WalkOptions) []AbsPath {
+	t.Helper()
+	ps := []AbsPath{}
+	err := root.WalkWithOptions(func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ps = append(ps, AbsPath{p})
+		return nil
+	}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ps
+}
+
+func TestWalkWithOptions(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"2024/05/01/app.log":  "",
+		"2024/05/01/app.txt":  "",
+		"2024/05/02/app.log":  "",
+		"2024/latest/app.log": "",
+		"archive/05/01/a.log": "",
+		"README.md":           "",
+	})
+	join := func(ss ...string) []AbsPath {
+		ps := []AbsPath{}
+		for _, s := range ss {
+			if s == "" {
+				ps = append(ps, root)
+				continue
+			}
+			ps = append(ps, root.Join(filepath.FromSlash(s)))
+		}
+		return ps
+	}
+
+	have := collectWalk(t, root, nil)
+	if len(have) != 15 {
+		t.Errorf("All entries should be visited without options: %v", have)
+	}
+
+	have = collectWalk(t, root, &WalkOptions{
+		ComponentRegexps: []*regexp.Regexp{
+			regexp.MustCompile(`^\d{4}$`),
+			regexp.MustCompile(`^\d{2}$`),
+			regexp.MustCompile(`^\d{2}$`),
+			regexp.MustCompile(`\.log$`),
+		},
+	})
+	assertPathsEqual(t, join("", "2024/05/01/app.log", "2024/05/02/app.log"), have)
+
+	have = collectWalk(t, root, &WalkOptions{
+		Regexp: regexp.MustCompile(`(^|/)05/01/`),
+	})
+	assertPathsEqual(t, join("", "2024/05/01/app.log", "2024/05/01/app.txt", "archive/05/01/a.log"), have)
+
+	have = collectWalk(t, root, &WalkOptions{
+		Regexp: regexp.MustCompile(`\.log$`),
+		ComponentRegexps: []*regexp.Regexp{
+			regexp.MustCompile(`^\d{4}$`),
+			regexp.MustCompile(`.`),
+		},
+	})
+	assertPathsEqual(t, join(""), have)
+
+	have = collectWalk(t, root, &WalkOptions{
+		ComponentRegexps: []*regexp.Regexp{
+			regexp.MustCompile(`^\d{4}$`),
+			regexp.MustCompile(`.`),
+		},
+	})
+	assertPathsEqual(t, join("", "2024/05", "2024/latest"), have)
+}
+
+func TestWalkWithOptionsSkipDir(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"a/b/c.txt": "",
+		"d/e.txt":   "",
+	})
+	ps := []AbsPath{}
+	err := root.WalkWithOptions(func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ps = append(ps, AbsPath{p})
+		if info.IsDir() && info.Name() == "a" {
+			return filepath.SkipDir
+		}
+		return nil
+	}, &WalkOptions{Regexp: regexp.MustCompile(`^[ad]`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertPathsEqual(t, []AbsPath{root, root.Join("a"), root.Join("d"), root.Join("d", "e.txt")}, ps)
+}
+
+func TestWalkChan(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"a/b.txt": "",
+		"c.txt":   "",
+		"d.log":   "",
+	})
+
+	entries, errs := root.WalkChan(context.Background(), &WalkOptions{Regexp: regexp.MustCompile(`\.txt$`)})
+	have := []AbsPath{}
+	for e := range entries {
+		if !e.Info.Mode().IsRegular() && e.Path != root {
+			t.Errorf("Unexpected entry %s (%s)", e.Path, e.Info.Mode())
+		}
+		have = append(have, e.Path)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	assertPathsEqual(t, []AbsPath{root, root.Join("a", "b.txt"), root.Join("c.txt")}, have)
+
+	if _, ok := <-errs; ok {
+		t.Errorf("Error channel should be closed")
+	}
+}
+
+func TestWalkChanCancel(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"a.txt": "",
+		"b.txt": "",
+		"c.txt": "",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	entries, errs := root.WalkChan(ctx, nil)
+	if e := <-entries; e.Path != root {
+		t.Errorf("First entry should be root but %s", e.Path)
+	}
+	cancel()
+
+	// Walking stops soon after the cancellation
+	n := 0
+	for range entries {
+		n++
+	}
+	if n > 3 {
+		t.Errorf("Too many entries were received after cancellation: %d", n)
+	}
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("Cancellation error should be sent but actually %v", err)
+	}
+}
+
+func TestWalkChanError(t *testing.T) {
+	root := makeTree(t, map[string]string{})
+	entries, errs := root.Join("missing").WalkChan(context.Background(), nil)
+	for e := range entries {
+		t.Errorf("No entry should be sent: %s", e.Path)
+	}
+	if err := <-errs; !os.IsNotExist(err) {
+		t.Errorf("Not-exist error should be sent but actually %v", err)
+	}
+}
+
+func TestWalkSkipOptions(t *testing.T) {
+	root := makeTree(t, map[string]string{
+		"main.go":                  "",
+		".env":                     "",
+		".git/HEAD":                "",
+		".hg/store/data":           "",
+		"CVS/Root":                 "",
+		"node_modules/foo/i.js":    "",
+		"vendor/bar/bar.go":        "",
+		"third_party/baz/baz.go":   "",
+		"src/.hidden/a.go":         "",
+		"src/vendor/qux/qux.go":    "",
+		"src/lib.go":               "",
+		"src/node_modules.go/x.go": "",
+	})
+	files := func(opts *WalkOptions) []AbsPath {
+		ps := []AbsPath{}
+		for _, p := range collectWalk(t, root, opts) {
+			if s, err := os.Stat(p.String()); err == nil && s.Mode().IsRegular() {
+				ps = append(ps, p)
+			}
+		}
+		return ps
+	}
+	join := func(ss ...string) []AbsPath {
+		ps := make([]AbsPath, 0, len(ss))
+		for _, s := range ss {
+			ps = append(ps, root.Join(filepath.FromSlash(s)))
+		}
+		return ps
+	}
+
+	have := files(&WalkOptions{SkipVCS: true})
+	assertPathsEqual(t, join(".env", "main.go", "node_modules/foo/i.js", "src/.hidden/a.go", "src/lib.go", "src/node_modules.go/x.go", "src/vendor/qux/qux.go", "third_party/baz/baz.go", "vendor/bar/bar.go"), have)
+
+	have = files(&WalkOptions{SkipHidden: true})
+	assertPathsEqual(t, join("CVS/Root", "main.go", "node_modules/foo/i.js", "src/lib.go", "src/node_modules.go/x.go", "src/vendor/qux/qux.go", "third_party/baz/baz.go", "vendor/bar/bar.go"), have)
+
+	have = files(&WalkOptions{SkipVCS: true, SkipHidden: true, SkipGenerated: true})
+	assertPathsEqual(t, join("main.go", "src/lib.go", "src/node_modules.go/x.go", "third_party/baz/baz.go"), have)
+
+	have = files(&WalkOptions{SkipGenerated: true, GeneratedDirs: []string{"third_party"}, SkipVCS: true, SkipHidden: true})
+	assertPathsEqual(t, join("main.go", "node_modules/foo/i.js", "src/lib.go", "src/node_modules.go/x.go", "src/vendor/qux/qux.go", "vendor/bar/bar.go"), have)
+
+	// Root is never skipped
+	git := root.Join(".git")
+	have = collectWalk(t, git, &WalkOptions{SkipVCS: true, SkipHidden: true})
+	assertPathsEqual(t, []AbsPath{git, git.Join("HEAD")}, have)
+}
+
+func TestWalkFollowSymlinks(t *testing.T) {
+	if isWindows {
+		t.Skip("Symlink requires privilege on Windows")
+	}
+
+	root := makeTree(t, map[string]string{
+		"a/file.txt": "",
+		"b/":         "",
+	})
+	for _, l := range []struct{ link, target string }{
+		{"b/to-a", "../a"},
+		{"a/loop", ".."},
+		{"a/self", "."},
+		{"dangling", "not-exist"},
+		{"to-file", "a/file.txt"},
+	} {
+		if err := os.Symlink(l.target, root.Join(filepath.FromSlash(l.link)).String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	modes := map[string]os.FileMode{}
+	have := []AbsPath{}
+	err := root.WalkWithOptions(func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		have = append(have, AbsPath{p})
+		rel, _ := filepath.Rel(root.String(), p)
+		modes[filepath.ToSlash(rel)] = info.Mode()
+		return nil
+	}, &WalkOptions{FollowSymlinks: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []AbsPath{root}
+	for _, s := range []string{
+		"a",
+		"a/file.txt",
+		"a/loop",
+		"a/self",
+		"b",
+		"b/to-a",
+		"b/to-a/file.txt",
+		"b/to-a/loop",
+		"b/to-a/self",
+		"dangling",
+		"to-file",
+	} {
+		want = append(want, root.Join(filepath.FromSlash(s)))
+	}
+	assertPathsEqual(t, want, have)
+
+	for _, l := range []string{"a/loop", "a/self", "b/to-a/loop", "b/to-a/self", "dangling"} {
+		if modes[l]&os.ModeSymlink == 0 {
+			t.Errorf("%s should be reported as symlink but mode is %s", l, modes[l])
+		}
+	}
+	if !modes["b/to-a"].IsDir() {
+		t.Errorf("Followed link should be reported as directory but mode is %s", modes["b/to-a"])
+	}
+	if !modes["to-file"].IsRegular() {
+		t.Errorf("Link to file should be reported with target info but mode is %s", modes["to-file"])
+	}
+
+	// Without the option, links are not followed
+	have = collectWalk(t, root, &WalkOptions{})
+	if len(have) != 9 {
+		t.Errorf("Links should not be followed: %v", have)
+	}
+}
@@ -0,0 +1,326 @@
+package abspath
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// EventOp is a set of operations on a file notified by Watch() method.
+type EventOp uint32
+
+const (
+	// EventCreate is an operation to create a new file.
+	EventCreate EventOp = 1 << iota
+	// EventWrite is an operation to write to a file.
+	EventWrite
+	// EventRemove is an operation to remove a file.
+	EventRemove
+	// EventRename is an operation to rename a file.
+	EventRename
+	// EventChmod is an operation to change attributes of a file.
+	EventChmod
+)
+
+var eventOpNames = []struct {
+	op   EventOp
+	name string
+}{
+	{EventCreate, "CREATE"},
+	{EventWrite, "WRITE"},
+	{EventRemove, "REMOVE"},
+	{EventRename, "RENAME"},
+	{EventChmod, "CHMOD"},
+}
+
+// Has returns whether the set of operations contains the given operation.
+func (op EventOp) Has(o EventOp) bool {
+	return op&o != 0
+}
+
+// String returns a human readable representation of the operations like "CREATE|WRITE".
+func (op EventOp) String() string {
+	ss := make([]string, 0, len(eventOpNames))
+	for _, n := range eventOpNames {
+		if op.Has(n.op) {
+			ss = append(ss, n.name)
+		}
+	}
+	if len(ss) == 0 {
+		return "NONE"
+	}
+	return strings.Join(ss, "|")
+}
+
+// Event is a notification of a change on a watched path.  When the watcher fails, an event with non-nil Err field is
+// sent.  In the case, Path is the watched path and Op is empty.
+type Event struct {
+	// Path is a path of the changed file.
+	Path AbsPath
+	// Op is a set of operations happened on the file.
+	Op EventOp
+	// Err is an error reported by the watcher.
+	Err error
+}
+
+func eventOpFromFsnotify(o fsnotify.Op) EventOp {
+	var op EventOp
+	for _, m := range []struct {
+		from fsnotify.Op
+		to   EventOp
+	}{
+		{fsnotify.Create, EventCreate},
+		{fsnotify.Write, EventWrite},
+		{fsnotify.Remove, EventRemove},
+		{fsnotify.Rename, EventRename},
+		{fsnotify.Chmod, EventChmod},
+	} {
+		if o.Has(m.from) {
+			op |= m.to
+		}
+	}
+	return op
+}
+
+// WatchOptions is a set of options for WatchWithOptions() method.
+type WatchOptions struct {
+	// Recursive makes the watcher watch all directories under the watched directory.  Directories created after
+	// starting to watch are also watched automatically.  It is ignored when the watched path is not a directory.
+	Recursive bool
+	// Debounce is a duration to wait for subsequent events.  When it is positive, events are not notified until no
+	// event happens for the duration.  Then events happened on the same path are coalesced into one event whose Op is
+	// the union of their operations.  Events are notified in order of their first occurrences.
+	Debounce time.Duration
+	// PollInterval makes the watcher poll the filesystem at the interval instead of using the native notification API.
+	// Changes are detected by comparing modification times, sizes and permissions of files.  It is useful where the
+	// native API is not available or not reliable such as NFS or some containers.  Unlike the native API, which always
+	// watches the OS filesystem, the polling watcher accesses the filesystem set by SetFS().
+	PollInterval time.Duration
+}
+
+// Watch starts watching changes on the path and returns a channel to receive notifications.  It uses the native
+// notification API of the platform (inotify, kqueue or ReadDirectoryChangesW).  When the path is a directory, changes
+// on entries directly in the directory are notified.  When the path is a file, only changes on the file are notified.
+// Since the parent directory is watched for a file, replacing the file by renaming another file onto it (which many
+// editors do on save) is also notified.  Watching stops and the channel is closed when the context is done.
+//
+// Example:
+//	a, _ := abspath.ExpandFrom("~/.config/app/config.json")
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	events, err := a.Watch(ctx)
+//	if err != nil {
+//		panic(err)
+//	}
+//	for e := range events {
+//		fmt.Println(e.Op, e.Path)
+//	}
+func (a AbsPath) Watch(ctx context.Context) (<-chan Event, error) {
+	return a.WatchWithOptions(ctx, nil)
+}
+
+// addWatchRecursive adds the directory and all directories under it to the watcher.  It returns paths of all entries
+// found under the directory.
+func addWatchRecursive(w *fsnotify.Watcher, dir string) ([]AbsPath, error) {
+	var found []AbsPath
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if p != dir && os.IsNotExist(err) {
+				return nil // Removed while walking
+			}
+			return err
+		}
+		if p != dir {
+			found = append(found, AbsPath{p})
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		return w.Add(p)
+	})
+	return found, err
+}
+
+// eventQueue sends events to the channel returned from WatchWithOptions() with debouncing them.
+type eventQueue struct {
+	ctx      context.Context
+	ch       chan Event
+	debounce time.Duration
+	timer    *time.Timer
+	fire     <-chan time.Time
+	pending  map[AbsPath]EventOp
+	order    []AbsPath
+}
+
+func newEventQueue(ctx context.Context, debounce time.Duration) *eventQueue {
+	return &eventQueue{ctx: ctx, ch: make(chan Event), debounce: debounce}
+}
+
+// send sends the event immediately.  It returns false when the context is done.
+func (q *eventQueue) send(e Event) bool {
+	select {
+	case q.ch <- e:
+		return true
+	case <-q.ctx.Done():
+		return false
+	}
+}
+
+// emit sends the event or queues it when debouncing is enabled.  It returns false when the context is done.
+func (q *eventQueue) emit(p AbsPath, op EventOp) bool {
+	if q.debounce <= 0 {
+		return q.send(Event{Path: p, Op: op})
+	}
+	if q.pending == nil {
+		q.pending = map[AbsPath]EventOp{}
+	}
+	if _, ok := q.pending[p]; !ok {
+		q.order = append(q.order, p)
+	}
+	q.pending[p] |= op
+	if q.timer == nil {
+		q.timer = time.NewTimer(q.debounce)
+	} else {
+		if !q.timer.Stop() {
+			select {
+			case <-q.timer.C:
+			default:
+			}
+		}
+		q.timer.Reset(q.debounce)
+	}
+	q.fire = q.timer.C
+	return true
+}
+
+// flush sends all queued events.  It must be called when a value is received from q.fire.  It returns false when the
+// context is done.
+func (q *eventQueue) flush() bool {
+	q.fire = nil
+	for _, p := range q.order {
+		if !q.send(Event{Path: p, Op: q.pending[p]}) {
+			return false
+		}
+	}
+	q.pending = nil
+	q.order = nil
+	return true
+}
+
+// close stops the timer and closes the channel.
+func (q *eventQueue) close() {
+	if q.timer != nil {
+		q.timer.Stop()
+	}
+	close(q.ch)
+}
+
+// WatchWithOptions is the same as Watch() but accepts options.  It can watch a directory recursively and debounce
+// bursts of events.  opts can be nil.
+//
+// Example:
+//	a, _ := abspath.ExpandFrom("src")
+//	events, err := a.WatchWithOptions(ctx, &abspath.WatchOptions{
+//		Recursive: true,
+//		Debounce:  100 * time.Millisecond,
+//	})
+func (a AbsPath) WatchWithOptions(ctx context.Context, opts *WatchOptions) (_ <-chan Event, err error) {
+	defer annotate(&err, "watch", a)
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+
+	// The native notification API watches the OS filesystem so it is not accessed via the current FS
+	stat := os.Stat
+	if opts.PollInterval > 0 {
+		stat = fsys().Stat
+	}
+	s, err := stat(a.underlying)
+	if err != nil {
+		return nil, err
+	}
+	isDir := s.IsDir()
+	recursive := isDir && opts.Recursive
+
+	if opts.PollInterval > 0 {
+		return a.watchPoll(ctx, isDir, recursive, opts)
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if recursive {
+		_, err = addWatchRecursive(w, a.underlying)
+	} else if isDir {
+		err = w.Add(a.underlying)
+	} else {
+		err = w.Add(a.Dir().underlying)
+	}
+	if err != nil {
+		w.Close()
+		return nil, err
+	}
+
+	q := newEventQueue(ctx, opts.Debounce)
+	go func() {
+		defer q.close()
+		defer w.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				p := AbsPath{filepath.Clean(e.Name)}
+				if !isDir && p != a {
+					continue
+				}
+				op := eventOpFromFsnotify(e.Op)
+				if !q.emit(p, op) {
+					return
+				}
+				if !recursive || !op.Has(EventCreate) {
+					continue
+				}
+				if s, err := os.Lstat(p.underlying); err != nil || !s.IsDir() {
+					continue
+				}
+				// Entries may be created in the new directory before starting to watch it
+				found, err := addWatchRecursive(w, p.underlying)
+				if err != nil {
+					if !q.send(Event{Path: p, Err: err}) {
+						return
+					}
+					continue
+				}
+				for _, f := range found {
+					if !q.emit(f, EventCreate) {
+						return
+					}
+				}
+			case <-q.fire:
+				if !q.flush() {
+					return
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				if !q.send(Event{Path: a, Err: err}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return q.ch, nil
+}
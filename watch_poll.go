@@ -0,0 +1,135 @@
+package abspath
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// pollState is a state of file to detect changes by polling.
+type pollState struct {
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+}
+
+// pollSnapshot collects states of the watched files.  When dir is false, only the path itself is collected.  When
+// recursive is false, only entries directly in the directory are collected.
+func pollSnapshot(root AbsPath, dir, recursive bool) (map[AbsPath]pollState, error) {
+	m := map[AbsPath]pollState{}
+	add := func(p string, info os.FileInfo) {
+		m[AbsPath{p}] = pollState{info.Size(), info.ModTime(), info.Mode()}
+	}
+
+	if !dir {
+		s, err := fsys().Stat(root.underlying)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return m, nil
+			}
+			return nil, err
+		}
+		add(root.underlying, s)
+		return m, nil
+	}
+
+	err := walk(root.underlying, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil // Removed while walking
+			}
+			return err
+		}
+		if p == root.underlying {
+			return nil
+		}
+		add(p, info)
+		if info.IsDir() && !recursive {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return m, err
+}
+
+// pollDiff compares two snapshots and returns changed paths and their operations in order of paths.
+func pollDiff(prev, cur map[AbsPath]pollState) ([]AbsPath, map[AbsPath]EventOp) {
+	ops := map[AbsPath]EventOp{}
+	for p, c := range cur {
+		s, ok := prev[p]
+		if !ok {
+			ops[p] = EventCreate
+			continue
+		}
+		var op EventOp
+		if !c.mode.IsDir() && (s.size != c.size || !s.modTime.Equal(c.modTime)) {
+			op |= EventWrite
+		}
+		if s.mode != c.mode {
+			op |= EventChmod
+		}
+		if op != 0 {
+			ops[p] = op
+		}
+	}
+	for p := range prev {
+		if _, ok := cur[p]; !ok {
+			ops[p] = EventRemove
+		}
+	}
+
+	ps := make([]AbsPath, 0, len(ops))
+	for p := range ops {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].underlying < ps[j].underlying
+	})
+	return ps, ops
+}
+
+// watchPoll is an implementation of WatchWithOptions() by polling the filesystem.
+func (a AbsPath) watchPoll(ctx context.Context, dir, recursive bool, opts *WatchOptions) (<-chan Event, error) {
+	prev, err := pollSnapshot(a, dir, recursive)
+	if err != nil {
+		return nil, err
+	}
+
+	q := newEventQueue(ctx, opts.Debounce)
+	go func() {
+		defer q.close()
+
+		t := time.NewTicker(opts.PollInterval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				cur, err := pollSnapshot(a, dir, recursive)
+				if err != nil {
+					if !q.send(Event{Path: a, Err: err}) {
+						return
+					}
+					continue
+				}
+				ps, ops := pollDiff(prev, cur)
+				for _, p := range ps {
+					if !q.emit(p, ops[p]) {
+						return
+					}
+				}
+				prev = cur
+			case <-q.fire:
+				if !q.flush() {
+					return
+				}
+			}
+		}
+	}()
+
+	return q.ch, nil
+}
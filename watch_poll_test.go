@@ -0,0 +1,103 @@
+package abspath
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatchPoll(t *testing.T) {
+	root := makeTree(t, map[string]string{"file": "hello", "sub/": ""})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := root.WatchWithOptions(ctx, &WatchOptions{Recursive: true, PollInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := root.Join("sub", "new-file")
+	if err := ioutil.WriteFile(f.String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ch, f, EventCreate)
+
+	f = root.Join("file")
+	if err := ioutil.WriteFile(f.String(), []byte("hello, world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ch, f, EventWrite)
+
+	if err := os.Remove(f.String()); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ch, f, EventRemove)
+}
+
+func TestWatchPollFile(t *testing.T) {
+	root := makeTree(t, map[string]string{"watched": "", "other": ""})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f := root.Join("watched")
+	ch, err := f.WatchWithOptions(ctx, &WatchOptions{PollInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(root.Join("other").String(), []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(f.String(), []byte("bar"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := <-ch
+	if e.Path != f {
+		t.Errorf("Only events for %s should be notified but got %s", f, e.Path)
+	}
+	if !e.Op.Has(EventWrite) {
+		t.Errorf("Expected write event but actually %v", e.Op)
+	}
+}
+
+func TestPollDiff(t *testing.T) {
+	now := time.Now()
+	a := AbsPath{"/a"}
+	b := AbsPath{"/b"}
+	c := AbsPath{"/c"}
+	d := AbsPath{"/d"}
+	e := AbsPath{"/e"}
+	prev := map[AbsPath]pollState{
+		a: {1, now, 0644},
+		b: {1, now, 0644},
+		c: {1, now, 0644},
+		d: {1, now, 0644},
+	}
+	cur := map[AbsPath]pollState{
+		a: {1, now, 0644},
+		b: {2, now, 0644},
+		c: {1, now, 0600},
+		e: {1, now, 0644},
+	}
+	ps, ops := pollDiff(prev, cur)
+	expected := []struct {
+		path AbsPath
+		op   EventOp
+	}{
+		{b, EventWrite},
+		{c, EventChmod},
+		{d, EventRemove},
+		{e, EventCreate},
+	}
+	if len(ps) != len(expected) {
+		t.Fatalf("Expected %d changes but actually %v", len(expected), ps)
+	}
+	for i, x := range expected {
+		if ps[i] != x.path || ops[ps[i]] != x.op {
+			t.Errorf("Expected %v for %s but actually %v for %s", x.op, x.path, ops[ps[i]], ps[i])
+		}
+	}
+}
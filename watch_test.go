@@ -0,0 +1,192 @@
+package abspath
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func waitEvent(t *testing.T, ch <-chan Event, path AbsPath, op EventOp) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				t.Fatalf("Channel was closed before receiving %v event for %s", op, path)
+			}
+			if e.Err != nil {
+				t.Fatal(e.Err)
+			}
+			if e.Path == path && e.Op.Has(op) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("Timeout while waiting for %v event for %s", op, path)
+		}
+	}
+}
+
+func TestWatchDir(t *testing.T) {
+	root := makeTree(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := root.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := root.Join("new-file")
+	if err := ioutil.WriteFile(f.String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ch, f, EventCreate)
+
+	cancel()
+	for range ch {
+	}
+}
+
+func TestWatchFile(t *testing.T) {
+	root := makeTree(t, map[string]string{"watched": "", "other": ""})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f := root.Join("watched")
+	ch, err := f.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(root.Join("other").String(), []byte("foo"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(f.String(), []byte("bar"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e := <-ch
+	if e.Path != f {
+		t.Errorf("Only events for %s should be notified but got %s", f, e.Path)
+	}
+	if !e.Op.Has(EventWrite) {
+		t.Errorf("Expected write event but actually %v", e.Op)
+	}
+}
+
+func TestWatchNotExist(t *testing.T) {
+	root := makeTree(t, nil)
+	if _, err := root.Join("not-exist").Watch(context.Background()); err == nil {
+		t.Errorf("Not existing path must cause an error")
+	}
+}
+
+func TestEventOpString(t *testing.T) {
+	for _, c := range []struct {
+		op       EventOp
+		expected string
+	}{
+		{EventCreate, "CREATE"},
+		{EventWrite | EventChmod, "WRITE|CHMOD"},
+		{0, "NONE"},
+	} {
+		if s := c.op.String(); s != c.expected {
+			t.Errorf("Expected %s but actually %s", c.expected, s)
+		}
+	}
+}
+
+func TestWatchRecursive(t *testing.T) {
+	root := makeTree(t, map[string]string{"sub/": ""})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := root.WatchWithOptions(ctx, &WatchOptions{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := root.Join("sub", "file")
+	if err := ioutil.WriteFile(f.String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ch, f, EventCreate)
+
+	d := root.Join("new-dir")
+	if err := os.Mkdir(d.String(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ch, d, EventCreate)
+
+	f = d.Join("file")
+	if err := ioutil.WriteFile(f.String(), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, ch, f, EventCreate)
+}
+
+func TestWatchDebounce(t *testing.T) {
+	root := makeTree(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := root.WatchWithOptions(ctx, &WatchOptions{Debounce: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := root.Join("file")
+	for i := 0; i < 5; i++ {
+		if err := ioutil.WriteFile(f.String(), []byte("hello"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case e := <-ch:
+		if e.Err != nil {
+			t.Fatal(e.Err)
+		}
+		if e.Path != f {
+			t.Fatalf("Expected event for %s but actually %s", f, e.Path)
+		}
+		if !e.Op.Has(EventCreate) || !e.Op.Has(EventWrite) {
+			t.Errorf("Expected coalesced CREATE|WRITE event but actually %v", e.Op)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout while waiting for event")
+	}
+
+	select {
+	case e := <-ch:
+		t.Errorf("Events should be coalesced into one but got %v", e)
+	case <-time.After(400 * time.Millisecond):
+	}
+}
+
+// statErrorFS fails to stat any file
+type statErrorFS struct {
+	FS
+}
+
+func (f statErrorFS) Stat(name string) (os.FileInfo, error) {
+	return nil, &os.PathError{Op: "stat", Path: name, Err: ErrUnsupported}
+}
+
+func TestWatchNativeAccessesOSFS(t *testing.T) {
+	root := makeTree(t, nil)
+	prev := SetFS(statErrorFS{OSFS})
+	defer SetFS(prev)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := root.Watch(ctx); err != nil {
+		t.Errorf("Native watcher should access the OS filesystem: %v", err)
+	}
+	if _, err := root.WatchWithOptions(ctx, &WatchOptions{PollInterval: time.Second}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Polling watcher should access the current FS but got %v", err)
+	}
+}
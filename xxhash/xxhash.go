@@ -0,0 +1,31 @@
+// Package xxhash registers xxHash (XXH64) as a hash function of abspath package.  xxHash is a non-cryptographic hash
+// function which is much faster than SHA-2.  It is useful to detect changes of large files where collision resistance
+// against attackers is not required.  Importing this package makes "xxhash" name available in abspath.NewHash() and
+// abspath.AbsPath.HashByName().
+//
+// Example:
+//	import _ "github.com/rhysd/abspath/xxhash"
+//
+//	sum, err := a.HashByName("xxhash")
+//
+// Ref: https://github.com/cespare/xxhash
+package xxhash
+
+import (
+	"hash"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/rhysd/abspath"
+)
+
+// Name is the name of the hash function registered to abspath package.
+const Name = "xxhash"
+
+// New creates a new XXH64 hash function.
+func New() hash.Hash {
+	return xxhash.New()
+}
+
+func init() {
+	abspath.RegisterHash(Name, New)
+}
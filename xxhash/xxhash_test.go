@@ -0,0 +1,33 @@
+package xxhash
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/rhysd/abspath"
+)
+
+func TestRegistered(t *testing.T) {
+	d := t.TempDir()
+	p := filepath.Join(d, "file")
+	if err := ioutil.WriteFile(p, []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a, err := abspath.New(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"xxhash", "XXHash"} {
+		sum, err := a.HashByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// xxh64("hello") with seed 0
+		if s := hex.EncodeToString(sum); s != "26c7827d889f6da3" {
+			t.Errorf("Unexpected digest by %q: %s", name, s)
+		}
+	}
+}
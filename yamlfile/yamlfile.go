@@ -0,0 +1,32 @@
+// Package yamlfile provides functions to read and write YAML files at abspath.AbsPath.  It is separated from abspath
+// package so that abspath does not depend on a YAML library.  Errors on decoding and encoding are returned as
+// *abspath.FileFormatError which annotates the error with the path.
+//
+// Ref: https://github.com/go-yaml/yaml
+package yamlfile
+
+import (
+	"os"
+
+	"github.com/rhysd/abspath"
+	"gopkg.in/yaml.v3"
+)
+
+// Read reads the YAML file at the path and decodes it into v as yaml.Unmarshal() does.
+//
+// Example:
+//	var c Config
+//	a, _ := abspath.ExpandFrom("~/.config/myapp/config.yaml")
+//	err := yamlfile.Read(a, &c)
+func Read(a abspath.AbsPath, v interface{}) error {
+	return a.ReadFormat("YAML", v, yaml.Unmarshal)
+}
+
+// Write encodes v as YAML and writes it to the file at the path.  When the file does not exist, it is created with the
+// permission.  Otherwise the file is truncated.
+//
+// Example:
+//	err := yamlfile.Write(a, &c, 0644)
+func Write(a abspath.AbsPath, v interface{}, perm os.FileMode) error {
+	return a.WriteFormat("YAML", v, yaml.Marshal, perm)
+}